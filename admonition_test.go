@@ -0,0 +1,49 @@
+package md_test
+
+import (
+	"testing"
+
+	md "github.com/pafthang/md"
+)
+
+func TestAdmonition(t *testing.T) {
+	engine := md.New()
+	engine.SetAdmonition(true)
+	engine.SetCodeSyntaxHighlight(false)
+	for _, c := range []struct{ markdown, html, formatted string }{
+		{"> [!NOTE]\n> a\n", "<div class=\"admonition admonition-note\">\n<p class=\"admonition-title\">Note</p>\n<p>a</p>\n</div>\n", ""},
+		{"> [!WARNING] Be careful\n> a\n> b\n", "<div class=\"admonition admonition-warning\">\n<p class=\"admonition-title\">Be careful</p>\n<p>a<br />\nb</p>\n</div>\n", ""},
+		{"> [!tip]\n> a\n", "<div class=\"admonition admonition-tip\">\n<p class=\"admonition-title\">Tip</p>\n<p>a</p>\n</div>\n", "> [!TIP]\n> a\n"},
+		{"> [!CAUTION]\n", "<div class=\"admonition admonition-caution\">\n<p class=\"admonition-title\">Caution</p>\n</div>\n", ""},
+		// 不支持的类型作为普通块引用
+		{"> [!FOO]\n> a\n", "<blockquote>\n<p>[!FOO]<br />\na</p>\n</blockquote>\n", ""},
+		{"> [!NOTE1]\n> a\n", "<blockquote>\n<p>[!NOTE1]<br />\na</p>\n</blockquote>\n", ""},
+		{"> a\n> [!NOTE]\n", "<blockquote>\n<p>a<br />\n[!NOTE]</p>\n</blockquote>\n", ""},
+		// 围栏容器支持任意类型
+		{":::note\na\n:::\n", "<div class=\"admonition admonition-note\">\n<p class=\"admonition-title\">Note</p>\n<p>a</p>\n</div>\n", ""},
+		{":::details Click me\n* a\n* b\n:::\n", "<div class=\"admonition admonition-details\">\n<p class=\"admonition-title\">Click me</p>\n<ul>\n<li>a</li>\n<li>b</li>\n</ul>\n</div>\n", ""},
+		{"::::warning\n:::tip\nx\n:::\n::::\n", "<div class=\"admonition admonition-warning\">\n<p class=\"admonition-title\">Warning</p>\n<div class=\"admonition admonition-tip\">\n<p class=\"admonition-title\">Tip</p>\n<p>x</p>\n</div>\n</div>\n", ""},
+		{":::note\n```\n:::\n```\n:::\n", "<div class=\"admonition admonition-note\">\n<p class=\"admonition-title\">Note</p>\n<pre><code>:::\n</code></pre>\n</div>\n", ""},
+		{":: note\na\n", "<p>:: note<br />\na</p>\n", ""},
+	} {
+		if html := engine.MarkdownStr("", c.markdown); c.html != html {
+			t.Errorf("md2html mismatch for [%q], expected [%q] but got [%q]", c.markdown, c.html, html)
+		}
+		formatted := c.formatted
+		if "" == formatted {
+			formatted = c.markdown
+		}
+		if actual := engine.FormatStr("", c.markdown); formatted != actual {
+			t.Errorf("format mismatch for [%q], expected [%q] but got [%q]", c.markdown, formatted, actual)
+		}
+		if actual := engine.BlockDOM2StdMd(engine.Md2BlockDOM(c.markdown, false)); formatted != actual {
+			t.Errorf("block DOM round trip mismatch for [%q], expected [%q] but got [%q]", c.markdown, formatted, actual)
+		}
+	}
+
+	// 未开启时作为块引用和段落
+	engine = md.New()
+	if html := engine.MarkdownStr("", "> [!NOTE]\n> a\n"); "<blockquote>\n<p>[!NOTE]<br />\na</p>\n</blockquote>\n" != html {
+		t.Errorf("admonition is parsed when disabled [%q]", html)
+	}
+}
@@ -0,0 +1,18 @@
+package ast
+
+// NodeTypeFlag 描述了自定义节点类型的特性。
+type NodeTypeFlag int
+
+const (
+	// NodeTypeFlagBlock 标识块级节点。
+	NodeTypeFlagBlock NodeTypeFlag = 1 << iota
+	// NodeTypeFlagContainer 标识容器块，可以包含其他块级节点。
+	NodeTypeFlagContainer
+	// NodeTypeFlagAcceptLines 标识可以接受文本行的叶子块。
+	NodeTypeFlagAcceptLines
+)
+
+// hasTypeFlag 判断节点 n 的自定义类型是否具有特性 flag，特性由解析选项中注册的自定义块级解析器记录在 TypeFlags 上。
+func (n *Node) hasTypeFlag(flag NodeTypeFlag) bool {
+	return 0 != n.TypeFlags&flag
+}
@@ -45,6 +45,10 @@ type Node struct {
 	LastLineBlank   bool `json:"-"` // 标识最后一行是否是空行
 	LastLineChecked bool `json:"-"` // 标识最后一行是否检查过
 
+	// 自定义节点类型
+
+	TypeFlags NodeTypeFlag `json:"-"` // 节点类型的特性，由解析选项中注册的自定义块级解析器设置
+
 	// 代码
 
 	CodeMarkerLen int `json:",omitempty"` // ` 个数，1 或 2
@@ -86,9 +90,10 @@ type Node struct {
 
 	// 标题
 
-	HeadingLevel        int    `json:",omitempty"` // 1~6
-	HeadingSetext       bool   `json:",omitempty"` // 是否为 Setext
-	HeadingNormalizedID string `json:",omitempty"` // 规范化后的 ID
+	HeadingLevel        int               `json:",omitempty"` // 1~6
+	HeadingSetext       bool              `json:",omitempty"` // 是否为 Setext
+	HeadingNormalizedID string            `json:",omitempty"` // 规范化后的 ID
+	HeadingSlugIDs      map[string]string `json:"-"`          // 按照标题 ID 生成策略名称缓存的 ID
 
 	// 数学公式块
 
@@ -130,6 +135,17 @@ type Node struct {
 
 	CustomBlockFenceOffset int    `json:",omitempty"` // 自定义块标记符起始偏移量
 	CustomBlockInfo        string `json:",omitempty"` // 自定义块信息
+
+	// 提示块
+
+	AdmonitionKind     string `json:",omitempty"` // 提示块类型，比如 note、warning，统一为小写
+	AdmonitionTitle    string `json:",omitempty"` // 提示块标题
+	AdmonitionFenceLen int    `json:",omitempty"` // 围栏容器 :::type title 中 : 的个数，为 0 时表示 GFM 提示 > [!NOTE]
+
+	// 源码位置，仅在解析选项 SourcePos 开启时记录
+
+	SourceStart *Pos `json:",omitempty"` // 在原始输入中的起始位置
+	SourceEnd   *Pos `json:",omitempty"` // 在原始输入中的结束位置（不包含）
 }
 
 // ListData 用于记录列表或列表项节点的附加信息。
@@ -472,12 +488,42 @@ func (n *Node) Text() (ret string) {
 			buf.Write(n.Tokens)
 		case NodeTextMark:
 			buf.WriteString(n.TextMarkTextContent)
+		case NodeWikiLink, NodeWikiLinkEmbed:
+			buf.WriteString(n.WikiLinkText())
+			return WalkSkipChildren
 		}
 		return WalkContinue
 	})
 	return buf.String()
 }
 
+// WikiLinkParts 返回维基链接节点 n 的页面名称、标题和别名。
+func (n *Node) WikiLinkParts() (page, heading, alias string) {
+	for c := n.FirstChild; nil != c; c = c.Next {
+		switch c.Type {
+		case NodeWikiLinkPage:
+			page = c.TokensStr()
+		case NodeWikiLinkHeading:
+			heading = c.TokensStr()
+		case NodeWikiLinkAlias:
+			alias = c.TokensStr()
+		}
+	}
+	return
+}
+
+// WikiLinkText 返回维基链接节点 n 的显示文本，优先使用别名，否则使用 Page#Heading。
+func (n *Node) WikiLinkText() string {
+	page, heading, alias := n.WikiLinkParts()
+	if "" != alias {
+		return alias
+	}
+	if "" != heading {
+		return page + "#" + heading
+	}
+	return page
+}
+
 // TextLen 返回 n 及其文本子节点的累计长度。
 func (n *Node) TextLen() (ret int) {
 	buf := make([]byte, 0, 4096)
@@ -490,6 +536,9 @@ func (n *Node) TextLen() (ret int) {
 			buf = append(buf, n.Tokens...)
 		case NodeTextMark:
 			buf = append(buf, n.TextMarkTextContent...)
+		case NodeWikiLink, NodeWikiLinkEmbed:
+			buf = append(buf, n.WikiLinkText()...)
+			return WalkSkipChildren
 		}
 		return WalkContinue
 	})
@@ -603,6 +652,15 @@ func (n *Node) TokenLen() (ret int) {
 			return WalkContinue
 		}
 		ret += lex.BytesShowLength(n.Tokens)
+		switch n.Type {
+		case NodeWikiLinkHeading: // #
+			ret++
+		case NodeWikiLinkAlias: // | 或者表格中的 \|
+			ret++
+			if n.ParentIs(NodeTableCell) {
+				ret++
+			}
+		}
 		return WalkContinue
 	})
 	return
@@ -781,19 +839,19 @@ func (n *Node) IsBlock() bool {
 	case NodeDocument, NodeParagraph, NodeHeading, NodeThematicBreak, NodeBlockquote, NodeList, NodeListItem, NodeHTMLBlock,
 		NodeCodeBlock, NodeTable, NodeMathBlock, NodeFootnotesDefBlock, NodeFootnotesDef, NodeToC, NodeYamlFrontMatter,
 		NodeBlockQueryEmbed, NodeKramdownBlockIAL, NodeSuperBlock, NodeGitConflict, NodeAudio, NodeVideo, NodeIFrame, NodeWidget,
-		NodeAttributeView, NodeCustomBlock:
+		NodeAttributeView, NodeCustomBlock, NodeAdmonition, NodeDefinitionList, NodeDefinitionTerm, NodeDefinitionDescription:
 		return true
 	}
-	return false
+	return n.hasTypeFlag(NodeTypeFlagBlock)
 }
 
 // IsContainerBlock 判断 n 是否为容器块。
 func (n *Node) IsContainerBlock() bool {
 	switch n.Type {
-	case NodeDocument, NodeBlockquote, NodeList, NodeListItem, NodeFootnotesDefBlock, NodeFootnotesDef, NodeSuperBlock:
+	case NodeDocument, NodeBlockquote, NodeList, NodeListItem, NodeFootnotesDefBlock, NodeFootnotesDef, NodeSuperBlock, NodeAdmonition:
 		return true
 	}
-	return false
+	return n.hasTypeFlag(NodeTypeFlagContainer)
 }
 
 // IsMarker 判断 n 是否为节点标记符。
@@ -818,7 +876,7 @@ func (n *Node) AcceptLines() bool {
 		NodeGitConflict, NodeIFrame, NodeWidget, NodeVideo, NodeAudio, NodeAttributeView, NodeCustomBlock:
 		return true
 	}
-	return false
+	return n.hasTypeFlag(NodeTypeFlagAcceptLines)
 }
 
 // CanContain 判断是否能够包含 NodeType 指定类型的节点。 比如列表节点（块级容器）只能包含列表项节点，
@@ -826,7 +884,8 @@ func (n *Node) AcceptLines() bool {
 func (n *Node) CanContain(nodeType NodeType) bool {
 	switch n.Type {
 	case NodeCodeBlock, NodeHTMLBlock, NodeParagraph, NodeThematicBreak, NodeTable, NodeMathBlock, NodeYamlFrontMatter,
-		NodeGitConflict, NodeIFrame, NodeWidget, NodeVideo, NodeAudio, NodeAttributeView, NodeCustomBlock:
+		NodeGitConflict, NodeIFrame, NodeWidget, NodeVideo, NodeAudio, NodeAttributeView, NodeCustomBlock,
+		NodeDefinitionList, NodeDefinitionTerm, NodeDefinitionDescription:
 		return false
 	case NodeList:
 		return NodeListItem == nodeType
@@ -841,6 +900,10 @@ func (n *Node) CanContain(nodeType NodeType) bool {
 		}
 		return true
 	}
+	if NodeTypeMaxVal < n.Type {
+		// 自定义叶子块不能包含任何块级节点
+		return n.hasTypeFlag(NodeTypeFlagContainer) && NodeListItem != nodeType
+	}
 	return NodeListItem != nodeType
 }
 
@@ -982,6 +1045,16 @@ const (
 	NodeBlockRefText        NodeType = 433 // 内容块引用锚文本
 	NodeBlockRefDynamicText NodeType = 434 // 内容块引用动态锚文本
 
+	// 维基链接（Wiki Link） [[Page#Heading|Alias]]
+
+	NodeWikiLink            NodeType = 435 // 维基链接 [[Page]]
+	NodeWikiLinkEmbed       NodeType = 436 // 维基链接嵌入 ![[Page]]
+	NodeWikiLinkOpenMarker  NodeType = 437 // 开始维基链接标记符 [[ 或者 ![[
+	NodeWikiLinkPage        NodeType = 438 // 维基链接页面名称
+	NodeWikiLinkHeading     NodeType = 439 // 维基链接标题 #Heading
+	NodeWikiLinkAlias       NodeType = 440 // 维基链接别名 |Alias
+	NodeWikiLinkCloseMarker NodeType = 441 // 结束维基链接标记符 ]]
+
 	// ==Mark== 标记语法 https://github.com/pafthang/md/issues/84
 
 	NodeMark             NodeType = 450 // 标记
@@ -1082,5 +1155,15 @@ const (
 
 	NodeCustomBlock NodeType = 560 // 自定义块
 
+	// 提示块 > [!NOTE] 或者 :::type title
+
+	NodeAdmonition NodeType = 565 // 提示块
+
+	// 定义列表 Term\n: Definition
+
+	NodeDefinitionList        NodeType = 570 // 定义列表
+	NodeDefinitionTerm        NodeType = 571 // 定义列表术语
+	NodeDefinitionDescription NodeType = 572 // 定义列表描述
+
 	NodeTypeMaxVal NodeType = 1024 // 节点类型最大值
 )
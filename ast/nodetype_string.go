@@ -96,6 +96,13 @@ func _() {
 	_ = x[NodeBlockRefSpace-432]
 	_ = x[NodeBlockRefText-433]
 	_ = x[NodeBlockRefDynamicText-434]
+	_ = x[NodeWikiLink-435]
+	_ = x[NodeWikiLinkEmbed-436]
+	_ = x[NodeWikiLinkOpenMarker-437]
+	_ = x[NodeWikiLinkPage-438]
+	_ = x[NodeWikiLinkHeading-439]
+	_ = x[NodeWikiLinkAlias-440]
+	_ = x[NodeWikiLinkCloseMarker-441]
 	_ = x[NodeMark-450]
 	_ = x[NodeMark1OpenMarker-451]
 	_ = x[NodeMark1CloseMarker-452]
@@ -142,10 +149,14 @@ func _() {
 	_ = x[NodeFileAnnotationRefText-543]
 	_ = x[NodeAttributeView-550]
 	_ = x[NodeCustomBlock-560]
+	_ = x[NodeAdmonition-565]
+	_ = x[NodeDefinitionList-570]
+	_ = x[NodeDefinitionTerm-571]
+	_ = x[NodeDefinitionDescription-572]
 	_ = x[NodeTypeMaxVal-1024]
 }
 
-const _NodeType_name = "NodeDocumentNodeParagraphNodeHeadingNodeHeadingC8hMarkerNodeThematicBreakNodeBlockquoteNodeBlockquoteMarkerNodeListNodeListItemNodeHTMLBlockNodeInlineHTMLNodeCodeBlockNodeCodeBlockFenceOpenMarkerNodeCodeBlockFenceCloseMarkerNodeCodeBlockFenceInfoMarkerNodeCodeBlockCodeNodeTextNodeEmphasisNodeEmA6kOpenMarkerNodeEmA6kCloseMarkerNodeEmU8eOpenMarkerNodeEmU8eCloseMarkerNodeStrongNodeStrongA6kOpenMarkerNodeStrongA6kCloseMarkerNodeStrongU8eOpenMarkerNodeStrongU8eCloseMarkerNodeCodeSpanNodeCodeSpanOpenMarkerNodeCodeSpanContentNodeCodeSpanCloseMarkerNodeHardBreakNodeSoftBreakNodeLinkNodeImageNodeBangNodeOpenBracketNodeCloseBracketNodeOpenParenNodeCloseParenNodeLinkTextNodeLinkDestNodeLinkTitleNodeLinkSpaceNodeHTMLEntityNodeLinkRefDefBlockNodeLinkRefDefNodeLessNodeGreaterNodeTaskListItemMarkerNodeStrikethroughNodeStrikethrough1OpenMarkerNodeStrikethrough1CloseMarkerNodeStrikethrough2OpenMarkerNodeStrikethrough2CloseMarkerNodeTableNodeTableHeadNodeTableRowNodeTableCellNodeEmojiNodeEmojiUnicodeNodeEmojiImgNodeEmojiAliasNodeMathBlockNodeMathBlockOpenMarkerNodeMathBlockContentNodeMathBlockCloseMarkerNodeInlineMathNodeInlineMathOpenMarkerNodeInlineMathContentNodeInlineMathCloseMarkerNodeBackslashNodeBackslashContentNodeEditorCaretNodeFootnotesDefBlockNodeFootnotesDefNodeFootnotesRefNodeToCNodeHeadingIDNodeYamlFrontMatterNodeYamlFrontMatterOpenMarkerNodeYamlFrontMatterContentNodeYamlFrontMatterCloseMarkerNodeBlockRefNodeBlockRefIDNodeBlockRefSpaceNodeBlockRefTextNodeBlockRefDynamicTextNodeMarkNodeMark1OpenMarkerNodeMark1CloseMarkerNodeMark2OpenMarkerNodeMark2CloseMarkerNodeKramdownBlockIALNodeKramdownSpanIALNodeTagNodeTagOpenMarkerNodeTagCloseMarkerNodeBlockQueryEmbedNodeOpenBraceNodeCloseBraceNodeBlockQueryEmbedScriptNodeSuperBlockNodeSuperBlockOpenMarkerNodeSuperBlockLayoutMarkerNodeSuperBlockCloseMarkerNodeSupNodeSupOpenMarkerNodeSupCloseMarkerNodeSubNodeSubOpenMarkerNodeSubCloseMarkerNodeGitConflictNodeGitConflictOpenMarkerNodeGitConflictContentNodeGitConflictCloseMarkerNodeIFrameNodeAudioNodeVideoNodeKbdNodeKbdOpenMarkerNodeKbdCloseMarkerNodeUnderlineNodeUnderlineOpenMarkerNodeUnderlineCloseMarkerNodeBrNodeTextMarkNodeWidgetNodeFileAnnotationRefNodeFileAnnotationRefIDNodeFileAnnotationRefSpaceNodeFileAnnotationRefTextNodeAttributeViewNodeCustomBlockNodeTypeMaxVal"
+const _NodeType_name = "NodeDocumentNodeParagraphNodeHeadingNodeHeadingC8hMarkerNodeThematicBreakNodeBlockquoteNodeBlockquoteMarkerNodeListNodeListItemNodeHTMLBlockNodeInlineHTMLNodeCodeBlockNodeCodeBlockFenceOpenMarkerNodeCodeBlockFenceCloseMarkerNodeCodeBlockFenceInfoMarkerNodeCodeBlockCodeNodeTextNodeEmphasisNodeEmA6kOpenMarkerNodeEmA6kCloseMarkerNodeEmU8eOpenMarkerNodeEmU8eCloseMarkerNodeStrongNodeStrongA6kOpenMarkerNodeStrongA6kCloseMarkerNodeStrongU8eOpenMarkerNodeStrongU8eCloseMarkerNodeCodeSpanNodeCodeSpanOpenMarkerNodeCodeSpanContentNodeCodeSpanCloseMarkerNodeHardBreakNodeSoftBreakNodeLinkNodeImageNodeBangNodeOpenBracketNodeCloseBracketNodeOpenParenNodeCloseParenNodeLinkTextNodeLinkDestNodeLinkTitleNodeLinkSpaceNodeHTMLEntityNodeLinkRefDefBlockNodeLinkRefDefNodeLessNodeGreaterNodeTaskListItemMarkerNodeStrikethroughNodeStrikethrough1OpenMarkerNodeStrikethrough1CloseMarkerNodeStrikethrough2OpenMarkerNodeStrikethrough2CloseMarkerNodeTableNodeTableHeadNodeTableRowNodeTableCellNodeEmojiNodeEmojiUnicodeNodeEmojiImgNodeEmojiAliasNodeMathBlockNodeMathBlockOpenMarkerNodeMathBlockContentNodeMathBlockCloseMarkerNodeInlineMathNodeInlineMathOpenMarkerNodeInlineMathContentNodeInlineMathCloseMarkerNodeBackslashNodeBackslashContentNodeEditorCaretNodeFootnotesDefBlockNodeFootnotesDefNodeFootnotesRefNodeToCNodeHeadingIDNodeYamlFrontMatterNodeYamlFrontMatterOpenMarkerNodeYamlFrontMatterContentNodeYamlFrontMatterCloseMarkerNodeBlockRefNodeBlockRefIDNodeBlockRefSpaceNodeBlockRefTextNodeBlockRefDynamicTextNodeWikiLinkNodeWikiLinkEmbedNodeWikiLinkOpenMarkerNodeWikiLinkPageNodeWikiLinkHeadingNodeWikiLinkAliasNodeWikiLinkCloseMarkerNodeMarkNodeMark1OpenMarkerNodeMark1CloseMarkerNodeMark2OpenMarkerNodeMark2CloseMarkerNodeKramdownBlockIALNodeKramdownSpanIALNodeTagNodeTagOpenMarkerNodeTagCloseMarkerNodeBlockQueryEmbedNodeOpenBraceNodeCloseBraceNodeBlockQueryEmbedScriptNodeSuperBlockNodeSuperBlockOpenMarkerNodeSuperBlockLayoutMarkerNodeSuperBlockCloseMarkerNodeSupNodeSupOpenMarkerNodeSupCloseMarkerNodeSubNodeSubOpenMarkerNodeSubCloseMarkerNodeGitConflictNodeGitConflictOpenMarkerNodeGitConflictContentNodeGitConflictCloseMarkerNodeIFrameNodeAudioNodeVideoNodeKbdNodeKbdOpenMarkerNodeKbdCloseMarkerNodeUnderlineNodeUnderlineOpenMarkerNodeUnderlineCloseMarkerNodeBrNodeTextMarkNodeWidgetNodeFileAnnotationRefNodeFileAnnotationRefIDNodeFileAnnotationRefSpaceNodeFileAnnotationRefTextNodeAttributeViewNodeCustomBlockNodeAdmonitionNodeDefinitionListNodeDefinitionTermNodeDefinitionDescriptionNodeTypeMaxVal"
 
 var _NodeType_map = map[NodeType]string{
 	0:    _NodeType_name[0:12],
@@ -236,53 +247,64 @@ var _NodeType_map = map[NodeType]string{
 	432:  _NodeType_name[1438:1455],
 	433:  _NodeType_name[1455:1471],
 	434:  _NodeType_name[1471:1494],
-	450:  _NodeType_name[1494:1502],
-	451:  _NodeType_name[1502:1521],
-	452:  _NodeType_name[1521:1541],
-	453:  _NodeType_name[1541:1560],
-	454:  _NodeType_name[1560:1580],
-	455:  _NodeType_name[1580:1600],
-	456:  _NodeType_name[1600:1619],
-	460:  _NodeType_name[1619:1626],
-	461:  _NodeType_name[1626:1643],
-	462:  _NodeType_name[1643:1661],
-	465:  _NodeType_name[1661:1680],
-	466:  _NodeType_name[1680:1693],
-	467:  _NodeType_name[1693:1707],
-	468:  _NodeType_name[1707:1732],
-	475:  _NodeType_name[1732:1746],
-	476:  _NodeType_name[1746:1770],
-	477:  _NodeType_name[1770:1796],
-	478:  _NodeType_name[1796:1821],
-	485:  _NodeType_name[1821:1828],
-	486:  _NodeType_name[1828:1845],
-	487:  _NodeType_name[1845:1863],
-	490:  _NodeType_name[1863:1870],
-	491:  _NodeType_name[1870:1887],
-	492:  _NodeType_name[1887:1905],
-	495:  _NodeType_name[1905:1920],
-	496:  _NodeType_name[1920:1945],
-	497:  _NodeType_name[1945:1967],
-	498:  _NodeType_name[1967:1993],
-	500:  _NodeType_name[1993:2003],
-	505:  _NodeType_name[2003:2012],
-	510:  _NodeType_name[2012:2021],
-	515:  _NodeType_name[2021:2028],
-	516:  _NodeType_name[2028:2045],
-	517:  _NodeType_name[2045:2063],
-	520:  _NodeType_name[2063:2076],
-	521:  _NodeType_name[2076:2099],
-	522:  _NodeType_name[2099:2123],
-	525:  _NodeType_name[2123:2129],
-	530:  _NodeType_name[2129:2141],
-	535:  _NodeType_name[2141:2151],
-	540:  _NodeType_name[2151:2172],
-	541:  _NodeType_name[2172:2195],
-	542:  _NodeType_name[2195:2221],
-	543:  _NodeType_name[2221:2246],
-	550:  _NodeType_name[2246:2263],
-	560:  _NodeType_name[2263:2278],
-	1024: _NodeType_name[2278:2292],
+	435:  _NodeType_name[1494:1506],
+	436:  _NodeType_name[1506:1523],
+	437:  _NodeType_name[1523:1545],
+	438:  _NodeType_name[1545:1561],
+	439:  _NodeType_name[1561:1580],
+	440:  _NodeType_name[1580:1597],
+	441:  _NodeType_name[1597:1620],
+	450:  _NodeType_name[1620:1628],
+	451:  _NodeType_name[1628:1647],
+	452:  _NodeType_name[1647:1667],
+	453:  _NodeType_name[1667:1686],
+	454:  _NodeType_name[1686:1706],
+	455:  _NodeType_name[1706:1726],
+	456:  _NodeType_name[1726:1745],
+	460:  _NodeType_name[1745:1752],
+	461:  _NodeType_name[1752:1769],
+	462:  _NodeType_name[1769:1787],
+	465:  _NodeType_name[1787:1806],
+	466:  _NodeType_name[1806:1819],
+	467:  _NodeType_name[1819:1833],
+	468:  _NodeType_name[1833:1858],
+	475:  _NodeType_name[1858:1872],
+	476:  _NodeType_name[1872:1896],
+	477:  _NodeType_name[1896:1922],
+	478:  _NodeType_name[1922:1947],
+	485:  _NodeType_name[1947:1954],
+	486:  _NodeType_name[1954:1971],
+	487:  _NodeType_name[1971:1989],
+	490:  _NodeType_name[1989:1996],
+	491:  _NodeType_name[1996:2013],
+	492:  _NodeType_name[2013:2031],
+	495:  _NodeType_name[2031:2046],
+	496:  _NodeType_name[2046:2071],
+	497:  _NodeType_name[2071:2093],
+	498:  _NodeType_name[2093:2119],
+	500:  _NodeType_name[2119:2129],
+	505:  _NodeType_name[2129:2138],
+	510:  _NodeType_name[2138:2147],
+	515:  _NodeType_name[2147:2154],
+	516:  _NodeType_name[2154:2171],
+	517:  _NodeType_name[2171:2189],
+	520:  _NodeType_name[2189:2202],
+	521:  _NodeType_name[2202:2225],
+	522:  _NodeType_name[2225:2249],
+	525:  _NodeType_name[2249:2255],
+	530:  _NodeType_name[2255:2267],
+	535:  _NodeType_name[2267:2277],
+	540:  _NodeType_name[2277:2298],
+	541:  _NodeType_name[2298:2321],
+	542:  _NodeType_name[2321:2347],
+	543:  _NodeType_name[2347:2372],
+	550:  _NodeType_name[2372:2389],
+	560:  _NodeType_name[2389:2404],
+	565:  _NodeType_name[2404:2418],
+	570:  _NodeType_name[2418:2436],
+	571:  _NodeType_name[2436:2454],
+	572:  _NodeType_name[2454:2479],
+	1024: _NodeType_name[2479:2493],
 }
 
 func (i NodeType) String() string {
@@ -0,0 +1,26 @@
+package ast
+
+import "strconv"
+
+// Pos 描述了节点在原始输入中的一个位置。
+type Pos struct {
+	Line   int // 行号，从 1 开始
+	Column int // 列号，从 1 开始，按字节计算
+	Offset int // 字节偏移量，从 0 开始
+}
+
+// Advance 返回同一行上向后移动 n 个字节后的位置。
+func (p *Pos) Advance(n int) *Pos {
+	if nil == p {
+		return nil
+	}
+	return &Pos{Line: p.Line, Column: p.Column + n, Offset: p.Offset + n}
+}
+
+// String 返回 line:column 形式的位置字符串。
+func (p *Pos) String() string {
+	if nil == p {
+		return "-"
+	}
+	return strconv.Itoa(p.Line) + ":" + strconv.Itoa(p.Column)
+}
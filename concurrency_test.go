@@ -0,0 +1,148 @@
+package md_test
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	md "github.com/pafthang/md"
+	"github.com/pafthang/md/render"
+)
+
+// concurrencyMarkdown 覆盖了 Emoji、术语修正、标题 ID、目录、脚注、代码高亮和表格等依赖共享状态的特性。
+const concurrencyMarkdown = `[toc]
+
+# Title :smile: {#title}
+
+## Sub Title
+
+Use github and javascript :custom: :heart: with footnote[^1].
+
+* [x] task
+* item
+
+| a | b |
+|---|---|
+| 1 | 2 |
+
+` + "```go\nfunc main() {}\n```" + `
+
+[^1]: footnote text
+`
+
+func newConcurrencyEngine() *md.MD {
+	engine := md.New()
+	engine.SetToC(true)
+	engine.SetAutoSpace(true)
+	engine.SetFixTermTypo(true)
+	engine.SetHeadingAnchor(true)
+	engine.SetHeadingIDSlugger(render.SluggerGitHub)
+	return engine
+}
+
+// TestConcurrentMarkdown 验证配置完成的引擎可以被多个 goroutine 同时使用，需要使用 go test -race 运行。
+func TestConcurrentMarkdown(t *testing.T) {
+	engine := newConcurrencyEngine()
+	engine.PutEmojis(map[string]string{"custom": "custom.png"})
+	expected := engine.MarkdownStr("", concurrencyMarkdown)
+	expectedFormat := engine.FormatStr("", concurrencyMarkdown)
+	expectedJSON := engine.RenderJSON(concurrencyMarkdown)
+	expectedH2M, _ := engine.HTML2Markdown(expected)
+	expectedLaTeX := string(engine.Markdown2LaTeX("", []byte(concurrencyMarkdown)))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 8; j++ {
+				if html := engine.MarkdownStr("", concurrencyMarkdown); expected != html {
+					t.Errorf("html mismatch, expected [%s] but got [%s]", expected, html)
+				}
+				if formatted := engine.FormatStr("", concurrencyMarkdown); expectedFormat != formatted {
+					t.Errorf("formatted mismatch, expected [%s] but got [%s]", expectedFormat, formatted)
+				}
+				if json := engine.RenderJSON(concurrencyMarkdown); expectedJSON != json {
+					t.Errorf("json mismatch, expected [%s] but got [%s]", expectedJSON, json)
+				}
+				if markdown, _ := engine.HTML2Markdown(expected); expectedH2M != markdown {
+					t.Errorf("html2markdown mismatch, expected [%s] but got [%s]", expectedH2M, markdown)
+				}
+				if latex := string(engine.Markdown2LaTeX("", []byte(concurrencyMarkdown))); expectedLaTeX != latex {
+					t.Errorf("latex mismatch, expected [%s] but got [%s]", expectedLaTeX, latex)
+				}
+				if _, err := engine.MarkdownE("", []byte(concurrencyMarkdown)); nil != err {
+					t.Errorf("unexpected error %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// TestConcurrentEngines 验证每个引擎修改自己的 Emoji 和术语字典时不会影响正在渲染的其他引擎。
+func TestConcurrentEngines(t *testing.T) {
+	shared := newConcurrencyEngine()
+	expected := shared.MarkdownStr("", concurrencyMarkdown)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 8; j++ {
+				if html := shared.MarkdownStr("", concurrencyMarkdown); expected != html {
+					t.Errorf("html mismatch, expected [%s] but got [%s]", expected, html)
+				}
+			}
+		}()
+
+		tenant := strconv.Itoa(i)
+		go func() {
+			defer wg.Done()
+			engine := newConcurrencyEngine()
+			for j := 0; j < 8; j++ {
+				engine.PutEmojis(map[string]string{"custom": "tenant" + tenant + ".png"})
+				engine.PutTerms(map[string]string{"task": "TASK"})
+				html := engine.MarkdownStr("", concurrencyMarkdown)
+				if !strings.Contains(html, "tenant"+tenant+".png") || !strings.Contains(html, " TASK</li>") {
+					t.Errorf("tenant %s dictionaries not applied [%s]", tenant, html)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if html := md.New().MarkdownStr("", ":custom:"); strings.Contains(html, "tenant") {
+		t.Fatalf("global emoji dictionary modified [%s]", html)
+	}
+	if _, ok := md.New().GetTerms()["task"]; ok {
+		t.Fatalf("default terms modified")
+	}
+}
+
+// TestConcurrentProtyle 验证 Protyle 引擎的 Markdown 和块 DOM 互转可以被多个 goroutine 同时使用。
+func TestConcurrentProtyle(t *testing.T) {
+	engine := newProtyleEngine()
+	expected := engine.Md2BlockDOM(concurrencyMarkdown, false)
+	expectedMd := engine.BlockDOM2Md(expected)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 8; j++ {
+				// 块 DOM 中的节点 ID 每次都会重新生成，所以只比较转换回的 kramdown
+				if kramdown := engine.BlockDOM2Md(engine.Md2BlockDOM(concurrencyMarkdown, false)); !strings.Contains(kramdown, "footnote text") {
+					t.Errorf("unexpected kramdown [%s]", kramdown)
+				}
+				if kramdown := engine.BlockDOM2Md(expected); expectedMd != kramdown {
+					t.Errorf("kramdown mismatch, expected [%s] but got [%s]", expectedMd, kramdown)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,70 @@
+package md_test
+
+import (
+	"strconv"
+	"testing"
+)
+
+// specKnownFailures 记录了目前渲染结果和规范不一致的示例编号，修复后需要从这里移除。
+var specKnownFailures = map[string][]int{
+	specGFM: {
+		22,         // 三个波浪线不应该解析为删除线
+		30, 34, 36, // 扩展自动链接不支持 ftp:// 以及域名后直接跟 # 和 ? 的情况
+	},
+}
+
+func specKnownFailure(spec string, example int) bool {
+	for _, num := range specKnownFailures[spec] {
+		if num == example {
+			return true
+		}
+	}
+	return false
+}
+
+// TestSpecConformance 使用 CommonMark 规范示例和整理的 GFM 扩展示例检查 MD.Markdown 的渲染结果，按照章节分组报告通过情况。
+//
+// 已知不一致的示例会被跳过，这些示例通过时测试会失败以提醒更新 specKnownFailures。
+func TestSpecConformance(t *testing.T) {
+	for _, spec := range []string{specCommonMark, specGFM} {
+		spec := spec
+		t.Run(spec, func(t *testing.T) {
+			engine := newSpecEngine(spec)
+			var sections []string
+			sectionExamples := map[string][]*specExample{}
+			for _, example := range loadSpec(t, spec) {
+				if _, ok := sectionExamples[example.Section]; !ok {
+					sections = append(sections, example.Section)
+				}
+				sectionExamples[example.Section] = append(sectionExamples[example.Section], example)
+			}
+
+			passed, total := 0, 0
+			for _, section := range sections {
+				examples := sectionExamples[section]
+				sectionPassed := 0
+				t.Run(section, func(t *testing.T) {
+					for _, example := range examples {
+						html := string(engine.Markdown("", []byte(example.Markdown)))
+						ok := normalizeSpecHTML(html) == normalizeSpecHTML(example.HTML)
+						if ok {
+							sectionPassed++
+						}
+
+						known := specKnownFailure(spec, example.Example)
+						switch {
+						case ok && known:
+							t.Errorf("example [%d] passed, remove it from known failures", example.Example)
+						case !ok && !known:
+							t.Errorf("example [%d]\nmarkdown [%q]\nexpected [%q]\nactual   [%q]", example.Example, example.Markdown, example.HTML, html)
+						}
+					}
+				})
+				t.Logf("%-40s %3d/%-3d", section, sectionPassed, len(examples))
+				passed += sectionPassed
+				total += len(examples)
+			}
+			t.Logf("%s passed %d/%d (%s%%)", spec, passed, total, strconv.FormatFloat(float64(passed)*100/float64(total), 'f', 1, 64))
+		})
+	}
+}
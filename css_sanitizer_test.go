@@ -0,0 +1,102 @@
+package md_test
+
+import (
+	"strings"
+	"testing"
+
+	md "github.com/pafthang/md"
+	"github.com/pafthang/md/render"
+)
+
+var sanitizeStyleTests = []struct {
+	style    string
+	expected string
+}{
+	{"color:red; background:url(javascript:alert(1))", "color: red"},
+	{"background:url('javascript:alert(1)')", ""},
+	{`background:url("java\73 cript:alert(1)")`, ""},
+	{`background:url(java\73 cript:alert(1))`, ""},
+	{"background-image:url(https://e.com/a.png)", `background-image: url("https://e.com/a.png")`},
+	{"background-image:url(data:image/svg+xml;base64,PHN2Zz4=)", ""},
+	{"background-image:url(data:image/png;base64,iVBORw0=)", `background-image: url("data:image/png;base64,iVBORw0=")`},
+	{"width:expression(alert(1))", ""},
+	{`width:ex\70 ression(alert(1))`, ""},
+	{`w\69 dth:10px`, "width: 10px"},
+	{"color:rgb(1,2,3); color:red !important", "color: rgb(1,2,3); color: red !important"},
+	{"position:fixed; top:0", "top: 0"},
+	{"position:relative", "position: relative"},
+	{`position:f\69xed`, ""},
+	{"pos/**/ition:fixed", ""},
+	{"@import url(x.css); color:red", "color: red"},
+	{"behavior:url(x.htc); -moz-binding:url(x.xml)", ""},
+	{"color:red</style><script>alert(1)</script>", ""},
+	{`font-family:"a</style>"`, `font-family: "a\3c /style\3e "`},
+	{"color:red;;;  ;margin:0", "color: red; margin: 0"},
+}
+
+func TestSanitizeStyle(t *testing.T) {
+	policy := render.NewCSSPolicy()
+	for i, test := range sanitizeStyleTests {
+		if actual := policy.SanitizeStyle(test.style); test.expected != actual {
+			t.Errorf("test #%d [%s], expected [%s] but got [%s]", i, test.style, test.expected, actual)
+		}
+	}
+}
+
+var sanitizeStylesheetTests = []struct {
+	css      string
+	expected string
+}{
+	{"@import url(evil.css); p { color: red }", "p { color: red }\n"},
+	{"p{color:red}</style><script>alert(1)</script>", "p { color: red }\n"},
+	{"@media screen { p { color:red; position:fixed } } @font-face { src:url(x) }", "@media screen {\np { color: red }\n}\n"},
+	{`a[href^="javascript"]{color:red} p{background:url(javascript:x)}`, "a[href^=\"javascript\"] { color: red }\n"},
+	{`p { font-family: "</style>" }`, "p { font-family: \"\\3c /style\\3e \" }\n"},
+}
+
+func TestSanitizeStylesheet(t *testing.T) {
+	policy := render.NewCSSPolicy()
+	for i, test := range sanitizeStylesheetTests {
+		if actual := policy.SanitizeStylesheet(test.css); test.expected != actual {
+			t.Errorf("test #%d [%s], expected [%q] but got [%q]", i, test.css, test.expected, actual)
+		}
+	}
+
+	// 策略允许 <style> 元素时，其内容使用 CSS 策略清理，并且无法提前闭合
+	policy0 := render.NewTrustedDocsPolicy()
+	policy0.Elements["style"] = nil
+	if actual := policy0.Sanitize("<style>@import url(x.css); p{color:red} a{background:url(javascript:x)}</style>"); "<style>p { color: red }\n</style>" != actual {
+		t.Errorf("unexpected style element [%q]", actual)
+	}
+	if actual := policy0.Sanitize(`<style>p{font-family:"</style><script>alert(1)</script>"}</style>`); strings.Contains(actual, "<script") {
+		t.Errorf("style element breakout [%q]", actual)
+	}
+	if actual := policy0.Sanitize(`<p style="background:url(&quot;javascript:x&quot;);color:red">a</p>`); `<p style="color: red">a</p>` != actual {
+		t.Errorf("unexpected style attribute [%q]", actual)
+	}
+}
+
+func TestSanitizeStyleIAL(t *testing.T) {
+	const markdown = "# h\n{: id=\"h\" style=\"color:red;position:fixed;background:url(javascript:x)\"}\n\n# g\n{: id=\"g\" style=\"position:fixed\"}\n\n# b\n{: id=\"b\" style=\"color:red&quot; onclick=&quot;x()\"}\n"
+	const expected = "<h1 id=\"h\" style=\"color: red\">h</h1>\n<h1 id=\"g\">g</h1>\n<h1 id=\"b\">b</h1>\n"
+	for _, test := range []struct {
+		name  string
+		setup func(engine *md.MD)
+	}{
+		{"sanitize", func(engine *md.MD) { engine.SetSanitize(true) }},
+		{"policy", func(engine *md.MD) { engine.SetSanitizePolicy(render.NewTrustedDocsPolicy()) }},
+	} {
+		engine := md.New()
+		engine.SetKramdownIAL(true)
+		test.setup(engine)
+		if actual := engine.MarkdownStr("", markdown); expected != actual {
+			t.Errorf("%s heading IAL, expected [%q] but got [%q]", test.name, expected, actual)
+		}
+	}
+
+	engine := md.New()
+	engine.SetKramdownIAL(true)
+	if actual := engine.MarkdownStr("", markdown); !strings.Contains(actual, `style="position:fixed"`) {
+		t.Errorf("style is sanitized without sanitize options [%q]", actual)
+	}
+}
@@ -0,0 +1,32 @@
+package md_test
+
+import (
+	"testing"
+
+	md "github.com/pafthang/md"
+)
+
+func TestDefinitionListBlocks(t *testing.T) {
+	engine := md.New()
+	engine.ParseOptions.DefinitionList = true
+	for _, c := range []struct{ input, markdown, html string }{
+		{"<dl><dt>T</dt><dd><p>one</p><p>two</p></dd></dl>", "T\n: one\n\n    two\n", "<dl>\n<dt>T</dt>\n<dd>\n<p>one</p>\n<p>two</p>\n</dd>\n</dl>\n"},
+		{"<dl><dt>T</dt><dd><ul><li>x</li><li>y</li></ul></dd><dd>plain</dd></dl>", "T\n:\n\n    * x\n    * y\n\n: plain\n", "<dl>\n<dt>T</dt>\n<dd>\n<ul>\n<li>x</li>\n<li>y</li>\n</ul>\n</dd>\n<dd>plain</dd>\n</dl>\n"},
+		{"<dl><dt>T</dt><dd>intro<p>more</p></dd></dl>", "T\n: intro\n\n    more\n", "<dl>\n<dt>T</dt>\n<dd>\n<p>intro</p>\n<p>more</p>\n</dd>\n</dl>\n"},
+		{"<dl><dt>T</dt><dd>a<br>b</dd></dl>", "T\n: a\n  b\n", "<dl>\n<dt>T</dt>\n<dd>a<br />\nb</dd>\n</dl>\n"},
+	} {
+		markdown, err := engine.HTML2Markdown(c.input)
+		if nil != err {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if c.markdown != markdown {
+			t.Errorf("html2md mismatch for [%q], expected [%q] but got [%q]", c.input, c.markdown, markdown)
+		}
+		if html := engine.MarkdownStr("", markdown); c.html != html {
+			t.Errorf("md2html mismatch for [%q], expected [%q] but got [%q]", markdown, c.html, html)
+		}
+		if formatted := engine.FormatStr("", markdown); markdown != formatted {
+			t.Errorf("format mismatch for [%q], got [%q]", markdown, formatted)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package md_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	md "github.com/pafthang/md"
+	"github.com/pafthang/md/parse"
+	"github.com/pafthang/md/render"
+)
+
+// unpackDocx 解压 .docx 文件并校验每个部件都是格式正确的 XML、每个 r:id 和 r:embed 都能在部件自己的关系文件中找到。
+func unpackDocx(t *testing.T, docx []byte) map[string]string {
+	zr, err := zip.NewReader(bytes.NewReader(docx), int64(len(docx)))
+	if nil != err {
+		t.Fatalf("open docx failed: %s", err)
+	}
+	parts := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if nil != err {
+			t.Fatalf("open part [%s] failed: %s", f.Name, err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		parts[f.Name] = string(data)
+	}
+
+	relIDs := regexp.MustCompile(`r:(?:id|embed)="([^"]+)"`)
+	for name, data := range parts {
+		if !strings.HasSuffix(name, ".xml") && !strings.HasSuffix(name, ".rels") {
+			continue
+		}
+		decoder := xml.NewDecoder(strings.NewReader(data))
+		for {
+			if _, err := decoder.Token(); io.EOF == err {
+				break
+			} else if nil != err {
+				t.Fatalf("part [%s] is not well-formed: %s", name, err)
+			}
+		}
+		if !strings.HasPrefix(name, "word/") || strings.Contains(name, "_rels") {
+			continue
+		}
+		rels := parts["word/_rels/"+strings.TrimPrefix(name, "word/")+".rels"]
+		for _, m := range relIDs.FindAllStringSubmatch(data, -1) {
+			if !strings.Contains(rels, `Id="`+m[1]+`"`) {
+				t.Fatalf("relationship [%s] used in [%s] is not defined", m[1], name)
+			}
+		}
+	}
+	return parts
+}
+
+func TestDocx(t *testing.T) {
+	engine := md.New()
+	docx, err := engine.Markdown2Docx("", []byte("[a](https://a.com) b[^1]\n\n![shadow](/etc/shadow)\n\n[^1]: see [b](https://b.com)\n"))
+	if nil != err {
+		t.Fatalf("unexpected error %v", err)
+	}
+	parts := unpackDocx(t, docx)
+	if rels := parts["word/_rels/footnotes.xml.rels"]; !strings.Contains(rels, "https://b.com") || strings.Contains(rels, "https://a.com") {
+		t.Fatalf("unexpected footnotes relationships [%s]", rels)
+	}
+	if rels := parts["word/_rels/document.xml.rels"]; strings.Contains(rels, "https://b.com") || !strings.Contains(rels, "https://a.com") {
+		t.Fatalf("unexpected document relationships [%s]", rels)
+	}
+	if !strings.Contains(parts["word/document.xml"], "shadow") {
+		t.Fatalf("expected image alt text")
+	}
+	for name := range parts {
+		if strings.HasPrefix(name, "word/media/") {
+			t.Fatalf("unexpected media [%s] without image loader", name)
+		}
+	}
+}
+
+func TestDocxLocalImageLoader(t *testing.T) {
+	dir := t.TempDir()
+	pngData := &bytes.Buffer{}
+	if err := png.Encode(pngData, image.NewGray(image.Rect(0, 0, 2, 2))); nil != err {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a.png"), pngData.Bytes(), 0644); nil != err {
+		t.Fatal(err)
+	}
+	outside := t.TempDir()
+	if err := os.WriteFile(filepath.Join(outside, "b.png"), pngData.Bytes(), 0644); nil != err {
+		t.Fatal(err)
+	}
+
+	loader := render.NewLocalImageLoader(dir, 1024)
+	if _, err := loader("a.png"); nil != err {
+		t.Fatalf("load image failed: %s", err)
+	}
+	for _, dest := range []string{filepath.Join(outside, "b.png"), "../" + filepath.Base(outside) + "/b.png", "/dev/zero", "https://a.com/a.png"} {
+		if _, err := loader(dest); nil == err {
+			t.Fatalf("expected error loading [%s]", dest)
+		}
+	}
+	if _, err := render.NewLocalImageLoader(dir, 16)("a.png"); nil == err {
+		t.Fatalf("expected size limit error")
+	}
+
+	tree := parse.Parse("", []byte("![a](a.png)[^1]\n\n[^1]: ![a](a.png)\n"), parse.NewOptions())
+	renderer := render.NewDocxRenderer(tree, render.NewOptions())
+	renderer.ImageLoader = loader
+	parts := unpackDocx(t, renderer.Render())
+	if nil != renderer.Err() {
+		t.Fatalf("unexpected error %v", renderer.Err())
+	}
+	if _, ok := parts["word/media/image1.png"]; !ok {
+		t.Fatalf("expected embedded image")
+	}
+	if _, ok := parts["word/media/image2.png"]; ok {
+		t.Fatalf("expected image to be packed once")
+	}
+	if !strings.Contains(parts["word/_rels/footnotes.xml.rels"], "media/image1.png") {
+		t.Fatalf("expected footnote image relationship")
+	}
+}
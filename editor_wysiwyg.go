@@ -167,10 +167,14 @@ func (md *MD) parseHTML(htmlStr string) *html.Node {
 	if nil != err {
 		return nil
 	}
-	if "html" != doc.FirstChild.Data {
+	htmlNode := doc.FirstChild
+	if nil != htmlNode && html.DoctypeNode == htmlNode.Type { // <!DOCTYPE html> 的 Data 也是 html
+		htmlNode = htmlNode.NextSibling
+	}
+	if nil == htmlNode || html.ElementNode != htmlNode.Type || "html" != htmlNode.Data || nil == htmlNode.LastChild {
 		return doc
 	}
-	return doc.FirstChild.LastChild // doc.html.body
+	return htmlNode.LastChild // doc.html.body
 }
 
 func (md *MD) adjustEditorDOM(root *html.Node) {
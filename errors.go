@@ -0,0 +1,168 @@
+package md
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/pafthang/md/parse"
+	"github.com/pafthang/md/render"
+	"github.com/pafthang/md/util"
+)
+
+// 以下错误可以使用 errors.Is 判断 *E 系列方法返回的错误。
+var (
+	// ErrUnknownNode 表示语法树中存在没有渲染函数的节点，这些节点没有输出，输出仍然完整可用。
+	ErrUnknownNode = render.ErrUnknownNode
+	// ErrLimitExceeded 表示超出了 ParseOptions 中设置的资源限制，超出部分已经降级为文本，输出仍然完整可用。
+	ErrLimitExceeded = parse.ErrLimitExceeded
+	// ErrPanic 表示处理过程中发生了 panic，此时没有输出。
+	ErrPanic = errors.New("panic recovered")
+	// ErrStreamToC 表示开启了目录（RenderOptions.ToC）时调用了 MarkdownTo，目录需要完整文档中的标题，无法流式输出，此时没有输出。
+	ErrStreamToC = errors.New("toc is not supported in stream mode")
+)
+
+// PanicError 描述了处理过程中恢复的 panic，可以使用 errors.Is(err, ErrPanic) 判断。
+type PanicError struct {
+	Value interface{} // panic 的参数
+	Stack []byte      // 发生 panic 时的调用栈
+}
+
+func (e *PanicError) Error() string {
+	return ErrPanic.Error() + ": " + fmt.Sprint(e.Value)
+}
+
+func (e *PanicError) Unwrap() error {
+	return ErrPanic
+}
+
+// recoverPanic 将 panic 恢复为 *PanicError 保存到 err，需要直接使用 defer 调用。
+func recoverPanic(err *error) {
+	if e := recover(); nil != e {
+		*err = &PanicError{Value: e, Stack: debug.Stack()}
+	}
+}
+
+// degraded 判断 err 是否只是降级了输出，降级的输出仍然是完整的，可以和 err 一起返回。
+func degraded(err error) bool {
+	return errors.Is(err, ErrLimitExceeded) || errors.Is(err, ErrUnknownNode)
+}
+
+// parseE 解析 markdown，超出资源限制时返回降级后的语法树和 *parse.LimitError。
+func (md *MD) parseE(name string, markdown []byte) (tree *parse.Tree, err error) {
+	return parse.ParseWithContext(context.Background(), name, markdown, md.ParseOptions)
+}
+
+// renderE 使用 renderer 渲染输出，parseErr 为解析时返回的错误，返回解析和渲染时发生的第一个错误。
+func renderE(renderer render.Renderer, parseErr error) (output []byte, err error) {
+	output = renderer.Render()
+	err = parseErr
+	if r, ok := renderer.(interface{ Err() error }); ok && nil == err {
+		err = r.Err()
+	}
+	if nil != err && !degraded(err) {
+		output = nil
+	}
+	return
+}
+
+// MarkdownE 和 Markdown 一样将 markdown 渲染为 html，但是不会 panic，并且会返回处理时发生的第一个错误。
+//
+// 超出解析限制（ErrLimitExceeded）或者存在没有渲染函数的节点（ErrUnknownNode）时 html 为降级后的完整输出，
+// 其他错误（比如 ErrPanic）时 html 为 nil。输出中不会包含任何诊断信息，调用方可以记录 err 后决定是否使用输出。
+func (md *MD) MarkdownE(name string, markdown []byte) (html []byte, err error) {
+	defer recoverPanic(&err)
+	tree, err := md.parseE(name, markdown)
+	if nil != err && !degraded(err) {
+		return
+	}
+
+	renderer := render.NewHtmlRenderer(tree, md.RenderOptions)
+	for nodeType, rendererFunc := range md.Md2HTMLRendererFuncs {
+		renderer.ExtRendererFuncs[nodeType] = rendererFunc
+	}
+	return renderE(renderer, err)
+}
+
+// MarkdownStrE 接受 string 类型的 markdown 后直接调用 MarkdownE 进行处理。
+func (md *MD) MarkdownStrE(name, markdown string) (html string, err error) {
+	htmlBytes, err := md.MarkdownE(name, []byte(markdown))
+	html = util.BytesToStr(htmlBytes)
+	return
+}
+
+// FormatE 和 Format 一样格式化 markdown，错误处理和 MarkdownE 相同。
+func (md *MD) FormatE(name string, markdown []byte) (formatted []byte, err error) {
+	defer recoverPanic(&err)
+	tree, err := md.parseE(name, markdown)
+	if nil != err && !degraded(err) {
+		return
+	}
+	return renderE(render.NewFormatRenderer(tree, md.RenderOptions), err)
+}
+
+// Markdown2DocxE 和 Markdown2Docx 一样将 markdown 渲染为 .docx 文件，错误处理和 MarkdownE 相同。
+func (md *MD) Markdown2DocxE(name string, markdown []byte) (docx []byte, err error) {
+	defer recoverPanic(&err)
+	tree, err := md.parseE(name, markdown)
+	if nil != err && !degraded(err) {
+		return
+	}
+	return renderE(render.NewDocxRenderer(tree, md.RenderOptions), err)
+}
+
+// Markdown2LaTeXE 和 Markdown2LaTeX 一样将 markdown 渲染为 LaTeX 文档，错误处理和 MarkdownE 相同。
+func (md *MD) Markdown2LaTeXE(name string, markdown []byte) (latex []byte, err error) {
+	defer recoverPanic(&err)
+	tree, err := md.parseE(name, markdown)
+	if nil != err && !degraded(err) {
+		return
+	}
+	return renderE(render.NewLaTeXRenderer(tree, md.RenderOptions), err)
+}
+
+// RenderJSONE 和 RenderJSON 一样将 markdown 渲染为 JSON，错误处理和 MarkdownE 相同。
+func (md *MD) RenderJSONE(markdown string) (json string, err error) {
+	defer recoverPanic(&err)
+	tree, err := md.parseE("", []byte(markdown))
+	if nil != err && !degraded(err) {
+		return
+	}
+
+	output, err := renderE(render.NewJSONRenderer(tree, md.RenderOptions), err)
+	json = util.BytesToStr(output)
+	return
+}
+
+// HTML2MarkdownE 和 HTML2Markdown 一样将 HTML 转换为 Markdown，但是不会 panic，错误处理和 MarkdownE 相同。
+func (md *MD) HTML2MarkdownE(htmlStr string) (markdown string, err error) {
+	defer recoverPanic(&err)
+	tree := md.HTML2Tree(htmlStr)
+	if nil == tree {
+		err = errors.New("parse html failed")
+		return
+	}
+
+	renderer := render.NewFormatRenderer(tree, md.RenderOptions)
+	for nodeType, rendererFunc := range md.HTML2MdRendererFuncs {
+		renderer.ExtRendererFuncs[nodeType] = rendererFunc
+	}
+	output, err := renderE(renderer, nil)
+	markdown = util.BytesToStr(output)
+	return
+}
+
+// Md2BlockDOME 和 Md2BlockDOM 一样将 markdown 渲染为 Protyle DOM，但是不会 panic，发生 panic 时 vHTML 为空。
+func (md *MD) Md2BlockDOME(markdown string, reserveEmptyParagraph bool) (vHTML string, err error) {
+	defer recoverPanic(&err)
+	vHTML = md.Md2BlockDOM(markdown, reserveEmptyParagraph)
+	return
+}
+
+// BlockDOM2MdE 和 BlockDOM2Md 一样将 Protyle DOM 转换为 kramdown，但是不会 panic，发生 panic 时 kramdown 为空。
+func (md *MD) BlockDOM2MdE(htmlStr string) (kramdown string, err error) {
+	defer recoverPanic(&err)
+	kramdown = md.BlockDOM2Md(htmlStr)
+	return
+}
@@ -0,0 +1,92 @@
+package md_test
+
+import (
+	"errors"
+	"testing"
+
+	md "github.com/pafthang/md"
+	"github.com/pafthang/md/ast"
+	"github.com/pafthang/md/parse"
+	"github.com/pafthang/md/render"
+)
+
+func TestMarkdownE(t *testing.T) {
+	engine := md.New()
+	html, err := engine.MarkdownStrE("", "**foo**\n")
+	if nil != err {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if expected := "<p><strong>foo</strong></p>\n"; expected != html {
+		t.Fatalf("html mismatch, expected [%q] but got [%q]", expected, html)
+	}
+
+	engine.ParseOptions.MaxDepth = 2
+	html, err = engine.MarkdownStrE("", "> > > foo\n")
+	var limitErr *parse.LimitError
+	if !errors.Is(err, md.ErrLimitExceeded) || !errors.As(err, &limitErr) || parse.LimitMaxDepth != limitErr.Limit {
+		t.Fatalf("expected MaxDepth limit error but got %v", err)
+	}
+	if expected := "<blockquote>\n<blockquote>\n<p>&gt; foo</p>\n</blockquote>\n</blockquote>\n"; expected != html {
+		t.Fatalf("degraded html mismatch, expected [%q] but got [%q]", expected, html)
+	}
+
+	engine = md.New()
+	engine.Md2HTMLRendererFuncs[ast.NodeParagraph] = func(n *ast.Node, entering bool) (string, ast.WalkStatus) {
+		panic("broken renderer")
+	}
+	html, err = engine.MarkdownStrE("", "foo\n")
+	var panicErr *md.PanicError
+	if !errors.Is(err, md.ErrPanic) || !errors.As(err, &panicErr) || "broken renderer" != panicErr.Value {
+		t.Fatalf("expected panic error but got %v", err)
+	}
+	if "" != html {
+		t.Fatalf("expected empty html but got [%q]", html)
+	}
+}
+
+func TestRenderJSONE(t *testing.T) {
+	engine := md.New()
+	json, err := engine.RenderJSONE("foo\n")
+	if nil != err {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if expected := `{"Type":"NodeDocument","Children":[{"Type":"NodeParagraph","Children":[{"Type":"NodeText","Data":"foo"}]}]}`; expected != json {
+		t.Fatalf("json mismatch, expected [%s] but got [%s]", expected, json)
+	}
+}
+
+func TestHTML2MarkdownE(t *testing.T) {
+	engine := md.New()
+	markdown, err := engine.HTML2MarkdownE("<!DOCTYPE html><p><em>foo</em></p>")
+	if nil != err {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if expected := "*foo*\n"; expected != markdown {
+		t.Fatalf("markdown mismatch, expected [%q] but got [%q]", expected, markdown)
+	}
+}
+
+func TestRenderUnknownNode(t *testing.T) {
+	tree := parse.Parse("", []byte("foo\n"), parse.NewOptions())
+	unknown := &ast.Node{Type: ast.NodeTypeMaxVal + 100, Tokens: []byte("secret")}
+	unknown.AppendChild(&ast.Node{Type: ast.NodeText, Tokens: []byte("bar")})
+	tree.Root.FirstChild.AppendChild(unknown)
+
+	renderer := render.NewHtmlRenderer(tree, render.NewOptions())
+	html := string(renderer.Render())
+	if expected := "<p>foobar</p>\n"; expected != html {
+		t.Fatalf("html mismatch, expected [%q] but got [%q]", expected, html)
+	}
+	var unknownErr *render.UnknownNodeError
+	if err := renderer.Err(); !errors.Is(err, md.ErrUnknownNode) || !errors.As(err, &unknownErr) || unknown.Type != unknownErr.Type {
+		t.Fatalf("expected unknown node error but got %v", err)
+	}
+
+	if renderer.Render(); nil == renderer.Err() {
+		t.Fatalf("expected unknown node error on render again")
+	}
+	unknown.Unlink()
+	if renderer.Render(); nil != renderer.Err() {
+		t.Fatalf("unexpected error %v", renderer.Err())
+	}
+}
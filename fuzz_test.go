@@ -0,0 +1,115 @@
+package md_test
+
+import (
+	"fmt"
+	"runtime/debug"
+	"testing"
+	"time"
+
+	md "github.com/pafthang/md"
+	"github.com/pafthang/md/parse"
+	"github.com/pafthang/md/render"
+)
+
+const (
+	fuzzMaxInput  = 64 * 1024   // 超过该长度的输入不参与模糊测试，避免运行时间检查失去意义
+	fuzzTimeLimit = time.Second // 单次处理允许的最长时间，超过时视为存在病态的时间复杂度
+)
+
+// fuzzCheck 运行 fn 并检查耗时，panic 由模糊测试框架报告。
+//
+// 模糊测试框架不会报告死循环，所以在单独的 goroutine 中运行 fn，超时后直接失败，这样输入会被保存到失败语料中。
+func fuzzCheck(t *testing.T, input string, fn func()) {
+	if fuzzMaxInput < len(input) {
+		t.Skip()
+	}
+
+	done := make(chan string, 1)
+	go func() {
+		defer func() {
+			if r := recover(); nil != r {
+				done <- fmt.Sprintf("%v\n%s", r, debug.Stack())
+				return
+			}
+			done <- ""
+		}()
+		fn()
+	}()
+
+	select {
+	case panicked := <-done:
+		if "" != panicked {
+			t.Fatalf("processing %q panicked: %s", input, panicked)
+		}
+	case <-time.After(fuzzTimeLimit):
+		t.Fatalf("processing %d bytes took more than %s: %q", len(input), fuzzTimeLimit, input)
+	}
+}
+
+// addSpecMarkdownSeeds 添加规范示例中的 Markdown 作为种子语料。
+func addSpecMarkdownSeeds(f *testing.F) {
+	for _, spec := range []string{specCommonMark, specGFM} {
+		for _, example := range loadSpec(f, spec) {
+			f.Add(example.Markdown)
+		}
+	}
+}
+
+// addSpecHTMLSeeds 添加规范示例中的 HTML 作为种子语料。
+func addSpecHTMLSeeds(f *testing.F) {
+	for _, spec := range []string{specCommonMark, specGFM} {
+		for _, example := range loadSpec(f, spec) {
+			f.Add(example.HTML)
+		}
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	addSpecMarkdownSeeds(f)
+	f.Fuzz(func(t *testing.T, markdown string) {
+		fuzzCheck(t, markdown, func() {
+			parse.Parse("", []byte(markdown), parse.NewOptions())
+		})
+	})
+}
+
+func FuzzHTML2Markdown(f *testing.F) {
+	addSpecHTMLSeeds(f)
+	engine := md.New()
+	f.Fuzz(func(t *testing.T, html string) {
+		fuzzCheck(t, html, func() {
+			if _, err := engine.HTML2Markdown(html); nil != err {
+				t.Skip()
+			}
+		})
+	})
+}
+
+func FuzzBlockDOM2Md(f *testing.F) {
+	engine := newProtyleEngine()
+	for _, spec := range []string{specCommonMark, specGFM} {
+		for _, example := range loadSpec(f, spec) {
+			f.Add(engine.Md2BlockDOM(example.Markdown, false))
+		}
+	}
+	f.Fuzz(func(t *testing.T, dom string) {
+		fuzzCheck(t, dom, func() {
+			engine.BlockDOM2Md(dom)
+		})
+	})
+}
+
+func FuzzSanitize(f *testing.F) {
+	addSpecHTMLSeeds(f)
+	f.Add(`<a href="javascript:alert(1)" onclick="alert(2)">x</a><img src=x onerror=alert(3)><script>alert(4)</script>`)
+	f.Fuzz(func(t *testing.T, html string) {
+		fuzzCheck(t, html, func() {
+			render.Sanitize(html)
+		})
+	})
+}
+
+// newProtyleEngine 创建和 Protyle 编辑器一致配置的引擎。
+func newProtyleEngine() *md.MD {
+	return md.New(md.PresetProtyle)
+}
@@ -34,6 +34,11 @@ func (md *MD) HTML2Markdown(htmlStr string) (markdown string, err error) {
 
 // HTML2Tree 将 HTML 转换为 AST。
 func (md *MD) HTML2Tree(dom string) (ret *parse.Tree) {
+	if nil != md.RenderOptions.SanitizePolicy {
+		// 先按照过滤策略清理输入，避免不允许的元素和属性以内联 HTML 等形式保留到 Markdown 中
+		dom = md.RenderOptions.SanitizePolicy.Sanitize(dom)
+	}
+
 	htmlRoot := md.parseHTML(dom)
 	if nil == htmlRoot {
 		return
@@ -131,7 +136,7 @@ func (md *MD) genASTByDOM(n *html.Node, tree *parse.Tree) {
 		}
 		tree.Context.Tip.AppendChild(node)
 	case atom.P, atom.Div, atom.Section:
-		if md.parentIs(n, atom.Table) || ast.NodeLink == tree.Context.Tip.Type {
+		if md.parentIs(n, atom.Table) || ast.NodeLink == tree.Context.Tip.Type || ast.NodeDefinitionTerm == tree.Context.Tip.Type {
 			break
 		}
 
@@ -177,6 +182,23 @@ func (md *MD) genASTByDOM(n *html.Node, tree *parse.Tree) {
 	case atom.Hr:
 		node.Type = ast.NodeThematicBreak
 		tree.Context.Tip.AppendChild(node)
+	case atom.Dl:
+		node.Type = ast.NodeDefinitionList
+		tree.Context.Tip.AppendChild(node)
+		tree.Context.Tip = node
+		defer tree.Context.ParentTip()
+	case atom.Dt, atom.Dd:
+		if ast.NodeDefinitionList != tree.Context.Tip.Type {
+			// 不在 <dl> 中的 <dt>、<dd> 按段落处理
+			node.Type = ast.NodeParagraph
+		} else if atom.Dt == n.DataAtom {
+			node.Type = ast.NodeDefinitionTerm
+		} else {
+			node.Type = ast.NodeDefinitionDescription
+		}
+		tree.Context.Tip.AppendChild(node)
+		tree.Context.Tip = node
+		defer tree.Context.ParentTip()
 	case atom.Blockquote:
 		node.Type = ast.NodeBlockquote
 		node.AppendChild(&ast.Node{Type: ast.NodeBlockquoteMarker, Tokens: util.StrToBytes(">")})
@@ -636,6 +658,39 @@ func (md *MD) genASTByDOM(n *html.Node, tree *parse.Tree) {
 		appendSpace(n, tree, md)
 	case atom.Details:
 		tree.Context.Tip.AppendChild(&ast.Node{Type: ast.NodeHTMLBlock, Tokens: []byte("</details>")})
+	case atom.Dd:
+		if ast.NodeDefinitionDescription == node.Type {
+			wrapDescriptionInlines(node)
+		}
+	}
+}
+
+// wrapDescriptionInlines 在定义列表描述 desc 包含块级子节点时将其中连续的行级子节点包裹为段落，比如 <dd>foo<p>bar</p></dd>。
+func wrapDescriptionInlines(desc *ast.Node) {
+	hasBlock := false
+	for c := desc.FirstChild; nil != c; c = c.Next {
+		if c.IsBlock() {
+			hasBlock = true
+			break
+		}
+	}
+	if !hasBlock {
+		return
+	}
+
+	var paragraph *ast.Node
+	for c := desc.FirstChild; nil != c; {
+		next := c.Next
+		if c.IsBlock() {
+			paragraph = nil
+		} else {
+			if nil == paragraph {
+				paragraph = &ast.Node{Type: ast.NodeParagraph}
+				c.InsertBefore(paragraph)
+			}
+			paragraph.AppendChild(c)
+		}
+		c = next
 	}
 }
 
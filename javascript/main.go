@@ -34,29 +34,6 @@ func New(options map[string]map[string]*js.Object) *js.Object {
 }
 
 func BlockDOM2Content(dom string) string {
-	mdEngine := md.New()
-	mdEngine.SetProtyleWYSIWYG(true)
-	mdEngine.SetBlockRef(true)
-	mdEngine.SetFileAnnotationRef(true)
-	mdEngine.SetKramdownIAL(true)
-	mdEngine.SetTag(true)
-	mdEngine.SetSuperBlock(true)
-	mdEngine.SetImgPathAllowSpace(true)
-	mdEngine.SetGitConflict(true)
-	mdEngine.SetMark(true)
-	mdEngine.SetSup(true)
-	mdEngine.SetSub(true)
-	mdEngine.SetInlineMathAllowDigitAfterOpenMarker(true)
-	mdEngine.SetFootnotes(false)
-	mdEngine.SetToC(false)
-	mdEngine.SetIndentCodeBlock(false)
-	mdEngine.SetParagraphBeginningSpace(true)
-	mdEngine.SetAutoSpace(false)
-	mdEngine.SetHeadingID(false)
-	mdEngine.SetSetext(false)
-	mdEngine.SetYamlFrontMatter(false)
-	mdEngine.SetLinkRef(false)
-	mdEngine.SetCodeSyntaxHighlight(false)
-	mdEngine.SetSanitize(true)
+	mdEngine := md.New(md.PresetProtyle, md.WithSanitize(true))
 	return mdEngine.BlockDOM2Content(dom)
 }
@@ -0,0 +1,38 @@
+package md_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pafthang/md/parse"
+	"github.com/pafthang/md/render"
+)
+
+func renderLaTeXBody(markdown string) string {
+	tree := parse.Parse("", []byte(markdown), parse.NewOptions())
+	renderer := render.NewLaTeXRenderer(tree, render.NewOptions())
+	renderer.Standalone = false
+	return string(renderer.Render())
+}
+
+func TestLaTeXEscaping(t *testing.T) {
+	cases := []struct {
+		markdown string
+		latex    string
+	}{
+		{"- a\n- b\n  - c\n", "\\begin{itemize}\n\\item a\n\\item b\n\\begin{itemize}\n\\item c\n\\end{itemize}\n\\end{itemize}\n\n"},
+		{"- a\n\n- b\n", "\\begin{itemize}\n\\item a\n\n\\item b\n\n\\end{itemize}\n\n"},
+		{"```\n\\end{verbatim}\n\\input{/etc/passwd}\n```\n", "\\begin{flushleft}\\ttfamily\n\\textbackslash{}end\\{verbatim\\}\\\\\n\\textbackslash{}input\\{/etc/passwd\\}\n\\end{flushleft}\n\n"},
+		{"[x](#a}b) [y](#a%25b)\n", "\\hyperref[a+7Db]{x} \\hyperref[a+25b]{y}\n\n"},
+		{"a[^1]\n\n[^1]: note\n\n        code\n", "a\\footnote{note\n\n\\begin{flushleft}\\ttfamily\ncode\n\\end{flushleft}\\label{fn:1}}\n\n"},
+	}
+	for _, c := range cases {
+		if latex := renderLaTeXBody(c.markdown); c.latex != latex {
+			t.Errorf("latex mismatch for [%q], expected [%q] but got [%q]", c.markdown, c.latex, latex)
+		}
+	}
+
+	if latex := renderLaTeXBody("```\nfoo\n```\n"); !strings.HasPrefix(latex, "\\begin{verbatim}\nfoo\n\\end{verbatim}") {
+		t.Errorf("expected verbatim code block but got [%q]", latex)
+	}
+}
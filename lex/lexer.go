@@ -8,6 +8,10 @@ type Lexer struct {
 	length int    // 输入的文本字节数组的长度
 	offset int    // 当前读取字节位置
 	width  int    // 最新一个字符的长度（字节数）
+
+	line           int // 最新一行的行号，从 1 开始
+	lineOffset     int // 最新一行在原始输入中的起始字节偏移量
+	originalOffset int // 下一行在原始输入中的起始字节偏移量
 }
 
 // NewLexer 创建一个词法分析器。
@@ -29,6 +33,7 @@ func (l *Lexer) NextLine() (ret []byte) {
 
 	var b, nb byte
 	i := l.offset
+	consumed := 0 // 原始输入中被规范化掉的字节数，\r\n 移除 \r 记为 1，\u0000 扩展为 \uFFFD 记为 -2
 	for ; i < l.length; i += l.width {
 		b = l.input[i]
 		if ItemNewline == b {
@@ -40,6 +45,7 @@ func (l *Lexer) NextLine() (ret []byte) {
 				if ItemNewline == nb { // \r\n
 					l.input = append(l.input[:i], l.input[i+1:]...) // 移除 \r，依靠下一个的 \n 切行
 					l.length--                                      // 重新计算总长
+					consumed++
 				} else { // \rX
 					l.input[i] = ItemNewline // 将 \r 替换为 \n
 				}
@@ -56,6 +62,7 @@ func (l *Lexer) NextLine() (ret []byte) {
 			l.input[i], l.input[i+1], l.input[i+2] = '\xEF', '\xBF', '\xBD'
 			l.length += 2 // 重新计算总长
 			l.width = 3
+			consumed -= 2
 			continue
 		}
 
@@ -67,5 +74,18 @@ func (l *Lexer) NextLine() (ret []byte) {
 	}
 	ret = l.input[l.offset:i]
 	l.offset = i
+	l.line++
+	l.lineOffset = l.originalOffset
+	l.originalOffset += len(ret) + consumed
 	return
 }
+
+// Line 返回最新一次 NextLine 返回的行的行号，从 1 开始。
+func (l *Lexer) Line() int {
+	return l.line
+}
+
+// LineOffset 返回最新一次 NextLine 返回的行在原始输入中的起始字节偏移量。
+func (l *Lexer) LineOffset() int {
+	return l.lineOffset
+}
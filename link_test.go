@@ -0,0 +1,58 @@
+package md_test
+
+import (
+	"strings"
+	"testing"
+
+	md "github.com/pafthang/md"
+	"github.com/pafthang/md/parse"
+	"github.com/pafthang/md/render"
+)
+
+func TestLinkResolver(t *testing.T) {
+	engine := md.New()
+	engine.SetSanitize(true)
+	attrs := [][]string{{"title", `a"b`}}
+	engine.SetLinkResolver(func(kind render.LinkKind, dest string, tree *parse.Tree) (string, [][]string) {
+		if render.LinkKindFootnote == kind {
+			return "javascript:alert(1)", attrs
+		}
+		return "", attrs
+	})
+
+	for i := 0; i < 2; i++ {
+		html := engine.MarkdownStr("", "[a](/a)[^1]\n\n[^1]: b\n")
+		if strings.Contains(html, "javascript:") {
+			t.Fatalf("unsanitized footnote href [%s]", html)
+		}
+		if strings.Contains(html, "&amp;quot;") || !strings.Contains(html, `title="a&quot;b"`) {
+			t.Fatalf("unexpected attribute escaping [%s]", html)
+		}
+	}
+	if `a"b` != attrs[0][1] {
+		t.Fatalf("resolver attributes were modified [%s]", attrs[0][1])
+	}
+}
+
+func TestWikiLinkBlockDOM(t *testing.T) {
+	engine := md.New()
+	engine.SetWikiLink(true)
+	markdown := "a [[Page|Alias]] b [[P#H]] ![[img.png]] [[Page|a*b]]\n"
+	dom := engine.Md2BlockDOM(markdown, false)
+	if kramdown := engine.BlockDOM2Md(dom); !strings.HasPrefix(kramdown, markdown) {
+		t.Fatalf("wiki links are not kept, expected [%q] but got [%q]", markdown, kramdown)
+	}
+	if std := engine.BlockDOM2StdMd(dom); "a [Alias](Page) b [P#H](P#H) ![img.png](img.png) [a\\*b](Page)\n" != std {
+		t.Fatalf("unexpected std markdown [%q]", std)
+	}
+
+	options := parse.NewOptions()
+	options.WikiLink = true
+	tree := parse.Parse("", []byte(markdown), options)
+	renderOptions := render.NewOptions()
+	renderOptions.WikiLinkResolver = func(page string) string { return "docs/" + page + " (1).md" }
+	expected := "a [Alias](<docs/Page (1).md>) b [P#H](<docs/P (1).md#H>) ![img.png](<docs/img.png (1).md>) [a\\*b](<docs/Page (1).md>)\n"
+	if std := string(render.NewProtyleExportMdRenderer(tree, renderOptions).Render()); expected != std {
+		t.Fatalf("exported markdown mismatch, expected [%q] but got [%q]", expected, std)
+	}
+}
@@ -0,0 +1,352 @@
+// Package lint 实现了 Markdown 语法树的检查，比如失效的链接引用、脚注引用、块引用和锚点链接。
+package lint
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pafthang/md/ast"
+	"github.com/pafthang/md/parse"
+	"github.com/pafthang/md/render"
+	"github.com/pafthang/md/util"
+)
+
+// 检查规则 ID。
+const (
+	RuleUndefinedLinkRef  = "undefined-link-ref"  // 链接引用 [foo][bar] 没有对应的链接引用定义
+	RuleUndefinedFootnote = "undefined-footnote"  // 脚注引用 [^foo] 没有对应的脚注定义
+	RuleUndefinedBlockRef = "undefined-block-ref" // 块引用 ((id)) 引用的块不存在
+	RuleUndefinedAnchor   = "undefined-anchor"    // 锚点链接 [foo](#bar) 指向的标题或元素不存在
+)
+
+// Diagnostic 描述了一条检查结果。
+type Diagnostic struct {
+	Rule    string    `json:"rule"`            // 规则 ID
+	Message string    `json:"message"`         // 描述信息
+	Target  string    `json:"target"`          // 失效的目标，比如链接引用标签、块 ID 或者锚点
+	Start   *ast.Pos  `json:"start,omitempty"` // 在原始输入中的起始位置，需要开启解析选项 SourcePos
+	End     *ast.Pos  `json:"end,omitempty"`   // 在原始输入中的结束位置，需要开启解析选项 SourcePos
+	Fixable bool      `json:"fixable"`         // 是否可以通过 Linter.Fix 自动修复
+	Node    *ast.Node `json:"-"`               // 出现问题的节点
+}
+
+// String 返回 line:column: message [rule] 形式的检查结果。
+func (d *Diagnostic) String() string {
+	return d.Start.String() + ": " + d.Message + " [" + d.Rule + "]"
+}
+
+// Resolver 用于判断文档外部的目标是否存在，kind 为目标类型，target 为块 ID 或者锚点（不包含 #）。
+type Resolver func(kind render.LinkKind, target string) bool
+
+// CheckOptions 描述了检查选项。
+type CheckOptions struct {
+	// Resolver 用于解析文档中找不到的块引用和锚点，为 nil 时仅在文档内查找。
+	Resolver Resolver
+	// RenderOptions 用于按照渲染时的规则计算标题 ID，比如 HeadingIDSlugger，为 nil 时使用默认规则。
+	RenderOptions *render.Options
+	// ShortcutLinkRefs 设置是否检查简写形式的链接引用 [foo]，默认仅检查 [foo][bar] 和 [foo][]。
+	ShortcutLinkRefs bool
+}
+
+var (
+	fullLinkRefRegexp     = regexp.MustCompile(`\[([^\[\]\x02]+)\]\[([^\[\]\x02]*)\]`)
+	shortcutLinkRefRegexp = regexp.MustCompile(`\[([^\[\]\x02^]+)\]`)
+	footnotesRefRegexp    = regexp.MustCompile(`\[\^([^\[\]\x02\s]+)\]`)
+	htmlIDRegexp          = regexp.MustCompile(`\s(?:id|name)\s*=\s*["']?([^"'\s>]+)`)
+)
+
+// Check 检查语法树 tree 中失效的链接引用、脚注引用、块引用和锚点链接，返回的检查结果按照在文档中出现的顺序排列。
+//
+// 失效的链接引用和脚注引用在解析时会退化为普通文本，因此按照段落等行级内容中文本节点的方括号进行识别（方括号中可以包含强调等行级元素），
+// 分别需要开启解析选项 LinkRef 和 Footnotes。
+func Check(tree *parse.Tree, options *CheckOptions) (ret []*Diagnostic) {
+	if nil == options {
+		options = &CheckOptions{}
+	}
+
+	c := &checker{tree: tree, options: options}
+	c.collectAnchors()
+	ast.Walk(tree.Root, func(n *ast.Node, entering bool) ast.WalkStatus {
+		if !entering {
+			return ast.WalkContinue
+		}
+
+		switch n.Type {
+		case ast.NodeParagraph, ast.NodeHeading, ast.NodeTableCell, ast.NodeDefinitionTerm, ast.NodeDefinitionDescription:
+			c.checkInlines(n)
+		case ast.NodeLink:
+			if dest := n.ChildByType(ast.NodeLinkDest); nil != dest {
+				c.checkAnchor(n, util.BytesToStr(dest.Tokens))
+			}
+		case ast.NodeBlockRef:
+			if id := n.ChildByType(ast.NodeBlockRefID); nil != id {
+				c.checkBlockRef(n, util.BytesToStr(id.Tokens))
+			}
+		case ast.NodeTextMark:
+			if n.IsTextMarkType("a") {
+				c.checkAnchor(n, n.TextMarkAHref)
+			}
+			if n.IsTextMarkType("block-ref") {
+				c.checkBlockRef(n, n.TextMarkBlockRefID)
+			}
+		case ast.NodeCodeBlock, ast.NodeCodeSpan, ast.NodeMathBlock, ast.NodeInlineMath:
+			return ast.WalkSkipChildren
+		}
+		return ast.WalkContinue
+	})
+	return c.diagnostics
+}
+
+type checker struct {
+	tree        *parse.Tree
+	options     *CheckOptions
+	anchors     map[string]bool // 文档内可以作为锚点的 ID
+	blockIDs    map[string]bool // 文档内的块 ID
+	diagnostics []*Diagnostic
+}
+
+func (c *checker) collectAnchors() {
+	c.anchors = map[string]bool{}
+	c.blockIDs = map[string]bool{}
+
+	renderOptions := c.options.RenderOptions
+	if nil == renderOptions {
+		renderOptions = render.NewOptions()
+	}
+	var headingIDs map[*ast.Node]string
+	if nil != renderOptions.HeadingIDSlugger && nil != renderOptions.HeadingIDSlugger.Slug {
+		headingIDs = render.HeadingIDs(c.tree.Root, renderOptions.HeadingIDSlugger)
+	}
+
+	var defs int
+	ast.Walk(c.tree.Root, func(n *ast.Node, entering bool) ast.WalkStatus {
+		if !entering {
+			return ast.WalkContinue
+		}
+
+		if "" != n.ID {
+			c.blockIDs[n.ID] = true
+			c.anchors[n.ID] = true
+		}
+		if id := n.IALAttr("id"); "" != id {
+			c.blockIDs[id] = true
+			c.anchors[id] = true
+		}
+
+		switch n.Type {
+		case ast.NodeHeading:
+			if nil != headingIDs {
+				c.anchors[headingIDs[n]] = true
+			} else {
+				c.anchors[render.HeadingID(n)] = true
+			}
+		case ast.NodeFootnotesRef:
+			c.anchors["footnotes-ref-"+n.FootnotesRefId] = true
+		case ast.NodeFootnotesDef:
+			defs++
+			c.anchors["footnotes-def-"+strconv.Itoa(defs)] = true
+		case ast.NodeHTMLBlock, ast.NodeInlineHTML:
+			for _, m := range htmlIDRegexp.FindAllSubmatch(n.Tokens, -1) {
+				c.anchors[string(m[1])] = true
+			}
+		}
+		return ast.WalkContinue
+	})
+}
+
+// inlineText 为块节点中行级内容拼接后的文本，用于识别跨越多个行级节点的链接引用和脚注引用，比如 [*foo*][bar]。
+type inlineText struct {
+	match    []byte        // 用于匹配的文本，只有文本节点中的方括号保留为方括号，链接等节点使用 inlineBreak 分隔
+	text     []byte        // 和 match 等长的原始文本，用于提取标签
+	segments []inlineChunk // 每段文本对应的节点
+}
+
+// inlineChunk 记录了 inlineText 中从 start 开始的一段文本来自节点 node。
+type inlineChunk struct {
+	start int
+	node  *ast.Node
+}
+
+const (
+	inlineBracket = 0x01 // 替换非文本节点中的方括号，比如转义字符和行级代码中的方括号
+	inlineBreak   = 0x02 // 分隔链接、脚注引用等节点，链接引用不能跨越这些节点
+)
+
+func (t *inlineText) write(n *ast.Node, tokens []byte, brackets bool) {
+	if 1 > len(tokens) {
+		return
+	}
+	t.segments = append(t.segments, inlineChunk{start: len(t.match), node: n})
+	t.text = append(t.text, tokens...)
+	for _, b := range tokens {
+		if !brackets && ('[' == b || ']' == b) {
+			b = inlineBracket
+		}
+		t.match = append(t.match, b)
+	}
+}
+
+// pos 返回 inlineText 中第 offset 个字节在原始输入中的位置，end 为 true 时返回该字节之后的位置。
+//
+// 只有内容和原文一致的文本节点可以按照字节偏移计算位置，其他节点（比如 HTML 实体和转义字符）使用节点自身的位置。
+func (t *inlineText) pos(offset int, end bool) (n *ast.Node, ret *ast.Pos) {
+	i := sort.Search(len(t.segments), func(i int) bool { return t.segments[i].start > offset }) - 1
+	chunk := t.segments[i]
+	n = chunk.node
+	for p := n; nil != p && nil == ret; p = p.Parent {
+		if end {
+			ret = p.SourceEnd
+		} else {
+			ret = p.SourceStart
+		}
+	}
+	if (ast.NodeText == n.Type || ast.NodeLinkText == n.Type) && nil != n.SourceStart && nil != n.SourceEnd && n.SourceStart.Line == n.SourceEnd.Line &&
+		n.SourceEnd.Offset-n.SourceStart.Offset == len(n.Tokens) {
+		if end {
+			offset++
+		}
+		ret = n.SourceStart.Advance(offset - chunk.start)
+	}
+	return
+}
+
+// checkInlines 检查块节点 block 的行级内容中失效的链接引用和脚注引用。
+func (c *checker) checkInlines(block *ast.Node) {
+	if nil == block.FirstChild || block.FirstChild.IsBlock() {
+		return
+	}
+
+	t := &inlineText{}
+	ast.Walk(block, func(n *ast.Node, entering bool) ast.WalkStatus {
+		if n == block {
+			return ast.WalkContinue
+		}
+
+		switch n.Type {
+		case ast.NodeText, ast.NodeLinkText:
+			if entering {
+				t.write(n, n.Tokens, true)
+			}
+		case ast.NodeLink, ast.NodeImage:
+			// 链接文本中的内容单独识别
+			t.write(n, []byte{inlineBreak}, false)
+		case ast.NodeFootnotesRef, ast.NodeBlockRef, ast.NodeFileAnnotationRef, ast.NodeWikiLink, ast.NodeWikiLinkEmbed, ast.NodeTextMark:
+			if entering {
+				t.write(n, []byte{inlineBreak}, false)
+			}
+			return ast.WalkSkipChildren
+		case ast.NodeBackslash:
+			if entering {
+				t.write(n, []byte{'\\'}, false)
+			}
+		default:
+			if entering && nil == n.FirstChild {
+				t.write(n, n.Tokens, false)
+			}
+		}
+		return ast.WalkContinue
+	})
+	if 1 > len(t.match) {
+		return
+	}
+
+	// 链接引用和脚注引用分别匹配，最后按照在文本中的位置排序
+	begin, offsets := len(c.diagnostics), map[*Diagnostic]int{}
+	defer func() {
+		found := c.diagnostics[begin:]
+		sort.SliceStable(found, func(i, j int) bool { return offsets[found[i]] < offsets[found[j]] })
+	}()
+
+	parseOptions := c.tree.Context.ParseOption
+	if parseOptions.LinkRef {
+		fulls := fullLinkRefRegexp.FindAllSubmatchIndex(t.match, -1)
+		for _, m := range fulls {
+			label := t.text[m[4]:m[5]]
+			if 0 == len(label) { // [foo][]
+				label = t.text[m[2]:m[3]]
+			}
+			if '^' == label[0] || nil != c.tree.FindLinkRefDefLink(label) {
+				continue
+			}
+			offsets[c.reportInline(RuleUndefinedLinkRef, "undefined link reference ["+string(label)+"]", string(label), t, m[0], m[1])] = m[0]
+		}
+
+		if c.options.ShortcutLinkRefs {
+			for _, m := range shortcutLinkRefRegexp.FindAllSubmatchIndex(t.match, -1) {
+				if inFullLinkRef(fulls, m[0]) {
+					continue // 已经作为完整形式检查过
+				}
+				label := t.text[m[2]:m[3]]
+				if nil != c.tree.FindLinkRefDefLink(label) {
+					continue
+				}
+				offsets[c.reportInline(RuleUndefinedLinkRef, "undefined link reference ["+string(label)+"]", string(label), t, m[0], m[1])] = m[0]
+			}
+		}
+	}
+
+	if parseOptions.Footnotes {
+		for _, m := range footnotesRefRegexp.FindAllSubmatchIndex(t.match, -1) {
+			label := t.text[m[2]:m[3]]
+			if _, def := c.tree.FindFootnotesDef(label); nil != def {
+				continue
+			}
+			offsets[c.reportInline(RuleUndefinedFootnote, "undefined footnote [^"+string(label)+"]", string(label), t, m[0], m[1])] = m[0]
+		}
+	}
+}
+
+// inFullLinkRef 判断偏移 offset 是否位于完整形式的链接引用匹配结果 fulls 中。
+func inFullLinkRef(fulls [][]int, offset int) bool {
+	for _, m := range fulls {
+		if m[0] <= offset && offset < m[1] {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *checker) checkBlockRef(n *ast.Node, id string) {
+	if "" == id || c.blockIDs[id] {
+		return
+	}
+	if nil != c.options.Resolver && c.options.Resolver(render.LinkKindBlockRef, id) {
+		return
+	}
+	c.report(RuleUndefinedBlockRef, "undefined block reference (("+id+"))", id, n)
+}
+
+func (c *checker) checkAnchor(n *ast.Node, dest string) {
+	if !strings.HasPrefix(dest, "#") || "#" == dest {
+		return
+	}
+
+	anchor := dest[1:]
+	if unescaped, err := url.PathUnescape(anchor); nil == err {
+		anchor = unescaped
+	}
+	if c.anchors[anchor] {
+		return
+	}
+	if nil != c.options.Resolver && c.options.Resolver(render.LinkKindLink, anchor) {
+		return
+	}
+	c.report(RuleUndefinedAnchor, "undefined anchor "+dest, anchor, n)
+}
+
+// report 记录一条节点 n 的检查结果，使用节点的位置。
+func (c *checker) report(rule, message, target string, n *ast.Node) {
+	c.diagnostics = append(c.diagnostics, &Diagnostic{Rule: rule, Message: message, Target: target, Start: n.SourceStart, End: n.SourceEnd, Node: n})
+}
+
+// reportInline 记录并返回一条行级内容 t 中从 start 到 end 的检查结果。
+func (c *checker) reportInline(rule, message, target string, t *inlineText, start, end int) (ret *Diagnostic) {
+	n, startPos := t.pos(start, false)
+	_, endPos := t.pos(end-1, true)
+	ret = &Diagnostic{Rule: rule, Message: message, Target: target, Start: startPos, End: endPos, Node: n}
+	c.diagnostics = append(c.diagnostics, ret)
+	return
+}
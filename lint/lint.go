@@ -0,0 +1,251 @@
+package lint
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"github.com/pafthang/md/ast"
+	"github.com/pafthang/md/parse"
+	"github.com/pafthang/md/render"
+)
+
+// Rule 描述了一条检查规则，规则 ID 和别名与 markdownlint 保持一致。
+type Rule struct {
+	ID          string // 规则 ID，比如 MD001
+	Alias       string // 规则别名，比如 heading-increment
+	Description string // 规则描述
+	Fixable     bool   // 是否可以通过 FormatRenderer 格式化自动修复
+
+	check func(ctx *ruleContext)
+}
+
+// Config 描述了检查规则配置，格式和 markdownlint 配置一致。
+//
+// 键为规则 ID 或者别名，值为 false 时禁用该规则，为 true 时启用该规则，为 map[string]interface{} 时启用该规则并使用其中的参数，
+// 比如 {"default": true, "MD013": {"line_length": 120}, "no-duplicate-heading": false}。"default" 设置未配置规则是否启用，默认启用。
+type Config map[string]interface{}
+
+// rule 返回规则 rule 是否启用以及规则参数。
+//
+// 同时配置了规则 ID 和别名时以规则 ID 为准。键不区分大小写，有多个仅大小写不同的键时优先使用完全匹配的键，其次使用排序最小的键。
+func (c Config) rule(rule *Rule) (enabled bool, params map[string]interface{}) {
+	enabled = true
+	if v, ok := c["default"].(bool); ok {
+		enabled = v
+	}
+	for _, name := range []string{rule.Alias, rule.ID} {
+		value, ok := c.lookup(name)
+		if !ok {
+			continue
+		}
+		switch v := value.(type) {
+		case bool:
+			enabled, params = v, nil
+		case map[string]interface{}:
+			enabled, params = true, v
+			if e, ok := v["enabled"].(bool); ok {
+				enabled = e
+			}
+		}
+	}
+	return
+}
+
+// lookup 不区分大小写地查找键 name 的值。
+func (c Config) lookup(name string) (value interface{}, ok bool) {
+	if value, ok = c[name]; ok {
+		return
+	}
+
+	var found string
+	for key := range c {
+		if strings.EqualFold(key, name) && (!ok || key < found) {
+			found, ok = key, true
+		}
+	}
+	if ok {
+		value = c[found]
+	}
+	return
+}
+
+// Linter 描述了 Markdown 检查器。
+type Linter struct {
+	Config        Config          // 规则配置
+	ParseOptions  *parse.Options  // 解析选项，检查时总会开启 SourcePos
+	RenderOptions *render.Options // 渲染选项，用于计算标题 ID 和自动修复时的格式化
+}
+
+// NewLinter 使用配置 config 创建一个检查器。
+func NewLinter(config Config) *Linter {
+	return &Linter{Config: config, ParseOptions: parse.NewOptions(), RenderOptions: render.NewOptions()}
+}
+
+// Rules 返回所有检查规则。
+func Rules() []*Rule {
+	return rules
+}
+
+// Lint 检查 Markdown 原文 source，返回的检查结果按照在原文中的位置排序。
+func (l *Linter) Lint(name string, source []byte) (ret []*Diagnostic) {
+	parseOptions := *l.ParseOptions
+	parseOptions.SourcePos = true
+	tree := parse.Parse(name, source, &parseOptions)
+
+	lines := bytes.Split(source, []byte("\n"))
+	lineOffsets := make([]int, len(lines))
+	for i, line := range lines {
+		if 0 < i {
+			lineOffsets[i] = lineOffsets[i-1] + len(lines[i-1]) + 1
+		}
+		lines[i] = bytes.TrimSuffix(line, []byte("\r"))
+	}
+	ctx := &ruleContext{tree: tree, source: source, lines: lines, lineOffsets: lineOffsets, renderOptions: l.RenderOptions}
+	ctx.collectBlockLines()
+	for _, rule := range rules {
+		enabled, params := l.Config.rule(rule)
+		if !enabled {
+			continue
+		}
+		ctx.rule, ctx.params = rule, params
+		rule.check(ctx)
+	}
+
+	ret = ctx.diagnostics
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].Start.Offset < ret[j].Start.Offset
+	})
+	return
+}
+
+// Fix 检查 Markdown 原文 source，存在可以自动修复的问题时使用 FormatRenderer 格式化后再次检查。
+//
+// 返回修复后的原文 fixed（没有需要修复的问题时为 source）和修复后仍然存在的问题。
+func (l *Linter) Fix(name string, source []byte) (fixed []byte, diagnostics []*Diagnostic) {
+	fixed = source
+	diagnostics = l.Lint(name, source)
+	var fixable bool
+	for _, d := range diagnostics {
+		if d.Fixable {
+			fixable = true
+			break
+		}
+	}
+	if !fixable {
+		return
+	}
+
+	tree := parse.Parse(name, source, l.ParseOptions)
+	fixed = render.NewFormatRenderer(tree, l.RenderOptions).Render()
+	diagnostics = l.Lint(name, fixed)
+	return
+}
+
+type ruleContext struct {
+	tree          *parse.Tree
+	source        []byte
+	lines         [][]byte        // 原文按行切分，不包含换行符
+	lineOffsets   []int           // 每行起始字节偏移
+	codeLines     map[int]bool    // 代码块所在行，从 1 开始
+	tableLines    map[int]bool    // 表格所在行
+	headingLines  map[int]bool    // 标题所在行
+	renderOptions *render.Options // 渲染选项
+
+	rule        *Rule
+	params      map[string]interface{}
+	diagnostics []*Diagnostic
+}
+
+func (ctx *ruleContext) collectBlockLines() {
+	ctx.codeLines, ctx.tableLines, ctx.headingLines = map[int]bool{}, map[int]bool{}, map[int]bool{}
+	ast.Walk(ctx.tree.Root, func(n *ast.Node, entering bool) ast.WalkStatus {
+		if !entering || nil == n.SourceStart || nil == n.SourceEnd {
+			return ast.WalkContinue
+		}
+
+		var lines map[int]bool
+		switch n.Type {
+		case ast.NodeCodeBlock, ast.NodeMathBlock, ast.NodeHTMLBlock, ast.NodeYamlFrontMatter:
+			lines = ctx.codeLines
+		case ast.NodeTable:
+			lines = ctx.tableLines
+		case ast.NodeHeading:
+			lines = ctx.headingLines
+		default:
+			return ast.WalkContinue
+		}
+		for line := n.SourceStart.Line; line <= n.SourceEnd.Line; line++ {
+			lines[line] = true
+		}
+		return ast.WalkSkipChildren
+	})
+}
+
+// report 记录一条检查结果，node 为出现问题的节点，可以为 nil。
+func (ctx *ruleContext) report(node *ast.Node, start, end *ast.Pos, message string) {
+	if nil == start && nil != node {
+		start, end = node.SourceStart, node.SourceEnd
+	}
+	if nil == start {
+		start = &ast.Pos{Line: 1, Column: 1}
+	}
+	ctx.diagnostics = append(ctx.diagnostics, &Diagnostic{Rule: ctx.rule.ID, Message: ctx.rule.Description + message, Start: start, End: end, Node: node, Fixable: ctx.rule.Fixable})
+}
+
+// linePos 返回第 line 行（从 1 开始）第 column 个字节（从 1 开始）的位置。
+func (ctx *ruleContext) linePos(line, column int) *ast.Pos {
+	return &ast.Pos{Line: line, Column: column, Offset: ctx.lineOffsets[line-1] + column - 1}
+}
+
+// offsetPos 返回字节偏移 offset 对应的位置。
+func (ctx *ruleContext) offsetPos(offset int) *ast.Pos {
+	line := sort.Search(len(ctx.lineOffsets), func(i int) bool { return ctx.lineOffsets[i] > offset })
+	return ctx.linePos(line, offset-ctx.lineOffsets[line-1]+1)
+}
+
+// locate 返回没有记录位置的节点 n 在原文中的位置：从 n 之前最近的有位置的节点开始查找文本 text。
+func (ctx *ruleContext) locate(n *ast.Node, text []byte) (start, end *ast.Pos) {
+	var from *ast.Pos
+	for p := n; nil != p && nil == from; p = p.Parent {
+		for prev := p.Previous; nil != prev && nil == from; prev = prev.Previous {
+			from = prev.SourceStart
+		}
+		if nil == from && nil != p.Parent {
+			from = p.Parent.SourceStart
+		}
+	}
+	if nil == from {
+		return
+	}
+
+	idx := bytes.Index(ctx.source[from.Offset:], text)
+	if 0 > idx {
+		return from, from
+	}
+	return ctx.offsetPos(from.Offset + idx), ctx.offsetPos(from.Offset + idx + len(text))
+}
+
+func (ctx *ruleContext) intParam(name string, defaultValue int) int {
+	switch v := ctx.params[name].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+	return defaultValue
+}
+
+func (ctx *ruleContext) boolParam(name string, defaultValue bool) bool {
+	if v, ok := ctx.params[name].(bool); ok {
+		return v
+	}
+	return defaultValue
+}
+
+func (ctx *ruleContext) stringParam(name string, defaultValue string) string {
+	if v, ok := ctx.params[name].(string); ok {
+		return v
+	}
+	return defaultValue
+}
@@ -0,0 +1,280 @@
+package lint
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pafthang/md/ast"
+)
+
+// rules 为所有检查规则，按照规则 ID 排序。
+var rules = []*Rule{
+	{ID: "MD001", Alias: "heading-increment", Description: "Heading levels should only increment by one level at a time", check: checkHeadingIncrement},
+	{ID: "MD004", Alias: "ul-style", Description: "Unordered list style", check: checkULStyle},
+	{ID: "MD009", Alias: "no-trailing-spaces", Description: "Trailing spaces", Fixable: true, check: checkTrailingSpaces},
+	{ID: "MD012", Alias: "no-multiple-blanks", Description: "Multiple consecutive blank lines", Fixable: true, check: checkMultipleBlanks},
+	{ID: "MD013", Alias: "line-length", Description: "Line length", check: checkLineLength},
+	{ID: "MD022", Alias: "blanks-around-headings", Description: "Headings should be surrounded by blank lines", Fixable: true, check: checkBlanksAroundHeadings},
+	{ID: "MD024", Alias: "no-duplicate-heading", Description: "Multiple headings with the same content", check: checkDuplicateHeading},
+	{ID: "MD034", Alias: "no-bare-urls", Description: "Bare URL used", Fixable: true, check: checkBareURLs},
+	{ID: "MD045", Alias: "no-alt-text", Description: "Images should have alternate text (alt text)", check: checkAltText},
+	{ID: "MD047", Alias: "single-trailing-newline", Description: "Files should end with a single newline character", Fixable: true, check: checkTrailingNewline},
+	{ID: "MD051", Alias: "link-fragments", Description: "Link fragments should be valid", check: checkLinkFragments},
+	{ID: "MD052", Alias: "reference-links-images", Description: "Reference links and images should use a label that is defined", check: checkReferenceLinks},
+}
+
+// checkHeadingIncrement 检查标题级别是否跳级，比如 h1 下直接出现 h3。
+func checkHeadingIncrement(ctx *ruleContext) {
+	var prev int
+	ast.Walk(ctx.tree.Root, func(n *ast.Node, entering bool) ast.WalkStatus {
+		if !entering || ast.NodeHeading != n.Type {
+			return ast.WalkContinue
+		}
+		if 0 < prev && n.HeadingLevel > prev+1 {
+			ctx.report(n, nil, nil, " [Expected: h"+strconv.Itoa(prev+1)+"; Actual: h"+strconv.Itoa(n.HeadingLevel)+"]")
+		}
+		prev = n.HeadingLevel
+		return ast.WalkSkipChildren
+	})
+}
+
+// checkULStyle 检查无序列表标识符，参数 style 可以是 consistent（默认）、asterisk、dash 或者 plus。
+func checkULStyle(ctx *ruleContext) {
+	var expected byte
+	switch ctx.stringParam("style", "consistent") {
+	case "asterisk":
+		expected = '*'
+	case "dash":
+		expected = '-'
+	case "plus":
+		expected = '+'
+	}
+
+	ast.Walk(ctx.tree.Root, func(n *ast.Node, entering bool) ast.WalkStatus {
+		if !entering || ast.NodeListItem != n.Type || nil == n.ListData || 0 == n.ListData.BulletChar {
+			return ast.WalkContinue
+		}
+		if 0 == expected {
+			expected = n.ListData.BulletChar
+		}
+		if actual := n.ListData.BulletChar; expected != actual {
+			ctx.report(n, n.SourceStart, n.SourceStart.Advance(1), " [Expected: "+ulStyleName(expected)+"; Actual: "+ulStyleName(actual)+"]")
+		}
+		return ast.WalkContinue
+	})
+}
+
+func ulStyleName(bullet byte) string {
+	switch bullet {
+	case '*':
+		return "asterisk"
+	case '+':
+		return "plus"
+	}
+	return "dash"
+}
+
+// checkTrailingSpaces 检查行尾空白，参数 br_spaces（默认 2）设置允许用于硬换行的空格数，strict 设置是否连硬换行也不允许。
+func checkTrailingSpaces(ctx *ruleContext) {
+	brSpaces := ctx.intParam("br_spaces", 2)
+	strict := ctx.boolParam("strict", false)
+	for i, line := range ctx.lines {
+		if ctx.codeLines[i+1] {
+			continue
+		}
+		trimmed := bytes.TrimRight(line, " \t")
+		count := len(line) - len(trimmed)
+		if 0 == count {
+			continue
+		}
+		hardBreak := !strict && 2 <= brSpaces && count == brSpaces && 0 < len(trimmed) && !bytes.ContainsRune(line[len(trimmed):], '\t') &&
+			i+1 < len(ctx.lines) && 0 < len(bytes.TrimSpace(ctx.lines[i+1]))
+		if hardBreak {
+			continue
+		}
+		ctx.report(nil, ctx.linePos(i+1, len(trimmed)+1), ctx.linePos(i+1, len(line)+1), " [Expected: 0 or "+strconv.Itoa(brSpaces)+"; Actual: "+strconv.Itoa(count)+"]")
+	}
+}
+
+// checkMultipleBlanks 检查连续空行，参数 maximum（默认 1）设置允许的最大连续空行数。
+func checkMultipleBlanks(ctx *ruleContext) {
+	maximum := ctx.intParam("maximum", 1)
+	var blanks int
+	for i, line := range ctx.lines {
+		if ctx.codeLines[i+1] || 0 < len(bytes.TrimSpace(line)) {
+			blanks = 0
+			continue
+		}
+		if i == len(ctx.lines)-1 && 0 == len(line) {
+			break // 文件末尾换行符后的空串
+		}
+		if blanks++; blanks > maximum {
+			ctx.report(nil, ctx.linePos(i+1, 1), ctx.linePos(i+1, len(line)+1), " [Expected: "+strconv.Itoa(maximum)+"; Actual: "+strconv.Itoa(blanks)+"]")
+		}
+	}
+}
+
+// checkLineLength 检查行长度，参数和 markdownlint 一致：line_length（默认 80）、heading_line_length、code_block_line_length、
+// code_blocks、tables、headings 和 strict。非 strict 模式下允许超出部分不包含空白的长行，比如长 URL。
+func checkLineLength(ctx *ruleContext) {
+	lineLength := ctx.intParam("line_length", 80)
+	headingLineLength := ctx.intParam("heading_line_length", lineLength)
+	codeBlockLineLength := ctx.intParam("code_block_line_length", lineLength)
+	codeBlocks := ctx.boolParam("code_blocks", true)
+	tables := ctx.boolParam("tables", true)
+	headings := ctx.boolParam("headings", true)
+	strict := ctx.boolParam("strict", false)
+
+	for i, line := range ctx.lines {
+		limit := lineLength
+		switch {
+		case ctx.codeLines[i+1]:
+			if !codeBlocks {
+				continue
+			}
+			limit = codeBlockLineLength
+		case ctx.tableLines[i+1]:
+			if !tables {
+				continue
+			}
+		case ctx.headingLines[i+1]:
+			if !headings {
+				continue
+			}
+			limit = headingLineLength
+		}
+
+		length := utf8.RuneCount(line)
+		if length <= limit {
+			continue
+		}
+		if !strict && !strings.ContainsAny(string([]rune(string(line))[limit:]), " \t") {
+			continue
+		}
+		ctx.report(nil, ctx.linePos(i+1, 1), ctx.linePos(i+1, len(line)+1), " [Expected: "+strconv.Itoa(limit)+"; Actual: "+strconv.Itoa(length)+"]")
+	}
+}
+
+// checkBlanksAroundHeadings 检查标题上下是否有空行。
+func checkBlanksAroundHeadings(ctx *ruleContext) {
+	ast.Walk(ctx.tree.Root, func(n *ast.Node, entering bool) ast.WalkStatus {
+		if !entering || ast.NodeHeading != n.Type || nil == n.SourceStart || nil == n.SourceEnd {
+			return ast.WalkContinue
+		}
+
+		start, end := n.SourceStart.Line, n.SourceEnd.Line
+		if ctx.tree.Root == n.Parent {
+			if 1 < start && 0 < len(bytes.TrimSpace(ctx.lines[start-2])) && !ctx.frontMatterEnd(start-1) {
+				ctx.report(n, nil, nil, " [Expected: 1; Actual: 0; Above]")
+			}
+			if end < len(ctx.lines) && 0 < len(bytes.TrimSpace(ctx.lines[end])) {
+				ctx.report(n, nil, nil, " [Expected: 1; Actual: 0; Below]")
+			}
+		}
+		return ast.WalkSkipChildren
+	})
+}
+
+// frontMatterEnd 判断第 line 行是否为 YAML Front Matter 的结束行。
+func (ctx *ruleContext) frontMatterEnd(line int) bool {
+	front := ctx.tree.Root.FirstChild
+	return nil != front && ast.NodeYamlFrontMatter == front.Type && nil != front.SourceEnd && line == front.SourceEnd.Line
+}
+
+// checkDuplicateHeading 检查内容相同的标题，参数 siblings_only 设置是否仅检查同一个上级标题下的标题。
+func checkDuplicateHeading(ctx *ruleContext) {
+	siblingsOnly := ctx.boolParam("siblings_only", false)
+	seen := []map[string]bool{{}}
+	ast.Walk(ctx.tree.Root, func(n *ast.Node, entering bool) ast.WalkStatus {
+		if !entering || ast.NodeHeading != n.Type {
+			return ast.WalkContinue
+		}
+
+		texts := seen[0]
+		if siblingsOnly {
+			for len(seen) <= n.HeadingLevel {
+				seen = append(seen, map[string]bool{})
+			}
+			for level := n.HeadingLevel + 1; level < len(seen); level++ {
+				seen[level] = map[string]bool{}
+			}
+			texts = seen[n.HeadingLevel]
+		}
+		text := strings.TrimSpace(n.Text())
+		if texts[text] {
+			ctx.report(n, nil, nil, " [Context: \""+text+"\"]")
+		}
+		texts[text] = true
+		return ast.WalkSkipChildren
+	})
+}
+
+// checkBareURLs 检查没有使用尖括号或者链接语法包裹的 URL。
+func checkBareURLs(ctx *ruleContext) {
+	ast.Walk(ctx.tree.Root, func(n *ast.Node, entering bool) ast.WalkStatus {
+		if !entering {
+			return ast.WalkContinue
+		}
+
+		switch n.Type {
+		case ast.NodeLink:
+			if 2 != n.LinkType {
+				return ast.WalkContinue
+			}
+			if nil == n.SourceStart {
+				// GFM 自动链接在行级解析后从文本中拆分出来，没有记录位置
+				start, end := ctx.locate(n, []byte(n.Text()))
+				ctx.report(n, start, end, " [Context: \""+n.Text()+"\"]")
+			} else if offset := n.SourceStart.Offset; offset < len(ctx.source) && '<' != ctx.source[offset] {
+				ctx.report(n, nil, nil, " [Context: \""+n.Text()+"\"]")
+			}
+			return ast.WalkSkipChildren
+		case ast.NodeCodeBlock, ast.NodeCodeSpan, ast.NodeHTMLBlock, ast.NodeInlineHTML:
+			return ast.WalkSkipChildren
+		}
+		return ast.WalkContinue
+	})
+}
+
+// checkAltText 检查没有替代文本的图片。
+func checkAltText(ctx *ruleContext) {
+	ast.Walk(ctx.tree.Root, func(n *ast.Node, entering bool) ast.WalkStatus {
+		if !entering || ast.NodeImage != n.Type {
+			return ast.WalkContinue
+		}
+		if "" == strings.TrimSpace(n.Text()) {
+			ctx.report(n, nil, nil, "")
+		}
+		return ast.WalkSkipChildren
+	})
+}
+
+// checkTrailingNewline 检查文件是否以一个换行符结尾。
+func checkTrailingNewline(ctx *ruleContext) {
+	if 0 == len(ctx.source) || bytes.HasSuffix(ctx.source, []byte("\n")) {
+		return
+	}
+	last := len(ctx.lines)
+	ctx.report(nil, ctx.linePos(last, len(ctx.lines[last-1])+1), nil, "")
+}
+
+// checkLinkFragments 检查锚点链接指向的标题或元素是否存在。
+func checkLinkFragments(ctx *ruleContext) {
+	for _, d := range Check(ctx.tree, &CheckOptions{RenderOptions: ctx.renderOptions}) {
+		if RuleUndefinedAnchor == d.Rule {
+			ctx.report(d.Node, d.Start, d.End, " [Context: \"#"+d.Target+"\"]")
+		}
+	}
+}
+
+// checkReferenceLinks 检查链接引用的标签是否定义，参数 shortcut_syntax 设置是否检查简写形式 [foo]。
+func checkReferenceLinks(ctx *ruleContext) {
+	options := &CheckOptions{RenderOptions: ctx.renderOptions, ShortcutLinkRefs: ctx.boolParam("shortcut_syntax", false)}
+	for _, d := range Check(ctx.tree, options) {
+		if RuleUndefinedLinkRef == d.Rule {
+			ctx.report(d.Node, d.Start, d.End, " [Missing link or image reference definition: \""+d.Target+"\"]")
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package md_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	md "github.com/pafthang/md"
+	"github.com/pafthang/md/lint"
+	"github.com/pafthang/md/parse"
+	"github.com/pafthang/md/render"
+)
+
+func TestLintCheckInlines(t *testing.T) {
+	options := parse.NewOptions()
+	options.SourcePos = true
+	markdown := "a &amp; \\[x] [*foo*][missing] [^n] \\[q][r]\n[a\nb][c] [see [x][m]](/u) [`a]`][ok]\n\n[ok]: /ok\n"
+	tree := parse.Parse("", []byte(markdown), options)
+	var got []string
+	for _, d := range lint.Check(tree, &lint.CheckOptions{ShortcutLinkRefs: true}) {
+		got = append(got, d.Target+" "+markdown[d.Start.Offset:d.End.Offset])
+	}
+	expected := []string{"missing [*foo*][missing]", "n [^n]", "r [r]", "c [a\nb][c]", "m [x][m]"}
+	if len(expected) != len(got) {
+		t.Fatalf("expected %q but got %q", expected, got)
+	}
+	for i := range expected {
+		if expected[i] != got[i] {
+			t.Fatalf("expected %q but got %q", expected, got)
+		}
+	}
+}
+
+func TestLintCheck(t *testing.T) {
+	options := parse.NewOptions()
+	options.SourcePos = true
+	options.BlockRef = true
+	options.KramdownBlockIAL = true
+	markdown := "# Head\n\na [^1] [^2] [x][B] [y][] [z] [B]\n{: id=\"20210101000000-abcdefg\"}\n\n" +
+		"((20210101000000-abcdefg \"x\")) ((20210101000000-zzzzzzz \"y\"))\n\n" +
+		"[h](#Head) [g](#head) [m](#missing) [e](#external) <a id=\"html\"></a> [i](#html) [f](#footnotes-def-1)\n\n[^1]: x\n\n[b]: /b\n"
+	tree := parse.Parse("", []byte(markdown), options)
+
+	var resolved []string
+	resolver := func(kind render.LinkKind, target string) bool {
+		resolved = append(resolved, strconv.Itoa(int(kind))+" "+target)
+		return "external" == target || "20210101000000-zzzzzzz" == target
+	}
+	for _, c := range []struct {
+		options  *lint.CheckOptions
+		expected []string
+		resolved []string
+	}{
+		{nil, []string{
+			"undefined-footnote 2 3:8 [^2]",
+			"undefined-link-ref y 3:20 [y][]",
+			"undefined-block-ref 20210101000000-zzzzzzz 6:32 ((20210101000000-zzzzzzz \"y\"))",
+			"undefined-anchor head 8:12 [g](#head)",
+			"undefined-anchor missing 8:23 [m](#missing)",
+			"undefined-anchor external 8:37 [e](#external)",
+		}, nil},
+		// 简写形式的链接引用、按照渲染选项计算的标题 ID 以及外部目标解析
+		{&lint.CheckOptions{ShortcutLinkRefs: true, RenderOptions: &render.Options{HeadingIDSlugger: render.SluggerGitHub}, Resolver: resolver}, []string{
+			"undefined-footnote 2 3:8 [^2]",
+			"undefined-link-ref y 3:20 [y][]",
+			"undefined-link-ref z 3:26 [z]",
+			"undefined-anchor Head 8:1 [h](#Head)",
+			"undefined-anchor missing 8:23 [m](#missing)",
+		}, []string{
+			strconv.Itoa(int(render.LinkKindBlockRef)) + " 20210101000000-zzzzzzz",
+			strconv.Itoa(int(render.LinkKindLink)) + " Head",
+			strconv.Itoa(int(render.LinkKindLink)) + " missing",
+			strconv.Itoa(int(render.LinkKindLink)) + " external",
+		}},
+	} {
+		resolved = nil
+		var got []string
+		for _, d := range lint.Check(tree, c.options) {
+			got = append(got, d.Rule+" "+d.Target+" "+d.Start.String()+" "+markdown[d.Start.Offset:d.End.Offset])
+		}
+		if strings.Join(c.expected, "\n") != strings.Join(got, "\n") {
+			t.Errorf("check options %+v, expected %q but got %q", c.options, c.expected, got)
+		}
+		if strings.Join(c.resolved, "\n") != strings.Join(resolved, "\n") {
+			t.Errorf("check options %+v, expected resolved %q but got %q", c.options, c.resolved, resolved)
+		}
+	}
+
+	// 未开启 LinkRef 和 Footnotes 时不检查链接引用和脚注引用
+	options = parse.NewOptions()
+	options.LinkRef, options.Footnotes = false, false
+	if diagnostics := lint.Check(parse.Parse("", []byte("[x][y] [^z]\n"), options), nil); 0 < len(diagnostics) {
+		t.Errorf("unexpected diagnostics %v", diagnostics)
+	}
+}
+
+// lintResult 返回检查结果的规则 ID 和起始行列，比如 MD009 1:4。
+func lintResult(diagnostics []*lint.Diagnostic) (ret []string) {
+	for _, d := range diagnostics {
+		ret = append(ret, d.Rule+" "+d.Start.String())
+	}
+	return
+}
+
+func TestLintRules(t *testing.T) {
+	engine := md.New()
+	for _, c := range []struct {
+		rule     string
+		markdown string
+		params   map[string]interface{}
+		expected []string
+	}{
+		{"MD001", "# a\n\n### b\n\n## c\n\n### d\n", nil, []string{"MD001 3:1"}},
+		{"MD004", "* a\n\n- b\n", nil, []string{"MD004 3:1"}},
+		{"MD004", "* a\n* b\n", map[string]interface{}{"style": "dash"}, []string{"MD004 1:1", "MD004 2:1"}},
+		{"MD009", "a \nb  \nc\n\n```\nd \n```\n", nil, []string{"MD009 1:2"}},
+		{"MD009", "a  \nb\n", map[string]interface{}{"strict": true}, []string{"MD009 1:2"}},
+		{"MD012", "a\n\n\nb\n\n```\n\n\n```\n", nil, []string{"MD012 3:1"}},
+		{"MD012", "a\n\n\nb\n", map[string]interface{}{"maximum": 2}, nil},
+		{"MD013", strings.Repeat("a ", 41) + "\n" + strings.Repeat("b", 90) + "\n", nil, []string{"MD013 1:1"}},
+		{"MD013", "# " + strings.Repeat("h ", 30) + "\n", map[string]interface{}{"line_length": 40, "headings": false}, nil},
+		{"MD022", "a\n# b\nc\n\n# d\n", nil, []string{"MD022 2:1", "MD022 2:1"}},
+		{"MD024", "# a\n\n## b\n\n# c\n\n## b\n", nil, []string{"MD024 7:1"}},
+		{"MD024", "# a\n\n## b\n\n# c\n\n## b\n", map[string]interface{}{"siblings_only": true}, nil},
+		{"MD034", "see https://example.com and <https://example.org> `https://code`\n", nil, []string{"MD034 1:5"}},
+		{"MD045", "![](a.png) ![alt](b.png)\n", nil, []string{"MD045 1:1"}},
+		{"MD047", "a", nil, []string{"MD047 1:2"}},
+		{"MD047", "a\n", nil, nil},
+		{"MD051", "# Foo\n\n[a](#Foo) [b](#bar)\n", nil, []string{"MD051 3:11"}},
+		{"MD052", "[a][x] [b][ok] [c]\n\n[ok]: /ok\n", nil, []string{"MD052 1:1"}},
+		{"MD052", "[a][x] [b][ok] [c]\n\n[ok]: /ok\n", map[string]interface{}{"shortcut_syntax": true}, []string{"MD052 1:1", "MD052 1:16"}},
+	} {
+		config := lint.Config{"default": false, c.rule: true}
+		if nil != c.params {
+			config[c.rule] = c.params
+		}
+		got := lintResult(engine.Lint("", []byte(c.markdown), config))
+		if strings.Join(c.expected, ",") != strings.Join(got, ",") {
+			t.Errorf("%s %v for [%q]: expected %q but got %q", c.rule, c.params, c.markdown, c.expected, got)
+		}
+	}
+}
+
+func TestLintConfig(t *testing.T) {
+	engine := md.New()
+	markdown := "# a\n\n### b \n"
+	for _, c := range []struct {
+		config   lint.Config
+		expected []string
+	}{
+		{nil, []string{"MD001 3:1", "MD009 3:6"}},
+		{lint.Config{"MD009": false}, []string{"MD001 3:1"}},
+		{lint.Config{"no-trailing-spaces": false}, []string{"MD001 3:1"}},
+		{lint.Config{"md009": false, "Heading-Increment": false}, nil},
+		{lint.Config{"default": false}, nil},
+		{lint.Config{"default": false, "heading-increment": true}, []string{"MD001 3:1"}},
+		{lint.Config{"default": false, "MD009": map[string]interface{}{"br_spaces": 1}}, []string{"MD009 3:6"}},
+		{lint.Config{"MD009": map[string]interface{}{"enabled": false}}, []string{"MD001 3:1"}},
+		// 同时配置了规则 ID 和别名时以规则 ID 为准
+		{lint.Config{"MD009": true, "no-trailing-spaces": false}, []string{"MD001 3:1", "MD009 3:6"}},
+		{lint.Config{"MD009": false, "no-trailing-spaces": true}, []string{"MD001 3:1"}},
+		{lint.Config{"MD001": false, "md001": true}, []string{"MD009 3:6"}},
+	} {
+		for i := 0; i < 8; i++ { // 配置是 map，多次检查确认结果不依赖遍历顺序
+			if got := lintResult(engine.Lint("", []byte(markdown), c.config)); strings.Join(c.expected, ",") != strings.Join(got, ",") {
+				t.Fatalf("config %v: expected %q but got %q", c.config, c.expected, got)
+			}
+		}
+	}
+}
+
+func TestLintFix(t *testing.T) {
+	engine := md.New()
+	markdown := "# a\ntext \n\n\n\nsee https://example.com\n# b"
+	fixed, diagnostics := engine.LintFix("", []byte(markdown), nil)
+	for _, d := range diagnostics {
+		if d.Fixable {
+			t.Errorf("unexpected fixable diagnostic after fix %s %s", d.Rule, d.Start)
+		}
+	}
+	if expected := "# a\n\ntext\n\nsee [https://example.com](https://example.com)\n\n# b\n"; expected != string(fixed) {
+		t.Fatalf("fixed mismatch, expected [%q] but got [%q]", expected, fixed)
+	}
+
+	markdown = "# a\n\n### b\n"
+	fixed, diagnostics = engine.LintFix("", []byte(markdown), nil)
+	if markdown != string(fixed) || 1 != len(diagnostics) || "MD001" != diagnostics[0].Rule {
+		t.Fatalf("expected source unchanged with MD001 but got [%q] %q", fixed, lintResult(diagnostics))
+	}
+}
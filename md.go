@@ -4,12 +4,14 @@ package md
 import (
 	"bytes"
 	"errors"
+	"io"
 	"strings"
 	"sync"
 
 	"github.com/gopherjs/gopherjs/js"
 	"github.com/pafthang/md/ast"
 	"github.com/pafthang/md/lex"
+	"github.com/pafthang/md/lint"
 	"github.com/pafthang/md/parse"
 	"github.com/pafthang/md/render"
 	"github.com/pafthang/md/util"
@@ -18,6 +20,9 @@ import (
 const Version = "1.7.6"
 
 // MD 描述了 MD 引擎的顶层使用入口。
+//
+// 配置完成后（调用 Set*、Put* 方法，修改 ParseOptions、RenderOptions 或者注册渲染器函数），同一个 *MD 可以被多个 goroutine 同时调用 Markdown、Format 等处理方法。
+// 配置方法不是并发安全的，不能和处理方法同时调用。Emoji 和术语字典采用写时复制，修改一个引擎的字典不会影响其他引擎和默认的全局字典。
 type MD struct {
 	ParseOptions  *parse.Options  // 解析选项
 	RenderOptions *render.Options // 渲染选项
@@ -49,12 +54,10 @@ type MD struct {
 //   - 中西文间插入空格
 //   - 修正术语拼写
 //   - 标题自定义 ID
-func New(opts ...ParseOption) (ret *MD) {
+//
+// opts 会在默认选项的基础上依次应用，比如 New(PresetGFM, WithToC(true))，预设参见 PresetCommonMark、PresetGFM 和 PresetProtyle 等。
+func New(opts ...Option) (ret *MD) {
 	ret = &MD{ParseOptions: parse.NewOptions(), RenderOptions: render.NewOptions()}
-	for _, opt := range opts {
-		opt(ret)
-	}
-
 	ret.HTML2MdRendererFuncs = map[ast.NodeType]render.ExtRendererFunc{}
 	ret.HTML2EditorDOMRendererFuncs = map[ast.NodeType]render.ExtRendererFunc{}
 	ret.HTML2EditorIRDOMRendererFuncs = map[ast.NodeType]render.ExtRendererFunc{}
@@ -65,6 +68,9 @@ func New(opts ...ParseOption) (ret *MD) {
 	ret.Md2EditorIRDOMRendererFuncs = map[ast.NodeType]render.ExtRendererFunc{}
 	ret.Md2BlockDOMRendererFuncs = map[ast.NodeType]render.ExtRendererFunc{}
 	ret.Md2EditorSVDOMRendererFuncs = map[ast.NodeType]render.ExtRendererFunc{}
+	for _, opt := range opts {
+		opt(ret)
+	}
 	return ret
 }
 
@@ -79,6 +85,70 @@ func (md *MD) Markdown(name string, markdown []byte) (html []byte) {
 	return
 }
 
+// MarkdownTo 从 r 中流式读取 markdown 文本，每当顶层块确定后立即将其渲染为 HTML 写入 w。
+//
+// 适用于大文档的转换，内存占用只和单个顶层块以及暂缓返回的分段大小相关，分段规则参见 parse.Stream。和 Markdown 相比有以下限制：
+//   - 不支持目录，开启 RenderOptions.ToC 时直接返回 ErrStreamToC
+//   - 脚注定义在读取完毕后统一输出在末尾，引用在定义之前出现时引用和定义之间的内容会在读取到定义后才输出
+//   - 使用了后续才定义的链接引用的内容会暂缓输出，暂缓的内容超过 parse.Stream.MaxPendingBytes 时提前输出，此时其中的链接引用不会被解析
+//   - 标题 ID 在整个文档内去重，但是预先占用的脚注锚点 ID 只考虑当前分段中的脚注
+//
+// 超出解析限制时仍然会输出降级后的完整 HTML，并在结束后返回第一个 *parse.LimitError。
+func (md *MD) MarkdownTo(w io.Writer, r io.Reader) (err error) {
+	if md.RenderOptions.ToC {
+		return ErrStreamToC
+	}
+
+	stream := parse.NewStream("", r, md.ParseOptions)
+	var limitErr error
+	var last *parse.Tree
+	var footnotesDefs []*ast.Node
+	headingIDOccurs := map[string]int{}
+	for {
+		tree, readErr := stream.Next()
+		if io.EOF == readErr {
+			break
+		}
+		if nil != readErr {
+			if !errors.Is(readErr, parse.ErrLimitExceeded) {
+				return readErr
+			}
+			if nil == limitErr {
+				limitErr = readErr
+			}
+		}
+
+		renderer := md.newStreamRenderer(tree)
+		renderer.FootnotesDefs = footnotesDefs
+		renderer.DeferFootnotes = true
+		renderer.HeadingIDOccurs = headingIDOccurs
+		if _, err = w.Write(renderer.Render()); nil != err {
+			return
+		}
+		footnotesDefs = renderer.FootnotesDefs
+		last = tree
+	}
+
+	if 0 < len(footnotesDefs) {
+		tree := &parse.Tree{Root: &ast.Node{Type: ast.NodeDocument}, Context: last.Context}
+		renderer := md.newStreamRenderer(tree)
+		renderer.FootnotesDefs = footnotesDefs
+		if _, err = w.Write(renderer.Render()); nil != err {
+			return
+		}
+	}
+	return limitErr
+}
+
+// newStreamRenderer 创建 MarkdownTo 渲染分段语法树 tree 使用的 HTML 渲染器。
+func (md *MD) newStreamRenderer(tree *parse.Tree) *render.HtmlRenderer {
+	ret := render.NewHtmlRenderer(tree, md.RenderOptions)
+	for nodeType, rendererFunc := range md.Md2HTMLRendererFuncs {
+		ret.ExtRendererFuncs[nodeType] = rendererFunc
+	}
+	return ret
+}
+
 // MarkdownStr 接受 string 类型的 markdown 后直接调用 Markdown 进行处理。
 func (md *MD) MarkdownStr(name, markdown string) (html string) {
 	htmlBytes := md.Markdown(name, []byte(markdown))
@@ -101,6 +171,25 @@ func (md *MD) FormatStr(name, markdown string) (formatted string) {
 	return
 }
 
+// Check 检查语法树 tree 中失效的链接引用、脚注引用、块引用和锚点链接，resolver 用于解析文档外部的块引用和锚点，可以为 nil。
+func (md *MD) Check(tree *parse.Tree, resolver lint.Resolver) []*lint.Diagnostic {
+	return lint.Check(tree, &lint.CheckOptions{Resolver: resolver, RenderOptions: md.RenderOptions})
+}
+
+// Lint 按照 markdownlint 兼容的规则配置 config 检查 markdown，返回的检查结果按照在原文中的位置排序。
+func (md *MD) Lint(name string, markdown []byte, config lint.Config) []*lint.Diagnostic {
+	return md.linter(config).Lint(name, markdown)
+}
+
+// LintFix 检查 markdown 并使用 FormatRenderer 自动修复可以修复的问题，返回修复后的原文和仍然存在的问题。
+func (md *MD) LintFix(name string, markdown []byte, config lint.Config) (fixed []byte, diagnostics []*lint.Diagnostic) {
+	return md.linter(config).Fix(name, markdown)
+}
+
+func (md *MD) linter(config lint.Config) *lint.Linter {
+	return &lint.Linter{Config: config, ParseOptions: md.ParseOptions, RenderOptions: md.RenderOptions}
+}
+
 // TextBundle 将 markdown 文本字节数组进行 TextBundle 处理。
 func (md *MD) TextBundle(name string, markdown []byte, linkPrefixes []string) (textbundle []byte, originalLinks []string) {
 	tree := parse.Parse(name, markdown, md.ParseOptions)
@@ -116,6 +205,38 @@ func (md *MD) TextBundleStr(name, markdown string, linkPrefixes []string) (textb
 	return
 }
 
+// Markdown2Docx 将 markdown 文本字节数组渲染为 .docx 文件字节数组，不依赖 Pandoc 等外部转换工具。
+//
+// 图片渲染为替代文本，需要嵌入图片时请使用 render.NewDocxRenderer 并设置 ImageLoader。err 为渲染时发生的第一个错误，打包失败时 docx 为 nil。
+func (md *MD) Markdown2Docx(name string, markdown []byte) (docx []byte, err error) {
+	tree := parse.Parse(name, markdown, md.ParseOptions)
+	renderer := render.NewDocxRenderer(tree, md.RenderOptions)
+	docx = renderer.Render()
+	err = renderer.Err()
+	return
+}
+
+// Markdown2LaTeX 将 markdown 文本字节数组渲染为包含导言区的完整 LaTeX 文档。
+func (md *MD) Markdown2LaTeX(name string, markdown []byte) (latex []byte) {
+	tree := parse.Parse(name, markdown, md.ParseOptions)
+	renderer := render.NewLaTeXRenderer(tree, md.RenderOptions)
+	latex = renderer.Render()
+	return
+}
+
+// Markdown2Text 将 markdown 文本字节数组渲染为适合在终端中显示的纯文本，width 为折行宽度，ansi 设置是否输出 ANSI 样式。
+func (md *MD) Markdown2Text(name string, markdown []byte, width int, ansi bool) (text []byte) {
+	tree := parse.Parse(name, markdown, md.ParseOptions)
+	renderer := render.NewTerminalRenderer(tree, md.RenderOptions, width, ansi)
+	text = renderer.Render()
+	return
+}
+
+// TOC 返回语法树 tree 的标题层级结构，options 为 nil 时仅包含文档顶层的标题且不生成编号。
+func (md *MD) TOC(tree *parse.Tree, options *render.TOCOptions) []*render.TOCItem {
+	return render.TOC(tree, md.RenderOptions, options)
+}
+
 // HTML2Text 将指定的 HTMl dom 转换为文本。
 func (md *MD) HTML2Text(dom string) string {
 	tree := md.HTML2Tree(dom)
@@ -158,9 +279,6 @@ func (md *MD) IsValidLinkDest(str string) bool {
 
 // GetEmojis 返回 Emoji 别名和对应 Unicode 字符的字典列表。
 func (md *MD) GetEmojis() (ret map[string]string) {
-	parse.EmojiLock.Lock()
-	defer parse.EmojiLock.Unlock()
-
 	ret = make(map[string]string, len(md.ParseOptions.AliasEmoji))
 	placeholder := util.BytesToStr(parse.EmojiSitePlaceholder)
 	for k, v := range md.ParseOptions.AliasEmoji {
@@ -173,37 +291,48 @@ func (md *MD) GetEmojis() (ret map[string]string) {
 }
 
 // PutEmojis 将指定的 emojiMap 合并覆盖已有的 Emoji 字典。
+//
+// 合并时会复制当前引擎的字典（写时复制），不会修改默认的全局字典 parse.EmojiAliasUnicode 以及其他引擎的字典。
 func (md *MD) PutEmojis(emojiMap map[string]string) {
-	parse.EmojiLock.Lock()
-	defer parse.EmojiLock.Unlock()
-
+	aliasEmoji, emojiAlias := copyDict(md.ParseOptions.AliasEmoji, len(emojiMap)), copyDict(md.ParseOptions.EmojiAlias, len(emojiMap))
 	for k, v := range emojiMap {
-		md.ParseOptions.AliasEmoji[k] = v
-		md.ParseOptions.EmojiAlias[v] = k
+		aliasEmoji[k] = v
+		emojiAlias[v] = k
 	}
+	md.ParseOptions.AliasEmoji, md.ParseOptions.EmojiAlias = aliasEmoji, emojiAlias
 }
 
 // RemoveEmoji 用于删除 str 中的 Emoji Unicode。
 func (md *MD) RemoveEmoji(str string) string {
-	parse.EmojiLock.Lock()
-	defer parse.EmojiLock.Unlock()
-
 	for u := range md.ParseOptions.EmojiAlias {
 		str = strings.ReplaceAll(str, u, "")
 	}
 	return strings.TrimSpace(str)
 }
 
-// GetTerms 返回术语字典。
+// GetTerms 返回术语字典的副本。
 func (md *MD) GetTerms() map[string]string {
-	return md.RenderOptions.Terms
+	return copyDict(md.RenderOptions.Terms, 0)
 }
 
 // PutTerms 将制定的 termMap 合并覆盖已有的术语字典。
+//
+// 和 PutEmojis 一样，合并时会复制当前引擎的字典，不会修改其他引擎共享的字典。
 func (md *MD) PutTerms(termMap map[string]string) {
+	terms := copyDict(md.RenderOptions.Terms, len(termMap))
 	for k, v := range termMap {
-		md.RenderOptions.Terms[k] = v
+		terms[k] = v
+	}
+	md.RenderOptions.Terms = terms
+}
+
+// copyDict 复制字典 dict，grow 为预计新增的条目数。
+func copyDict(dict map[string]string, grow int) (ret map[string]string) {
+	ret = make(map[string]string, len(dict)+grow)
+	for k, v := range dict {
+		ret[k] = v
 	}
+	return
 }
 
 var (
@@ -288,9 +417,6 @@ func (md *MD) Tree2HTML(tree *parse.Tree, options *render.Options) string {
 	return util.BytesToStr(output)
 }
 
-// ParseOption 描述了解析选项设置函数签名。
-type ParseOption func(md *MD)
-
 // 以下 Setters 主要是给 JavaScript 端导出方法用。
 
 func (md *MD) SetGFMTable(b bool) {
@@ -351,6 +477,11 @@ func (md *MD) SetHeadingID(b bool) {
 	md.RenderOptions.HeadingID = b
 }
 
+// SetHeadingIDSlugger 设置标题 ID 的生成策略，比如 render.SluggerGitHub，传入 nil 使用默认规则。
+func (md *MD) SetHeadingIDSlugger(slugger *render.Slugger) {
+	md.RenderOptions.HeadingIDSlugger = slugger
+}
+
 func (md *MD) SetAutoSpace(b bool) {
 	md.RenderOptions.AutoSpace = b
 }
@@ -364,7 +495,7 @@ func (md *MD) SetEmoji(b bool) {
 }
 
 func (md *MD) SetEmojis(emojis map[string]string) {
-	md.ParseOptions.AliasEmoji = emojis
+	md.ParseOptions.AliasEmoji = copyDict(emojis, 0)
 }
 
 func (md *MD) SetEmojiSite(emojiSite string) {
@@ -376,7 +507,7 @@ func (md *MD) SetHeadingAnchor(b bool) {
 }
 
 func (md *MD) SetTerms(terms map[string]string) {
-	md.RenderOptions.Terms = terms
+	md.RenderOptions.Terms = copyDict(terms, 0)
 }
 
 func (md *MD) SetEditorWYSIWYG(b bool) {
@@ -411,6 +542,16 @@ func (md *MD) SetLinkBase(linkBase string) {
 	md.RenderOptions.LinkBase = linkBase
 }
 
+// SetFormatStyle 设置格式化输出风格，比如列表标记符、强调标记符、标题风格和折行宽度，传入 nil 保持原文风格。
+func (md *MD) SetFormatStyle(style *render.FormatStyle) {
+	md.RenderOptions.FormatStyle = style
+}
+
+// SetLinkResolver 设置链接地址改写回调，用于改写链接、图片、自动链接、脚注引用和块引用的地址。
+func (md *MD) SetLinkResolver(resolver render.LinkResolver) {
+	md.RenderOptions.LinkResolver = resolver
+}
+
 func (md *MD) GetLinkBase() string {
 	return md.RenderOptions.LinkBase
 }
@@ -437,6 +578,11 @@ func (md *MD) SetSanitize(b bool) {
 	md.RenderOptions.Sanitize = b
 }
 
+// SetSanitizePolicy 设置基于白名单的 HTML 过滤策略，比如 render.NewStrictUGCPolicy()，传入 nil 时取消。
+func (md *MD) SetSanitizePolicy(policy *render.SanitizePolicy) {
+	md.RenderOptions.SanitizePolicy = policy
+}
+
 func (md *MD) SetImageLazyLoading(dataSrc string) {
 	md.RenderOptions.ImageLazyLoading = dataSrc
 }
@@ -461,6 +607,22 @@ func (md *MD) SetFileAnnotationRef(b bool) {
 	md.ParseOptions.FileAnnotationRef = b
 }
 
+func (md *MD) SetAdmonition(b bool) {
+	md.ParseOptions.Admonition = b
+}
+
+func (md *MD) SetDefinitionList(b bool) {
+	md.ParseOptions.DefinitionList = b
+}
+
+func (md *MD) SetWikiLink(b bool) {
+	md.ParseOptions.WikiLink = b
+}
+
+func (md *MD) SetWikiLinkResolver(resolver func(page string) (dest string)) {
+	md.RenderOptions.WikiLinkResolver = resolver
+}
+
 func (md *MD) SetMark(b bool) {
 	md.ParseOptions.Mark = b
 }
@@ -548,6 +710,10 @@ func (md *MD) SetSpellcheck(b bool) {
 	md.RenderOptions.Spellcheck = b
 }
 
+func (md *MD) SetSourcePos(b bool) {
+	md.ParseOptions.SourcePos = b
+}
+
 func (md *MD) SetJSRenderers(options map[string]map[string]*js.Object) {
 	for rendererType, extRenderer := range options["renderers"] {
 		switch extRenderer.Interface().(type) { // 稍微进行一点格式校验
@@ -0,0 +1,278 @@
+package md
+
+import (
+	"encoding/json"
+
+	"github.com/pafthang/md/ast"
+	"github.com/pafthang/md/parse"
+	"github.com/pafthang/md/render"
+)
+
+// Option 描述了引擎配置函数签名，一个配置函数可以同时设置解析选项和渲染选项。
+type Option func(md *MD)
+
+// ParseOption 描述了解析选项设置函数签名。
+//
+// Deprecated: 请使用 Option。
+type ParseOption = Option
+
+// 以下预设会先将解析选项和渲染选项重置为 New 的默认值再进行设置，所以需要作为 New 或者 Clone 的第一个选项。
+
+// PresetCommonMark 仅启用 CommonMark 规范中的语法，渲染结果和规范示例一致。
+func PresetCommonMark(md *MD) {
+	md.ParseOptions, md.RenderOptions = parse.NewOptions(), render.NewOptions()
+	md.SetGFMTable(false)
+	md.SetGFMTaskListItem(false)
+	md.SetGFMStrikethrough(false)
+	md.SetGFMAutoLink(false)
+	md.SetGFMTaskListItemClass("")
+	md.SetFootnotes(false)
+	md.SetHeadingID(false)
+	md.SetEmoji(false)
+	md.SetYamlFrontMatter(false)
+	md.SetSoftBreak2HardBreak(false)
+	md.SetCodeSyntaxHighlight(false)
+}
+
+// PresetGFM 在 PresetCommonMark 的基础上启用 GFM 表格、任务列表、删除线和自动链接，用于和 GFM 扩展示例进行一致性比较。
+func PresetGFM(md *MD) {
+	PresetCommonMark(md)
+	md.SetGFMTable(true)
+	md.SetGFMTaskListItem(true)
+	md.SetGFMStrikethrough(true)
+	md.SetGFMAutoLink(true)
+}
+
+// PresetProtyle 使用 Protyle 所见即所得编辑器的配置：启用块引用、kramdown 内联属性列表、超级块和标签等扩展，
+// 关闭脚注、目录、缩进代码块、Setext 标题和链接引用定义等和块结构冲突的语法。
+func PresetProtyle(md *MD) {
+	md.ParseOptions, md.RenderOptions = parse.NewOptions(), render.NewOptions()
+	md.SetProtyleWYSIWYG(true)
+	md.SetBlockRef(true)
+	md.SetFileAnnotationRef(true)
+	md.SetKramdownIAL(true)
+	md.SetTag(true)
+	md.SetSuperBlock(true)
+	md.SetImgPathAllowSpace(true)
+	md.SetGitConflict(true)
+	md.SetMark(true)
+	md.SetSup(true)
+	md.SetSub(true)
+	md.SetInlineMathAllowDigitAfterOpenMarker(true)
+	md.SetFootnotes(false)
+	md.SetToC(false)
+	md.SetIndentCodeBlock(false)
+	md.SetParagraphBeginningSpace(true)
+	md.SetAutoSpace(false)
+	md.SetHeadingID(false)
+	md.SetSetext(false)
+	md.SetYamlFrontMatter(false)
+	md.SetLinkRef(false)
+	md.SetCodeSyntaxHighlight(false)
+}
+
+// PresetEditorWYSIWYG 使用 Editor 所见即所得模式的配置。
+func PresetEditorWYSIWYG(md *MD) {
+	md.ParseOptions, md.RenderOptions = parse.NewOptions(), render.NewOptions()
+	md.SetEditorWYSIWYG(true)
+}
+
+// PresetEditorIR 使用 Editor 即时渲染模式的配置。
+func PresetEditorIR(md *MD) {
+	md.ParseOptions, md.RenderOptions = parse.NewOptions(), render.NewOptions()
+	md.SetEditorIR(true)
+}
+
+// PresetEditorSV 使用 Editor 分屏预览模式的配置。
+func PresetEditorSV(md *MD) {
+	md.ParseOptions, md.RenderOptions = parse.NewOptions(), render.NewOptions()
+	md.SetEditorSV(true)
+}
+
+// WithParseOptions 使用 set 修改解析选项。
+func WithParseOptions(set func(options *parse.Options)) Option {
+	return func(md *MD) {
+		set(md.ParseOptions)
+	}
+}
+
+// WithRenderOptions 使用 set 修改渲染选项。
+func WithRenderOptions(set func(options *render.Options)) Option {
+	return func(md *MD) {
+		set(md.RenderOptions)
+	}
+}
+
+// WithToC 同时设置解析选项和渲染选项中的“目录”支持。
+func WithToC(b bool) Option {
+	return func(md *MD) {
+		md.SetToC(b)
+	}
+}
+
+// WithHeadingID 同时设置解析选项和渲染选项中的“自定义标题 ID”支持。
+func WithHeadingID(b bool) Option {
+	return func(md *MD) {
+		md.SetHeadingID(b)
+	}
+}
+
+// WithKramdownIAL 同时设置解析选项和渲染选项中的 kramdown 块级和行级内联属性列表支持。
+func WithKramdownIAL(b bool) Option {
+	return func(md *MD) {
+		md.SetKramdownIAL(b)
+	}
+}
+
+// WithSuperBlock 同时设置解析选项和渲染选项中的超级块支持。
+func WithSuperBlock(b bool) Option {
+	return func(md *MD) {
+		md.SetSuperBlock(b)
+	}
+}
+
+// WithParagraphBeginningSpace 同时设置解析时和渲染时是否保留段首空格。
+func WithParagraphBeginningSpace(b bool) Option {
+	return func(md *MD) {
+		md.SetParagraphBeginningSpace(b)
+	}
+}
+
+// WithSanitize 设置是否对输出进行 XSS 过滤，参见 SetSanitize。
+func WithSanitize(b bool) Option {
+	return func(md *MD) {
+		md.SetSanitize(b)
+	}
+}
+
+// WithSanitizePolicy 设置基于白名单的 HTML 过滤策略，参见 SetSanitizePolicy。
+func WithSanitizePolicy(policy *render.SanitizePolicy) Option {
+	return func(md *MD) {
+		md.SetSanitizePolicy(policy)
+	}
+}
+
+// WithEmojis 将 emojiMap 合并覆盖到引擎的 Emoji 字典，参见 PutEmojis。
+func WithEmojis(emojiMap map[string]string) Option {
+	return func(md *MD) {
+		md.PutEmojis(emojiMap)
+	}
+}
+
+// WithTerms 将 termMap 合并覆盖到引擎的术语字典，参见 PutTerms。
+func WithTerms(termMap map[string]string) Option {
+	return func(md *MD) {
+		md.PutTerms(termMap)
+	}
+}
+
+// WithConfig 使用 config 替换引擎的解析选项和渲染选项，并合并 config 中的 Emoji 和术语字典。
+func WithConfig(config *Config) Option {
+	return func(md *MD) {
+		parseOptions, renderOptions := config.ParseOptions, config.RenderOptions
+		if nil == parseOptions {
+			parseOptions = parse.NewOptions()
+		}
+		if nil == renderOptions {
+			renderOptions = render.NewOptions()
+		}
+		md.ParseOptions, md.RenderOptions = cloneOptions(parseOptions, renderOptions)
+		if nil == md.ParseOptions.AliasEmoji {
+			md.ParseOptions.AliasEmoji, md.ParseOptions.EmojiAlias = parse.EmojiAliasUnicode, parse.EmojiUnicodeAlias
+		}
+		if nil == md.RenderOptions.Terms {
+			md.RenderOptions.Terms = render.NewTerms()
+		}
+		md.PutEmojis(config.Emojis)
+		md.PutTerms(config.Terms)
+	}
+}
+
+// Clone 复制引擎后依次应用 opts，返回的新引擎和原引擎互不影响，可以用来从同一个基础配置派生出不同的变体。
+//
+// 渲染器函数、解析器扩展和 SanitizePolicy 等回调和策略对象由新旧引擎共享。
+func (md *MD) Clone(opts ...Option) (ret *MD) {
+	ret = &MD{}
+	ret.ParseOptions, ret.RenderOptions = cloneOptions(md.ParseOptions, md.RenderOptions)
+	ret.HTML2MdRendererFuncs = copyRendererFuncs(md.HTML2MdRendererFuncs)
+	ret.HTML2EditorDOMRendererFuncs = copyRendererFuncs(md.HTML2EditorDOMRendererFuncs)
+	ret.HTML2EditorIRDOMRendererFuncs = copyRendererFuncs(md.HTML2EditorIRDOMRendererFuncs)
+	ret.HTML2BlockDOMRendererFuncs = copyRendererFuncs(md.HTML2BlockDOMRendererFuncs)
+	ret.HTML2EditorSVDOMRendererFuncs = copyRendererFuncs(md.HTML2EditorSVDOMRendererFuncs)
+	ret.Md2HTMLRendererFuncs = copyRendererFuncs(md.Md2HTMLRendererFuncs)
+	ret.Md2EditorDOMRendererFuncs = copyRendererFuncs(md.Md2EditorDOMRendererFuncs)
+	ret.Md2EditorIRDOMRendererFuncs = copyRendererFuncs(md.Md2EditorIRDOMRendererFuncs)
+	ret.Md2BlockDOMRendererFuncs = copyRendererFuncs(md.Md2BlockDOMRendererFuncs)
+	ret.Md2EditorSVDOMRendererFuncs = copyRendererFuncs(md.Md2EditorSVDOMRendererFuncs)
+	for _, opt := range opts {
+		opt(ret)
+	}
+	return
+}
+
+// cloneOptions 复制解析选项和渲染选项，Emoji 和术语字典采用写时复制，所以不需要复制。
+func cloneOptions(parseOptions *parse.Options, renderOptions *render.Options) (*parse.Options, *render.Options) {
+	parseOptionsCopy, renderOptionsCopy := *parseOptions, *renderOptions
+	parseOptionsCopy.BlockParsers = append([]*parse.BlockParser(nil), parseOptions.BlockParsers...)
+	parseOptionsCopy.InlineParsers = append([]*parse.InlineParser(nil), parseOptions.InlineParsers...)
+	if nil != renderOptions.FormatStyle {
+		formatStyle := *renderOptions.FormatStyle
+		renderOptionsCopy.FormatStyle = &formatStyle
+	}
+	return &parseOptionsCopy, &renderOptionsCopy
+}
+
+func copyRendererFuncs(funcs map[ast.NodeType]render.ExtRendererFunc) (ret map[ast.NodeType]render.ExtRendererFunc) {
+	ret = make(map[ast.NodeType]render.ExtRendererFunc, len(funcs))
+	for nodeType, rendererFunc := range funcs {
+		ret[nodeType] = rendererFunc
+	}
+	return
+}
+
+// Config 描述了可以序列化为 JSON 的引擎配置。
+//
+// 解析器扩展、回调函数和 SanitizePolicy 不会被序列化；Emoji 和术语字典只序列化相对默认字典新增或者修改的条目，删除的条目不会保留。
+type Config struct {
+	ParseOptions  *parse.Options    `json:"parseOptions"`     // 解析选项
+	RenderOptions *render.Options   `json:"renderOptions"`    // 渲染选项
+	Emojis        map[string]string `json:"emojis,omitempty"` // 相对默认 Emoji 字典新增或者修改的条目
+	Terms         map[string]string `json:"terms,omitempty"`  // 相对默认术语字典新增或者修改的条目
+}
+
+// Config 返回引擎当前配置的副本，可以使用 json.Marshal 序列化，使用 WithConfig 还原。
+func (md *MD) Config() (ret *Config) {
+	ret = &Config{}
+	ret.ParseOptions, ret.RenderOptions = cloneOptions(md.ParseOptions, md.RenderOptions)
+	ret.Emojis = diffDict(md.ParseOptions.AliasEmoji, parse.EmojiAliasUnicode)
+	ret.Terms = diffDict(md.RenderOptions.Terms, render.NewTerms())
+	return
+}
+
+// UnmarshalConfig 将 JSON 格式的配置 data 反序列化为 Config，data 中没有出现的选项使用 New 的默认值。
+//
+// 自定义的标题 ID 生成策略无法还原，反序列化后为 nil（使用默认规则）。
+func UnmarshalConfig(data []byte) (ret *Config, err error) {
+	ret = &Config{ParseOptions: parse.NewOptions(), RenderOptions: render.NewOptions()}
+	if err = json.Unmarshal(data, ret); nil != err {
+		return nil, err
+	}
+	if slugger := ret.RenderOptions.HeadingIDSlugger; nil != slugger && nil == slugger.Slug {
+		ret.RenderOptions.HeadingIDSlugger = nil
+	}
+	return
+}
+
+// diffDict 返回 dict 中相对 base 新增或者修改的条目，没有差异时返回 nil。
+func diffDict(dict, base map[string]string) (ret map[string]string) {
+	for k, v := range dict {
+		if baseValue, ok := base[k]; ok && baseValue == v {
+			continue
+		}
+		if nil == ret {
+			ret = map[string]string{}
+		}
+		ret[k] = v
+	}
+	return
+}
@@ -0,0 +1,122 @@
+package md_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	md "github.com/pafthang/md"
+	"github.com/pafthang/md/ast"
+	"github.com/pafthang/md/parse"
+	"github.com/pafthang/md/render"
+)
+
+func TestPresets(t *testing.T) {
+	cases := []struct {
+		name     string
+		engine   *md.MD
+		markdown string
+		html     string
+	}{
+		{"commonmark", md.New(md.PresetCommonMark), "~~foo~~ :smile:\nbar\n", "<p>~~foo~~ :smile:\nbar</p>\n"},
+		{"gfm", md.New(md.PresetGFM), "~~foo~~ :smile:\n- [x] bar\n", "<p><del>foo</del> :smile:</p>\n<ul>\n<li><input checked=\"\" disabled=\"\" type=\"checkbox\" /> bar</li>\n</ul>\n"},
+		{"gfm toc", md.New(md.PresetGFM, md.WithToC(true), md.WithHeadingID(true)), "# foo {#bar}\n", "<h1 id=\"bar\">foo</h1>\n"},
+		{"reset", md.New(md.WithEmojis(map[string]string{"foo": "bar"}), md.PresetCommonMark), ":foo:\n", "<p>:foo:</p>\n"},
+	}
+	for _, c := range cases {
+		if html := c.engine.MarkdownStr("", c.markdown); c.html != html {
+			t.Errorf("preset [%s] html mismatch, expected [%q] but got [%q]", c.name, c.html, html)
+		}
+	}
+
+	protyle := md.New(md.PresetProtyle)
+	if !protyle.ParseOptions.ProtyleWYSIWYG || !protyle.RenderOptions.KramdownBlockIAL || protyle.ParseOptions.Setext {
+		t.Fatalf("unexpected protyle options")
+	}
+	ir := md.New(md.PresetEditorIR)
+	if !ir.ParseOptions.EditorIR || !ir.RenderOptions.EditorIR {
+		t.Fatalf("unexpected editor ir options")
+	}
+}
+
+func TestClone(t *testing.T) {
+	base := md.New(md.PresetGFM, md.WithRenderOptions(func(options *render.Options) {
+		options.FormatStyle = &render.FormatStyle{BulletChar: '-'}
+	}))
+	base.Md2HTMLRendererFuncs[ast.NodeText] = func(n *ast.Node, entering bool) (string, ast.WalkStatus) {
+		return "text", ast.WalkContinue
+	}
+
+	variant := base.Clone(md.WithParseOptions(func(options *parse.Options) {
+		options.GFMStrikethrough = false
+	}), md.WithEmojis(map[string]string{"foo": "bar"}))
+	variant.RenderOptions.FormatStyle.BulletChar = '*'
+	delete(variant.Md2HTMLRendererFuncs, ast.NodeText)
+
+	if !base.ParseOptions.GFMStrikethrough || '-' != base.RenderOptions.FormatStyle.BulletChar || nil == base.Md2HTMLRendererFuncs[ast.NodeText] {
+		t.Fatalf("clone modified the base engine")
+	}
+	if _, ok := base.GetEmojis()["foo"]; ok {
+		t.Fatalf("clone modified the base emojis")
+	}
+	if expected, html := "<p>~~a~~ :foo:</p>\n", variant.MarkdownStr("", "~~a~~ :foo:"); expected != html {
+		t.Fatalf("variant html mismatch, expected [%q] but got [%q]", expected, html)
+	}
+}
+
+func TestConfigJSON(t *testing.T) {
+	engine := md.New(md.PresetGFM,
+		md.WithSanitize(true),
+		md.WithEmojis(map[string]string{"foo": "bar.png"}),
+		md.WithTerms(map[string]string{"foo": "FOO"}),
+		md.WithRenderOptions(func(options *render.Options) {
+			options.HeadingIDSlugger = render.SluggerGitLab
+			options.FormatStyle = &render.FormatStyle{HardWrap: 80}
+		}),
+	)
+
+	data, err := json.Marshal(engine.Config())
+	if nil != err {
+		t.Fatalf("marshal config failed: %s", err)
+	}
+	config, err := md.UnmarshalConfig(data)
+	if nil != err {
+		t.Fatalf("unmarshal config failed: %s", err)
+	}
+	restored := md.New(md.WithConfig(config))
+	if again, _ := json.Marshal(restored.Config()); string(data) != string(again) {
+		t.Fatalf("config mismatch, expected [%s] but got [%s]", data, again)
+	}
+	if render.SluggerGitLab.Name != restored.RenderOptions.HeadingIDSlugger.Name || nil == restored.RenderOptions.HeadingIDSlugger.Slug {
+		t.Fatalf("slugger not restored")
+	}
+	if "bar.png" != restored.GetEmojis()["foo"] || "FOO" != restored.GetTerms()["foo"] {
+		t.Fatalf("dictionaries not restored")
+	}
+
+	markdown := "* foo :foo:\n"
+	if expected, html := engine.MarkdownStr("", markdown), restored.MarkdownStr("", markdown); expected != html {
+		t.Fatalf("restored engine output mismatch, expected [%q] but got [%q]", expected, html)
+	}
+
+	custom := md.New(md.WithRenderOptions(func(options *render.Options) {
+		options.HeadingIDSlugger = render.NewSlugger("foo", strings.ToUpper)
+	}))
+	data, _ = json.Marshal(custom.Config())
+	if config, err = md.UnmarshalConfig(data); nil != err {
+		t.Fatalf("unmarshal custom slugger config failed: %s", err)
+	}
+	if restored = md.New(md.WithConfig(config)); nil != restored.RenderOptions.HeadingIDSlugger {
+		t.Fatalf("expected unknown slugger to be skipped")
+	}
+	if expected, html := md.New().MarkdownStr("", "# Foo\n"), restored.MarkdownStr("", "# Foo\n"); expected != html {
+		t.Fatalf("unknown slugger output mismatch, expected [%q] but got [%q]", expected, html)
+	}
+	config, err = md.UnmarshalConfig([]byte(`{"parseOptions":{"GFMTable":false}}`))
+	if nil != err {
+		t.Fatalf("unmarshal config failed: %s", err)
+	}
+	if partial := md.New(md.WithConfig(config)); partial.ParseOptions.GFMTable || !partial.ParseOptions.Footnotes || !partial.RenderOptions.CodeSyntaxHighlight {
+		t.Fatalf("unexpected partial config options")
+	}
+}
@@ -0,0 +1,152 @@
+package parse
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/pafthang/md/ast"
+	"github.com/pafthang/md/editor"
+	"github.com/pafthang/md/lex"
+)
+
+// AdmonitionStart 判断围栏提示块（:::type title）是否开始。
+func AdmonitionStart(t *Tree, container *ast.Node) int {
+	if !t.Context.ParseOption.Admonition || t.Context.indented {
+		return 0
+	}
+
+	if ok, fenceLen, kind, title := t.parseAdmonition(); ok {
+		t.Context.closeUnmatchedBlocks()
+		admonition := t.Context.addChild(ast.NodeAdmonition)
+		admonition.AdmonitionFenceLen = fenceLen
+		admonition.AdmonitionKind = kind
+		admonition.AdmonitionTitle = title
+		t.Context.offset = t.Context.currentLineLen - 1 // 整行过
+		return 1
+	}
+	return 0
+}
+
+func AdmonitionContinue(admonition *ast.Node, context *Context) int {
+	if context.indented || !context.isAdmonitionClose(context.currentLine[context.nextNonspace:], admonition.AdmonitionFenceLen) {
+		return 0
+	}
+
+	for n := context.Tip; nil != n && admonition != n; n = n.Parent {
+		if (ast.NodeCodeBlock == n.Type && n.IsFencedCodeBlock) || ast.NodeMathBlock == n.Type || ast.NodeAdmonition == n.Type {
+			// 闭合标记符属于未闭合的子块
+			return 0
+		}
+	}
+
+	// 闭合提示块下的子节点
+	for n := context.Tip; nil != n && admonition != n; n = n.Parent {
+		context.finalize(n)
+	}
+	context.finalize(admonition)
+	return 2
+}
+
+// blockquoteFinalize 将首行为 [!KIND] 的块引用转换为提示块（GFM 提示）。
+func (context *Context) blockquoteFinalize(blockquote *ast.Node) {
+	if !context.ParseOption.Admonition {
+		return
+	}
+
+	marker := blockquote.FirstChild
+	if nil == marker || ast.NodeBlockquoteMarker != marker.Type {
+		return
+	}
+	p := marker.Next
+	if nil == p || ast.NodeParagraph != p.Type {
+		return
+	}
+
+	line := p.Tokens
+	if idx := bytes.IndexByte(line, lex.ItemNewline); 0 <= idx {
+		line = line[:idx+1]
+	}
+	kind, title := parseAlertMarker(line)
+	if "" == kind {
+		return
+	}
+
+	blockquote.Type = ast.NodeAdmonition
+	blockquote.AdmonitionKind = kind
+	blockquote.AdmonitionTitle = title
+	marker.Unlink()
+	context.shiftSourceSegments(p, len(line))
+	p.Tokens = p.Tokens[len(line):]
+	if 1 > len(lex.TrimWhitespace(p.Tokens)) {
+		p.Unlink()
+	} else if context.ParseOption.SourcePos {
+		p.SourceStart = context.sourcePos(p, 0)
+	}
+}
+
+// alertKinds 为 GFM 提示支持的类型，其他类型的 [!KIND] 仍然作为普通块引用。
+var alertKinds = []string{"note", "tip", "important", "warning", "caution"}
+
+// parseAlertMarker 解析 GFM 提示首行 [!KIND] title，不匹配时返回的 kind 为空。
+func parseAlertMarker(line []byte) (kind, title string) {
+	line = lex.TrimWhitespace(line)
+	if !bytes.HasPrefix(line, []byte("[!")) {
+		return
+	}
+	end := bytes.IndexByte(line, lex.ItemCloseBracket)
+	if 3 > end {
+		return
+	}
+	k := strings.ToLower(string(line[2:end]))
+	for _, alertKind := range alertKinds {
+		if alertKind == k {
+			return k, string(lex.TrimWhitespace(line[end+1:]))
+		}
+	}
+	return
+}
+
+func (t *Tree) parseAdmonition() (ok bool, fenceLen int, kind, title string) {
+	marker := t.Context.currentLine[t.Context.nextNonspace]
+	if lex.ItemColon != marker {
+		return
+	}
+
+	for i := t.Context.nextNonspace; i < t.Context.currentLineLen && lex.ItemColon == t.Context.currentLine[i]; i++ {
+		fenceLen++
+	}
+	if 3 > fenceLen {
+		return
+	}
+
+	info := lex.TrimWhitespace(t.Context.currentLine[t.Context.nextNonspace+fenceLen:])
+	info = bytes.ReplaceAll(info, editor.CaretTokens, nil)
+	var k []byte
+	k, info = info, nil
+	if idx := bytes.IndexAny(k, " \t"); 0 <= idx {
+		k, info = k[:idx], lex.TrimWhitespace(k[idx:])
+	}
+	if 1 > len(k) {
+		return
+	}
+	for _, token := range k {
+		if !lex.IsASCIILetter(token) && lex.ItemHyphen != token {
+			return
+		}
+	}
+	return true, fenceLen, strings.ToLower(string(k)), string(info)
+}
+
+func (context *Context) isAdmonitionClose(tokens []byte, fenceLen int) bool {
+	tokens = lex.TrimWhitespace(tokens)
+	tokens = bytes.ReplaceAll(tokens, editor.CaretTokens, nil)
+	if fenceLen > len(tokens) {
+		return false
+	}
+	for _, token := range tokens {
+		if lex.ItemColon != token {
+			return false
+		}
+	}
+	return true
+}
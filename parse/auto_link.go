@@ -153,6 +153,20 @@ func (t *Tree) parseGFMAutoLink0(node *ast.Node) {
 		return
 	}
 
+	// 自定义协议需要 tokens[i:] 中只有一个 :// 并且没有 http://、https:// 和 ftp://，预先记录它们最后出现的位置，避免每个位置都扫描到末尾
+	schemeSep := []byte("://")
+	lastSep := bytes.LastIndex(tokens, schemeSep)
+	prevSep := -1
+	if 0 < lastSep {
+		prevSep = bytes.LastIndex(tokens[:lastSep], schemeSep)
+	}
+	lastProto := bytes.LastIndex(tokens, httpProto)
+	for _, proto := range [][]byte{httpsProto, ftpProto} {
+		if last := bytes.LastIndex(tokens, proto); lastProto < last {
+			lastProto = last
+		}
+	}
+
 	var i, j, k int
 	var textStart, textEnd int
 	var token byte
@@ -175,16 +189,17 @@ func (t *Tree) parseGFMAutoLink0(node *ast.Node) {
 		} else if 12 <= tmpLen /* ftp://xxx.xx */ && 'f' == tokens[i] && 't' == tokens[i+1] && 'p' == tokens[i+2] && ':' == tokens[i+3] && '/' == tokens[i+4] && '/' == tokens[i+5] {
 			protocol = tokens[i : i+6]
 			i += 6
-		} else if parts := bytes.Split(tokens[i:], []byte("://")); 2 == len(parts) && 0 < len(parts[0]) && 0 < len(parts[1]) && !bytes.Contains(tokens[i:], httpProto) && !bytes.Contains(tokens[i:], httpsProto) && !bytes.Contains(tokens[i:], ftpProto) {
-			if !lex.IsASCIILetterNums(parts[0]) {
+		} else if prevSep < i && i < lastSep && lastSep+len(schemeSep) < length && lastProto < i {
+			scheme := tokens[i:lastSep:lastSep]
+			if !lex.IsASCIILetterNums(scheme) {
 				textEnd++
 				i++
 				continue
 			}
 
 			// 自定义协议均认为是有效的 https://github.com/siyuan-note/siyuan/issues/5865
-			protocol = append(parts[0], []byte("://")...)
-			i += len(parts[0]) + 3
+			protocol = append(scheme, schemeSep...)
+			i += len(scheme) + 3
 		} else {
 			textEnd++
 			if length-i < minLinkLen { // 剩余字符不足，已经不可能形成链接了
@@ -210,6 +225,7 @@ func (t *Tree) parseGFMAutoLink0(node *ast.Node) {
 
 		var url []byte
 		j = i
+		portScanner := &autoLinkPort{tokens: tokens, start: i, colon: i, fixed: i, lastSlash: -1}
 		for ; j < length; j++ {
 			token = tokens[j]
 			if (lex.IsWhitespace(token) || lex.ItemLess == token) || (!lex.IsASCIIPunct(token) && !lex.IsASCIILetterNum(token)) {
@@ -217,11 +233,8 @@ func (t *Tree) parseGFMAutoLink0(node *ast.Node) {
 			}
 
 			// 判断端口后部分是否为数字
-			if tmp := bytes.ReplaceAll(url, []byte("://"), nil); bytes.Contains(tmp, []byte(":")) {
-				tmp = tmp[bytes.Index(tmp, []byte(":"))+1:]
-				if !bytes.Contains(tmp, []byte("/")) && !lex.IsDigit(token) && lex.ItemSlash != token {
-					break
-				}
+			if portScanner.inPort(j) && !lex.IsDigit(token) && lex.ItemSlash != token {
+				break
 			}
 
 			url = append(url, token)
@@ -554,26 +567,26 @@ func (t *Tree) newLink(typ ast.NodeType, text, dest, title []byte, linkType int)
 
 func (t *Tree) parseAutolink(ctx *InlineContext) (ret *ast.Node) {
 	schemed := false
-	scheme := ""
+	schemeLen := 0
 	var dest []byte
 	var token byte
 	i := ctx.pos + 1
 	for ; i < ctx.tokensLen && lex.ItemGreater != ctx.tokens[i]; i++ {
 		token = ctx.tokens[i]
-		if lex.ItemSpace == token {
+		if lex.ItemSpace == token || lex.ItemLess == token { // 自动链接中不能包含 <，提前结束避免连续的 < 导致反复扫描到末尾
 			return nil
 		}
 
 		dest = append(dest, ctx.tokens[i])
 		if !schemed {
 			if lex.ItemColon != token {
-				scheme += string(token)
+				schemeLen++
 			} else {
 				schemed = true
 			}
 		}
 	}
-	if !schemed || 3 > len(scheme) || i == ctx.tokensLen {
+	if !schemed || 3 > schemeLen || i == ctx.tokensLen {
 		return nil
 	}
 
@@ -584,6 +597,47 @@ func (t *Tree) parseAutolink(ctx *InlineContext) (ret *ast.Node) {
 	return t.newLink(ast.NodeLink, dest, html.EncodeDestination(dest), nil, 2)
 }
 
+// autoLinkPort 用于扫描 GFM 自动链接时判断是否处于端口部分。
+//
+// 已扫描部分 url 为 tokens[start:end]，处于端口部分是指 url 去掉 :// 后包含 : 并且第一个 : 后不包含 /。
+// 通过增量维护第一个冒号和最后一个斜杠的位置，避免每扫描一个字符都重新处理整个 url。
+type autoLinkPort struct {
+	tokens    []byte
+	start     int // url 起始下标
+	colon     int // url 中第一个不属于 :// 的冒号下标，尚未找到时为下一个待检查的下标
+	fixed     int // 下一个待确定是否属于 :// 的斜杠下标
+	lastSlash int // 已经确定不属于 :// 的最后一个斜杠下标
+}
+
+// inPort 判断 url 为 tokens[start:end] 时是否处于端口部分。
+func (p *autoLinkPort) inPort(end int) bool {
+	for ; p.colon < end; p.colon++ {
+		if lex.ItemColon == p.tokens[p.colon] && !p.schemeSeparator(p.colon, end) {
+			break
+		}
+	}
+	if p.colon >= end {
+		return false
+	}
+
+	// end-2 及之前的斜杠是否属于 :// 已经可以确定
+	for ; p.fixed <= end-2; p.fixed++ {
+		if lex.ItemSlash == p.tokens[p.fixed] && !p.schemeSeparator(p.fixed-1, end) && !p.schemeSeparator(p.fixed-2, end) {
+			p.lastSlash = p.fixed
+		}
+	}
+	if p.lastSlash > p.colon {
+		return false
+	}
+	last := end - 1
+	return last <= p.colon || lex.ItemSlash != p.tokens[last] || p.schemeSeparator(last-2, end)
+}
+
+// schemeSeparator 判断 tokens[k:k+3] 是否是完整位于 tokens[start:end] 中的 ://。
+func (p *autoLinkPort) schemeSeparator(k, end int) bool {
+	return p.start <= k && k+3 <= end && lex.ItemColon == p.tokens[k] && lex.ItemSlash == p.tokens[k+1] && lex.ItemSlash == p.tokens[k+2]
+}
+
 func (t *Tree) addPreviousText(node *ast.Node, tokens []byte) {
 	if nil == node.Previous || ast.NodeText != node.Previous.Type {
 		node.InsertBefore(&ast.Node{Type: ast.NodeText, Tokens: tokens})
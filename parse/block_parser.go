@@ -0,0 +1,202 @@
+package parse
+
+import (
+	"errors"
+
+	"github.com/pafthang/md/ast"
+)
+
+// BlockParser 描述了用户自定义的块级解析器，用于在不修改解析器的情况下支持新的块级语法。
+//
+// 自定义块级节点需要配合渲染器的 ExtRendererFuncs 进行渲染。
+type BlockParser struct {
+	NodeType      ast.NodeType      // 自定义节点类型，必须大于 ast.NodeTypeMaxVal
+	Name          string            // 节点类型名称，比如 NodeAdmonition
+	Container     bool              // 是否为容器块（可包含其他块级节点），否则为叶子块
+	AcceptLines   bool              // 叶子块是否接受后续文本行，文本行会追加到节点 Tokens 上
+	InlineContent bool              // 叶子块的 Tokens 是否作为行级内容继续解析
+	Triggers      []byte            // 可能起始该块的首个非空字节，为空时会对所有行尝试 Start
+	Start         BlockStartFunc    // 判断块是否开始，必须设置
+	Continue      BlockContinueFunc // 判断块是否可以继续，为空时容器块总是继续，叶子块不再继续
+	Finalize      BlockFinalizeFunc // 块最终化处理，可以为空
+}
+
+// BlockContinueFunc 定义了用于判断块是否可以继续的函数签名，返回值：
+//
+//	0：可以继续
+//	1：不能继续
+//	2：块已闭合（需要先调用 context.Finalize），处理下一行
+type BlockContinueFunc func(n *ast.Node, context *Context) int
+
+// BlockFinalizeFunc 定义了块最终化处理函数签名。
+type BlockFinalizeFunc func(n *ast.Node, context *Context)
+
+// RegisterBlockParser 注册自定义块级解析器，注册后的解析器会在内置解析器之前尝试匹配。
+//
+// 注册仅对使用该解析选项的解析生效。自定义块级节点需要通过 Context.AddChild 构造，构造时节点特性（是否为块级、容器块、接受文本行）
+// 会记录在 Node.TypeFlags 上，节点类型名称可以通过 Options.NodeTypeName 获取。
+func (options *Options) RegisterBlockParser(parser *BlockParser) error {
+	if nil == parser.Start {
+		return errors.New("block parser start func is nil [name=" + parser.Name + "]")
+	}
+	if ast.NodeTypeMaxVal >= parser.NodeType {
+		return errors.New("block parser node type must be greater than NodeTypeMaxVal [name=" + parser.Name + "]")
+	}
+
+	for i, p := range options.BlockParsers {
+		if p.NodeType == parser.NodeType {
+			options.BlockParsers[i] = parser
+			return nil
+		}
+	}
+	options.BlockParsers = append(options.BlockParsers, parser)
+	return nil
+}
+
+// blockParser 返回 nodeType 对应的自定义块级解析器，没有注册的话返回 nil。
+func (options *Options) blockParser(nodeType ast.NodeType) *BlockParser {
+	if ast.NodeTypeMaxVal >= nodeType {
+		return nil
+	}
+	for _, p := range options.BlockParsers {
+		if p.NodeType == nodeType {
+			return p
+		}
+	}
+	return nil
+}
+
+// NodeTypeName 返回节点类型 typ 的名称，自定义节点类型使用注册的块级或者行级解析器名称，未注册时返回 typ.String()。
+func (options *Options) NodeTypeName(typ ast.NodeType) string {
+	if ast.NodeTypeMaxVal >= typ {
+		return typ.String()
+	}
+	if p := options.blockParser(typ); nil != p && "" != p.Name {
+		return p.Name
+	}
+	for _, p := range options.InlineParsers {
+		if p.NodeType == typ && "" != p.Name {
+			return p.Name
+		}
+	}
+	return typ.String()
+}
+
+// typeFlags 返回解析器 parser 构造的节点的类型特性。
+func (parser *BlockParser) typeFlags() (ret ast.NodeTypeFlag) {
+	ret = ast.NodeTypeFlagBlock
+	if parser.Container {
+		ret |= ast.NodeTypeFlagContainer
+	} else if parser.AcceptLines {
+		ret |= ast.NodeTypeFlagAcceptLines
+	}
+	return
+}
+
+// initBlockStarts 合并自定义块级解析器和内置解析器的起始函数，自定义块级解析器优先于内置解析器。
+func (t *Tree) initBlockStarts() {
+	t.blockStarts = blockStarts()
+	customParsers := t.Context.ParseOption.BlockParsers
+	if 1 > len(customParsers) {
+		return
+	}
+
+	starts := make([]BlockStartFunc, 0, len(customParsers)+len(t.blockStarts))
+	for _, p := range customParsers {
+		starts = append(starts, p.Start)
+	}
+	t.blockStarts = append(starts, t.blockStarts...)
+}
+
+// maybeCustomBlockStart 判断 marker 是否可能起始某个自定义块。
+func (options *Options) maybeCustomBlockStart(marker byte) bool {
+	for _, p := range options.BlockParsers {
+		if 1 > len(p.Triggers) {
+			return true
+		}
+		for _, trigger := range p.Triggers {
+			if trigger == marker {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func customBlockContinue(n *ast.Node, context *Context) int {
+	p := context.ParseOption.blockParser(n.Type)
+	if nil == p {
+		return 0
+	}
+	if nil != p.Continue {
+		return p.Continue(n, context)
+	}
+	if p.Container {
+		return 0
+	}
+	return 1
+}
+
+// 以下方法用于自定义块级解析器访问和推进块级解析上下文。
+
+// CurrentLine 返回当前正在解析的文本行（包含结尾的换行符）。
+func (context *Context) CurrentLine() []byte {
+	return context.currentLine
+}
+
+// Offset 返回当前行已经处理到的下标。
+func (context *Context) Offset() int {
+	return context.offset
+}
+
+// NextNonspace 返回当前行下一个非空字符的下标。
+func (context *Context) NextNonspace() int {
+	return context.nextNonspace
+}
+
+// Indent 返回当前行下一个非空字符前的缩进空格数。
+func (context *Context) Indent() int {
+	return context.indent
+}
+
+// Indented 判断当前行是否为缩进行（缩进至少 4 个空格）。
+func (context *Context) Indented() bool {
+	return context.indented
+}
+
+// Blank 判断当前行是否为空行。
+func (context *Context) Blank() bool {
+	return context.blank
+}
+
+// AdvanceOffset 在当前行上移动 count 个字符位置，columns 指定了遇到 tab 时是否需要空格进行补偿偏移。
+func (context *Context) AdvanceOffset(count int, columns bool) {
+	context.advanceOffset(count, columns)
+}
+
+// AdvanceNextNonspace 移动到当前行下一个非空字符位置。
+func (context *Context) AdvanceNextNonspace() {
+	context.advanceNextNonspace()
+}
+
+// AdvanceLine 移动到当前行结尾的换行符，即整行内容都已经被处理。
+func (context *Context) AdvanceLine() {
+	if remains := context.currentLineLen - 1 - context.offset; 0 < remains {
+		context.advanceOffset(remains, false)
+	}
+}
+
+// CloseUnmatchedBlocks 最终化所有未匹配的块节点，起始新块前需要调用。
+func (context *Context) CloseUnmatchedBlocks() {
+	context.closeUnmatchedBlocks()
+}
+
+// AddChild 构造一个 nodeType 节点并作为子节点添加到末梢节点上，添加完成后该节点会被设置为新的末梢节点。
+func (context *Context) AddChild(nodeType ast.NodeType) *ast.Node {
+	return context.addChild(nodeType)
+}
+
+// Finalize 执行块节点 block 的最终化处理，并将末梢节点置为 block 的父节点。
+func (context *Context) Finalize(block *ast.Node) {
+	context.finalize(block)
+}
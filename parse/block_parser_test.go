@@ -0,0 +1,74 @@
+package parse_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pafthang/md/ast"
+	"github.com/pafthang/md/parse"
+	"github.com/pafthang/md/render"
+)
+
+// percentQuote 为测试用的自定义容器块，每行以 % 开头，类似块引用。
+const percentQuote = ast.NodeTypeMaxVal + 100
+
+func percentMarker(context *parse.Context) bool {
+	line := context.CurrentLine()
+	return !context.Indented() && '%' == line[context.NextNonspace()]
+}
+
+func TestRegisterBlockParser(t *testing.T) {
+	options := parse.NewOptions()
+	err := options.RegisterBlockParser(&parse.BlockParser{
+		NodeType:  percentQuote,
+		Name:      "NodePercentQuote",
+		Container: true,
+		Triggers:  []byte{'%'},
+		Start: func(t *parse.Tree, container *ast.Node) int {
+			if !percentMarker(t.Context) {
+				return 0
+			}
+			t.Context.AdvanceNextNonspace()
+			t.Context.AdvanceOffset(1, false)
+			t.Context.CloseUnmatchedBlocks()
+			t.Context.AddChild(percentQuote)
+			return 1
+		},
+		Continue: func(n *ast.Node, context *parse.Context) int {
+			if !percentMarker(context) {
+				return 1
+			}
+			context.AdvanceNextNonspace()
+			context.AdvanceOffset(1, false)
+			return 0
+		},
+	})
+	if nil != err {
+		t.Fatalf("register block parser failed: %s", err)
+	}
+
+	tree := parse.Parse("", []byte("% a\n% - b\n\nc\n"), options)
+	quote := tree.Root.FirstChild
+	if percentQuote != quote.Type || !quote.IsContainerBlock() || ast.NodeParagraph != quote.FirstChild.Type || ast.NodeList != quote.LastChild.Type {
+		t.Fatalf("unexpected custom block [%s]", quote.Type)
+	}
+	if ast.NodeParagraph != quote.Next.Type || nil != quote.Next.Next {
+		t.Fatalf("unexpected sibling [%s]", quote.Next.Type)
+	}
+
+	if name := options.NodeTypeName(percentQuote); "NodePercentQuote" != name {
+		t.Fatalf("unexpected custom node type name [%s]", name)
+	}
+	if json := string(render.NewJSONRenderer(tree, render.NewOptions()).Render()); !strings.Contains(json, `"Type":"NodePercentQuote"`) {
+		t.Fatalf("custom node type name is not rendered [%s]", json)
+	}
+
+	// 注册不影响其他解析选项
+	other := parse.NewOptions()
+	if tree := parse.Parse("", []byte("% a\n"), other); ast.NodeParagraph != tree.Root.FirstChild.Type {
+		t.Fatalf("custom block parser leaked into other options")
+	}
+	if n := (&ast.Node{Type: percentQuote}); n.IsBlock() || percentQuote.String() != other.NodeTypeName(percentQuote) {
+		t.Fatalf("custom node type leaked into other options")
+	}
+}
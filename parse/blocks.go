@@ -12,8 +12,19 @@ import (
 // parseBlocks 解析并生成块级节点。
 func (t *Tree) parseBlocks() {
 	t.Context.Tip = t.Root
+	if t.Context.ParseOption.SourcePos {
+		t.Root.SourceStart = &ast.Pos{Line: 1, Column: 1}
+		t.Root.SourceEnd = &ast.Pos{Line: 1, Column: 1}
+	}
 	lines := 0
+	var rest []byte // 解析被取消后剩余的内容
 	for line := t.lexer.NextLine(); nil != line; line = t.lexer.NextLine() {
+		if t.Context.canceled() {
+			rest = append(rest, line...)
+			continue
+		}
+
+		t.Context.lineNum, t.Context.lineOffset = t.lexer.Line(), t.lexer.LineOffset()
 		if t.Context.ParseOption.EditorWYSIWYG || t.Context.ParseOption.EditorIR || t.Context.ParseOption.EditorSV || t.Context.ParseOption.ProtyleWYSIWYG {
 			if !bytes.Equal(line, editor.CaretNewlineTokens) && t.Context.Tip.ParentIs(ast.NodeListItem) && bytes.HasPrefix(line, editor.CaretTokens) {
 				// 插入符在开头的话移动到上一行结尾，处理 https://github.com/Vanessa219/editor/issues/633 中的一些情况
@@ -25,6 +36,7 @@ func (t *Tree) parseBlocks() {
 					t.Context.Tip.Tokens = append(t.Context.Tip.Tokens, editor.CaretNewlineTokens...)
 				}
 				line = line[len(editor.CaretTokens):]
+				t.Context.lineOffset += len(editor.CaretTokens)
 			}
 		}
 
@@ -34,6 +46,7 @@ func (t *Tree) parseBlocks() {
 	for nil != t.Context.Tip {
 		t.Context.finalize(t.Context.Tip)
 	}
+	t.appendTextBlock(rest)
 }
 
 func (t *Tree) BlockCount() (ret int) {
@@ -93,6 +106,7 @@ func (t *Tree) incorporateLine(line []byte) {
 			allMatched = false
 			break
 		case 2: // 匹配围栏代码块闭合，处理下一行
+			t.Context.markSourceEnd(container)
 			return
 		case 3: // 匹配超级块闭合，处理下一行
 			t.Context.closeSuperBlockChildren() // 闭合超级块下的子节点
@@ -102,7 +116,9 @@ func (t *Tree) incorporateLine(line []byte) {
 				sb.AppendChild(&ast.Node{Type: ast.NodeSuperBlockCloseMarker})
 				t.Context.Tip = sb.Parent
 				t.Context.lastMatchedContainer = sb
+				t.Context.markSourceEnd(sb)
 			} else {
+				t.Context.markSourceEnd(t.Context.Tip)
 				t.Context.Tip.AppendChild(&ast.Node{Type: ast.NodeSuperBlockCloseMarker})
 				t.Context.Tip.Close = true
 				t.Context.Tip = t.Context.Tip.Parent
@@ -121,13 +137,22 @@ func (t *Tree) incorporateLine(line []byte) {
 	t.Context.lastMatchedContainer = container
 
 	matchedLeaf := container.Type != ast.NodeParagraph && container.AcceptLines()
-	blockParsers := blockStarts()
+	if nil == t.blockStarts {
+		t.initBlockStarts()
+	}
+	blockParsers := t.blockStarts
 	startsLen := len(blockParsers)
 
 	// 除非最后一个匹配到的是代码块，否则的话就起始一个新的块级节点
 	for !matchedLeaf {
 		t.Context.findNextNonspace()
 
+		if t.Context.blockDepthExceeded(container) {
+			// 超出嵌套深度限制，余下的内容作为段落文本
+			t.Context.advanceNextNonspace()
+			break
+		}
+
 		// 如果不由潜在的节点标记符开头 ^[#`~*+_=<>0-9-${]，则说明不用继续迭代生成子节点
 		// 这里仅做简单判断的话可以提升一些性能
 		maybeMarker := t.Context.currentLine[t.Context.nextNonspace]
@@ -136,6 +161,7 @@ func (t *Tree) incorporateLine(line []byte) {
 			!lex.IsDigit(maybeMarker) && // 有序列表
 			lex.ItemBacktick != maybeMarker && lex.ItemTilde != maybeMarker && // 代码块
 			lex.ItemSemicolon != maybeMarker && // 定义块
+			lex.ItemColon != maybeMarker && // 提示块
 			lex.ItemCrosshatch != maybeMarker && // ATX 标题
 			lex.ItemGreater != maybeMarker && // 块引用
 			lex.ItemLess != maybeMarker && // HTML 块
@@ -145,12 +171,14 @@ func (t *Tree) incorporateLine(line []byte) {
 			lex.ItemOpenBrace != maybeMarker && // kramdown 内联属性列表或超级块开始
 			lex.ItemCloseBrace != maybeMarker && // 超级块闭合
 			lex.ItemBang != maybeMarker && "！"[0] != maybeMarker && // 内容块嵌入
-			editor.Caret[0] != maybeMarker { // Editor 编辑器支持
+			editor.Caret[0] != maybeMarker && // Editor 编辑器支持
+			!t.Context.ParseOption.maybeCustomBlockStart(maybeMarker) { // 自定义块
 			t.Context.advanceNextNonspace()
 			break
 		}
 
 		// 逐个尝试是否可以起始一个块级节点
+		t.Context.blockStartOffset = t.Context.nextNonspace
 		i := 0
 		for i < startsLen {
 			res := blockParsers[i](t, container)
@@ -212,6 +240,7 @@ func (t *Tree) incorporateLine(line []byte) {
 				if html.HtmlBlockType >= 1 && html.HtmlBlockType <= 5 {
 					tokens := t.Context.currentLine[t.Context.offset:]
 					if t.isHTMLBlockClose(tokens, html.HtmlBlockType) {
+						t.Context.markSourceEnd(container)
 						t.Context.finalize(container)
 					}
 				}
@@ -221,16 +250,23 @@ func (t *Tree) incorporateLine(line []byte) {
 					(bytes.HasSuffix(container.Tokens, MathBlockMarkerNewline) ||
 						bytes.HasSuffix(container.Tokens, MathBlockMarker) ||
 						bytes.HasSuffix(container.Tokens, MathBlockMarkerCaretNewline)) {
+					t.Context.markSourceEnd(container)
 					t.Context.finalize(container)
 				}
 			}
 		} else if t.Context.offset < t.Context.currentLineLen && !t.Context.blank {
 			// 普通段落开始
+			t.Context.blockStartOffset = t.Context.nextNonspace
 			t.Context.addChild(ast.NodeParagraph)
 			t.Context.advanceNextNonspace()
 			t.addLine()
 		}
 	}
+
+	if !t.Context.blank {
+		// 记录当前行所属的块节点的结束位置，当前行上已经闭合的块（比如被新段落结束的列表）不包含该行
+		t.Context.markSourceEnd(t.Context.Tip)
+	}
 }
 
 // addLine 用于在当前的末梢节点 context.Tip 上添加迭代行剩余的所有 Tokens。
@@ -246,8 +282,10 @@ func (t *Tree) addLine() {
 	startWithSpace := 1 < t.Context.currentLineLen && (' ' == t.Context.currentLine[0] || '\t' == t.Context.currentLine[0])
 	docChildPara := ast.NodeDocument == t.Context.Tip.Parent.Type
 	if t.Context.ParseOption.ParagraphBeginningSpace && startWithSpace && docChildPara {
+		t.Context.addSourceSegment(t.Context.Tip, len(t.Context.Tip.Tokens), 0)
 		t.Context.Tip.AppendTokens(t.Context.currentLine)
 	} else {
+		t.Context.addSourceSegment(t.Context.Tip, len(t.Context.Tip.Tokens), t.Context.offset)
 		t.Context.Tip.AppendTokens(t.Context.currentLine[t.Context.offset:])
 	}
 }
@@ -278,9 +316,15 @@ func _continue(n *ast.Node, context *Context) int {
 		return GitConflictContinue(n, context)
 	case ast.NodeCustomBlock:
 		return CustomBlockContinue(n, context)
+	case ast.NodeAdmonition:
+		return AdmonitionContinue(n, context)
 	case ast.NodeHeading, ast.NodeThematicBreak, ast.NodeKramdownBlockIAL, ast.NodeLinkRefDefBlock, ast.NodeBlockQueryEmbed,
 		ast.NodeIFrame, ast.NodeVideo, ast.NodeAudio, ast.NodeWidget, ast.NodeAttributeView:
 		return 1
+	default:
+		if ast.NodeTypeMaxVal < n.Type {
+			return customBlockContinue(n, context)
+		}
 	}
 	return 0
 }
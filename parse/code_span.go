@@ -104,9 +104,16 @@ func (t *Tree) parseCodeSpan(block *ast.Node, ctx *InlineContext) (ret *ast.Node
 		}
 	}
 
-	ret.AppendChild(&ast.Node{Type: ast.NodeCodeSpanContent, Tokens: textTokens})
+	content := &ast.Node{Type: ast.NodeCodeSpanContent, Tokens: textTokens}
+	ret.AppendChild(content)
 	ret.AppendChild(closeMarker)
 	ctx.pos = endPos + n
+	if t.Context.ParseOption.SourcePos {
+		contentStart, contentEnd := startPos+len(openMarker.Tokens), ctx.pos-len(closeMarker.Tokens)
+		t.markInlineSourcePos(openMarker, block, startPos, contentStart)
+		t.markInlineSourcePos(content, block, contentStart, contentEnd)
+		t.markInlineSourcePos(closeMarker, block, contentEnd, ctx.pos)
+	}
 	return
 }
 
@@ -0,0 +1,124 @@
+package parse
+
+import (
+	"bytes"
+
+	"github.com/pafthang/md/ast"
+	"github.com/pafthang/md/lex"
+)
+
+// definitionListFinalize 尝试将段落 p 转换为定义列表（Term\n: Definition），转换成功返回 true。
+//
+// 开头连续的非 : 开头的行作为术语，随后以 : 开头的每一行作为一个描述，描述之后非 : 开头的行作为该描述的续行。
+// 新的术语需要使用空行分隔，空行分隔的紧邻定义列表会被合并，紧跟在定义列表之后的以 : 开头的段落作为该定义列表的描述。
+// 只有 : 的行是内容为空的描述，用于描述的内容全部是缩进的续块的情况，参考 definitionDescriptionBlocks。
+func (context *Context) definitionListFinalize(p *ast.Node) bool {
+	tokens := p.Tokens
+	if 1 > len(tokens) {
+		return false
+	}
+	if lex.ItemColon == tokens[0] && (nil == p.Previous || ast.NodeDefinitionList != p.Previous.Type) {
+		return false
+	}
+
+	type line struct {
+		start, end int  // 内容在 p.Tokens 中的起止偏移量
+		desc       bool // 是否为描述行
+	}
+	var lines []line
+	hasDesc := false
+	for start := 0; start < len(tokens); {
+		end := bytes.IndexByte(tokens[start:], lex.ItemNewline)
+		if 0 > end {
+			end = len(tokens)
+		} else {
+			end += start
+		}
+		l := line{start: start, end: end}
+		if content := tokens[start:end]; (1 < len(content) && lex.ItemColon == content[0] && lex.IsWhitespace(content[1])) || (1 == len(content) && lex.ItemColon == content[0]) {
+			l.desc = true
+			for l.start++; l.start < l.end && lex.IsWhitespace(tokens[l.start]); l.start++ {
+			}
+			hasDesc = true
+		}
+		lines = append(lines, l)
+		start = end + 1
+	}
+	if !hasDesc {
+		return false
+	}
+
+	var items []*ast.Node
+	var last *ast.Node
+	var lastStart int
+	for _, l := range lines {
+		typ := ast.NodeDefinitionTerm
+		if l.desc {
+			typ = ast.NodeDefinitionDescription
+		} else if nil != last && ast.NodeDefinitionDescription == last.Type {
+			// 描述的续行
+			last.Tokens = tokens[lastStart:l.end]
+			continue
+		}
+
+		last, lastStart = &ast.Node{Type: typ, Tokens: tokens[l.start:l.end], Close: true}, l.start
+		context.sliceSourceSegments(p, last, l.start)
+		items = append(items, last)
+	}
+
+	if context.ParseOption.SourcePos {
+		for _, item := range items {
+			item.SourceStart = context.sourcePos(item, 0)
+			item.SourceEnd = context.sourcePos(item, len(item.Tokens))
+		}
+	}
+
+	list := p
+	if prev := p.Previous; nil != prev && ast.NodeDefinitionList == prev.Type {
+		// 合并紧邻的定义列表
+		list = prev
+		if nil != p.SourceEnd {
+			list.SourceEnd = p.SourceEnd
+		}
+		p.Unlink()
+	} else {
+		p.Type = ast.NodeDefinitionList
+		p.Tokens = nil
+	}
+	for _, item := range items {
+		list.AppendChild(item)
+	}
+	return true
+}
+
+// definitionDescriptionBlocks 尝试将紧跟在定义列表之后的缩进代码块 codeBlock 解析为定义列表最后一个描述的续块，成功返回 true。
+//
+// 描述中的后续段落、列表等块级内容使用空行分隔并缩进四个空格，和 pandoc 一致。描述原有的行级内容会转换为第一个段落。
+func (context *Context) definitionDescriptionBlocks(codeBlock *ast.Node) bool {
+	list := codeBlock.Previous
+	if nil == list || ast.NodeDefinitionList != list.Type || nil == list.LastChild || ast.NodeDefinitionDescription != list.LastChild.Type {
+		return false
+	}
+
+	options := *context.ParseOption
+	options.SourcePos = false // 续块内容已经去掉了缩进，无法对应到原始输入中的位置
+	tree := &Tree{Name: context.Tree.Name, Context: &Context{ParseOption: &options, cancelCtx: context.cancelCtx}}
+	tree.Context.Tree = tree
+	tree.lexer = lex.NewLexer(codeBlock.Tokens)
+	tree.Root = &ast.Node{Type: ast.NodeDocument}
+	tree.parseBlocks()
+
+	desc := list.LastChild
+	if nil == desc.FirstChild && 0 < len(desc.Tokens) {
+		desc.AppendChild(&ast.Node{Type: ast.NodeParagraph, Tokens: desc.Tokens, Close: true})
+		desc.Tokens = nil
+	}
+	for n := tree.Root.FirstChild; nil != n; n = tree.Root.FirstChild {
+		desc.AppendChild(n)
+	}
+	if nil != codeBlock.SourceEnd {
+		desc.SourceEnd, list.SourceEnd = codeBlock.SourceEnd, codeBlock.SourceEnd
+	}
+	codeBlock.Unlink()
+	return true
+}
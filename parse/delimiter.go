@@ -35,10 +35,11 @@ func (t *Tree) handleDelim(block *ast.Node, ctx *InlineContext) {
 
 	text := ctx.tokens[startPos:ctx.pos]
 	node := &ast.Node{Type: ast.NodeText, Tokens: text}
+	t.markInlineSourcePos(node, block, startPos, ctx.pos)
 	block.AppendChild(node)
 
 	// 将这个分隔符入栈
-	if delim.canOpen || delim.canClose {
+	if (delim.canOpen || delim.canClose) && !t.Context.inlineDelimitersExceeded(ctx) {
 		ctx.delimiters = &delimiter{
 			typ:         delim.typ,
 			num:         delim.num,
@@ -170,6 +171,14 @@ func (t *Tree) processEmphasis(stackBottom *delimiter, ctx *InlineContext) {
 			openMarker := &ast.Node{Tokens: openerTokens, Close: true}
 			emStrongDelMark := &ast.Node{Close: true}
 			closeMarker := &ast.Node{Tokens: closerTokens, Close: true}
+			if nil != openerInl.SourceEnd && nil != closerInl.SourceStart {
+				// 开始标记符取自开始分隔符的尾部，结束标记符取自结束分隔符的头部
+				openMarker.SourceStart, openMarker.SourceEnd = openerInl.SourceEnd.Advance(-useDelims), openerInl.SourceEnd
+				openerInl.SourceEnd = openMarker.SourceStart
+				closeMarker.SourceStart, closeMarker.SourceEnd = closerInl.SourceStart, closerInl.SourceStart.Advance(useDelims)
+				closerInl.SourceStart = closeMarker.SourceEnd
+				emStrongDelMark.SourceStart, emStrongDelMark.SourceEnd = openMarker.SourceStart, closeMarker.SourceEnd
+			}
 			if 1 == useDelims {
 				if lex.ItemAsterisk == closercc {
 					emStrongDelMark.Type = ast.NodeEmphasis
@@ -70,9 +70,7 @@ func (t *Tree) emoji0(node *ast.Node) {
 			continue
 		}
 
-		EmojiLock.Lock()
 		emoji, ok := t.Context.ParseOption.AliasEmoji[util.BytesToStr(maybeEmoji)]
-		EmojiLock.Unlock()
 		if ok {
 			emojiNode := &ast.Node{Type: ast.NodeEmoji}
 			emojiUnicodeOrImg := &ast.Node{Type: ast.NodeEmojiUnicode}
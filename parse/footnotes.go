@@ -74,10 +74,20 @@ func (t *Tree) FindFootnotesDef(label []byte) (pos int, def *ast.Node) {
 	if t.Context.ParseOption.EditorIR || t.Context.ParseOption.EditorSV || t.Context.ParseOption.EditorWYSIWYG || t.Context.ParseOption.ProtyleWYSIWYG {
 		label = bytes.ReplaceAll(label, editor.CaretTokens, nil)
 	}
+	for _, n := range t.Context.footnotesDefs {
+		pos++
+		if bytes.EqualFold(n.Tokens, label) {
+			def = n
+			return
+		}
+	}
 	ast.Walk(t.Root, func(n *ast.Node, entering bool) ast.WalkStatus {
-		if !entering || ast.NodeFootnotesDef != n.Type {
+		if !entering {
 			return ast.WalkContinue
 		}
+		if ast.NodeFootnotesDef != n.Type {
+			return skipDefSearch(n)
+		}
 		pos++
 		if bytes.EqualFold(n.Tokens, label) {
 			def = n
@@ -87,3 +97,12 @@ func (t *Tree) FindFootnotesDef(label []byte) (pos int, def *ast.Node) {
 	})
 	return
 }
+
+// skipDefSearch 返回查找链接引用定义和脚注定义时对不匹配节点 n 的遍历方式，定义只会出现在容器块和链接引用定义块中，
+// 其他节点的子节点都会被跳过，避免行级解析时每次查找都遍历当前块中已经生成的行级节点。
+func skipDefSearch(n *ast.Node) ast.WalkStatus {
+	if n.IsContainerBlock() || ast.NodeLinkRefDefBlock == n.Type {
+		return ast.WalkContinue
+	}
+	return ast.WalkSkipChildren
+}
@@ -16,13 +16,20 @@ func ATXHeadingStart(t *Tree, container *ast.Node) int {
 	}
 
 	if ok, markers, content, level := t.parseATXHeading(); ok {
+		contentOffset := t.Context.nextNonspace + level
+		for ; contentOffset < t.Context.currentLineLen && lex.IsWhitespace(t.Context.currentLine[contentOffset]) && lex.ItemNewline != t.Context.currentLine[contentOffset]; contentOffset++ {
+		}
 		t.Context.advanceNextNonspace()
 		t.Context.advanceOffset(len(content), false)
 		t.Context.closeUnmatchedBlocks()
 		heading := t.Context.addChild(ast.NodeHeading)
 		heading.HeadingLevel = level
 		heading.Tokens = content
+		t.Context.addSourceSegment(heading, 0, contentOffset)
 		crosshatchMarker := &ast.Node{Type: ast.NodeHeadingC8hMarker, Tokens: markers}
+		if t.Context.ParseOption.SourcePos {
+			crosshatchMarker.SourceStart, crosshatchMarker.SourceEnd = heading.SourceStart, heading.SourceStart.Advance(level)
+		}
 		heading.AppendChild(crosshatchMarker)
 		t.Context.advanceOffset(t.Context.currentLineLen-t.Context.offset, false)
 		return 2
@@ -69,6 +76,7 @@ func SetextHeadingStart(t *Tree, container *ast.Node) int {
 	// 解析链接引用定义
 	for tokens := container.Tokens; 0 < len(tokens) && lex.ItemOpenBracket == tokens[0]; tokens = container.Tokens {
 		if remains := t.Context.parseLinkRefDef(tokens); nil != remains {
+			t.Context.shiftSourceSegments(container, len(container.Tokens)-len(remains))
 			container.Tokens = remains
 		} else {
 			break
@@ -77,7 +85,13 @@ func SetextHeadingStart(t *Tree, container *ast.Node) int {
 
 	if 0 < len(container.Tokens) {
 		child := &ast.Node{Type: ast.NodeHeading, HeadingLevel: level, HeadingSetext: true}
+		leftWhitespaces, _ := lex.TrimLeft(container.Tokens)
+		t.Context.shiftSourceSegments(container, len(leftWhitespaces))
 		child.Tokens = lex.TrimWhitespace(container.Tokens)
+		if t.Context.ParseOption.SourcePos {
+			child.SourceStart = t.Context.sourcePos(container, 0)
+			t.Context.moveSourceSegments(container, child)
+		}
 		container.InsertAfter(child)
 		container.Unlink()
 		t.Context.Tip = child
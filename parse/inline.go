@@ -13,9 +13,26 @@ import (
 
 // parseInline 解析并生成块节点 block 的行级子节点。
 func (t *Tree) parseInline(block *ast.Node, ctx *InlineContext) {
-	for ctx.pos < ctx.tokensLen {
+	for steps := 0; ctx.pos < ctx.tokensLen; steps++ {
+		if 0 == steps%1024 && t.Context.canceled() {
+			// 解析被取消，剩余内容作为文本
+			block.AppendChild(&ast.Node{Type: ast.NodeText, Tokens: ctx.tokens[ctx.pos:]})
+			ctx.pos = ctx.tokensLen
+			break
+		}
+
+		start := ctx.pos
 		token := ctx.tokens[ctx.pos]
 		var n *ast.Node
+		customTrigger := t.isInlineTrigger(token)
+		if customTrigger {
+			if n = t.parseCustomInline(block, ctx, token, true); nil != n {
+				t.markInlineSourcePos(n, block, start, ctx.pos)
+				block.AppendChild(n)
+				continue
+			}
+		}
+		brackets, delimiters := ctx.brackets, ctx.delimiters
 		switch token {
 		case lex.ItemBackslash:
 			n = t.parseBackslash(block, ctx)
@@ -44,13 +61,17 @@ func (t *Tree) parseInline(block *ast.Node, ctx *InlineContext) {
 				}
 			}
 		case lex.ItemOpenBracket:
-			n = t.parseOpenBracket(ctx)
+			if n = t.parseWikiLink(ctx); nil == n {
+				n = t.parseOpenBracket(ctx)
+			}
 		case lex.ItemCloseBracket:
-			n = t.parseCloseBracket(ctx)
+			n = t.parseCloseBracket(block, ctx)
 		case lex.ItemAmpersand:
 			n = t.parseEntity(ctx)
 		case lex.ItemBang:
-			n = t.parseBang(ctx)
+			if n = t.parseWikiLink(ctx); nil == n {
+				n = t.parseBang(ctx)
+			}
 		case lex.ItemDollar:
 			n = t.parseInlineMath(ctx)
 		case lex.ItemOpenBrace:
@@ -61,7 +82,19 @@ func (t *Tree) parseInline(block *ast.Node, ctx *InlineContext) {
 			n = t.parseText(ctx)
 		}
 
+		if customTrigger && nil != n && ast.NodeText == n.Type && brackets == ctx.brackets && delimiters == ctx.delimiters {
+			// 内置解析器将触发字节解析为普通文本时再尝试低优先级的自定义解析器
+			end := ctx.pos
+			ctx.pos = start
+			if custom := t.parseCustomInline(block, ctx, token, false); nil != custom {
+				n = custom
+			} else {
+				ctx.pos = end
+			}
+		}
+
 		if nil != n {
+			t.markInlineSourcePos(n, block, start, ctx.pos)
 			block.AppendChild(n)
 		}
 	}
@@ -125,7 +158,7 @@ func (t *Tree) parseEntity(ctx *InlineContext) (ret *ast.Node) {
 
 // Try to match close bracket against an opening in the delimiter stack. Add either a link or image, or a plain [ character,
 // to block's children. If there is a matching delimiter, remove it from the delimiter stack.
-func (t *Tree) parseCloseBracket(ctx *InlineContext) *ast.Node {
+func (t *Tree) parseCloseBracket(block *ast.Node, ctx *InlineContext) *ast.Node {
 	closeBracket := []byte{ctx.tokens[ctx.pos]}
 	ctx.pos++
 	startPos := ctx.pos
@@ -146,6 +179,7 @@ func (t *Tree) parseCloseBracket(ctx *InlineContext) *ast.Node {
 	// 检查是否满足链接或者图片规则
 
 	var openParen, dest, space, title, closeParen []byte
+	var destStart, destEnd, titleEnd, closeParenStart int // 行内链接各部分在 ctx.tokens 中的偏移量，用于记录源码位置
 	savepos := ctx.pos
 	matched := false
 	// 尝试解析内联链接 [text](url "tile")
@@ -159,9 +193,11 @@ func (t *Tree) parseCloseBracket(ctx *InlineContext) *ast.Node {
 				break
 			}
 			ctx.pos += len(passed)
-			if passed, remains, dest = t.Context.parseInlineLinkDest(remains); nil == passed {
+			if passed, remains, dest = t.Context.parseInlineLinkDest(ctx, remains); nil == passed {
 				break
 			}
+			whitespace, _ := lex.TrimLeft(passed[1:])
+			destStart, destEnd = savepos+1+len(whitespace), savepos+len(passed)
 			if t.Context.ParseOption.EditorWYSIWYG || t.Context.ParseOption.EditorIR || t.Context.ParseOption.EditorSV || t.Context.ParseOption.ProtyleWYSIWYG {
 				if !isImage && nil == opener.node.Next {
 					break
@@ -172,6 +208,8 @@ func (t *Tree) parseCloseBracket(ctx *InlineContext) *ast.Node {
 			closeParen = passed[len(passed)-1:]
 			matched = lex.ItemCloseParen == passed[len(passed)-1]
 			if matched {
+				destEnd--
+				closeParenStart = destEnd
 				ctx.pos--
 				break
 			}
@@ -186,6 +224,7 @@ func (t *Tree) parseCloseBracket(ctx *InlineContext) *ast.Node {
 			ctx.pos += len(passed)
 			matched = lex.ItemCloseParen == remains[0]
 			closeParen = remains[0:1]
+			closeParenStart = destEnd + len(space)
 			if matched {
 				break
 			}
@@ -194,9 +233,11 @@ func (t *Tree) parseCloseBracket(ctx *InlineContext) *ast.Node {
 			if validTitle, passed, remains, title = t.Context.parseLinkTitle(remains); !validTitle {
 				break
 			}
+			titleEnd = closeParenStart + 1 + len(passed)
 			ctx.pos += len(passed)
 			isLink, passed, remains = lex.Spnl(remains)
 			ctx.pos += len(passed)
+			closeParenStart = titleEnd + len(passed)
 			matched = isLink && 0 < len(remains)
 			if matched {
 				if t.Context.ParseOption.EditorWYSIWYG || t.Context.ParseOption.EditorIR || t.Context.ParseOption.EditorSV || t.Context.ParseOption.ProtyleWYSIWYG {
@@ -271,7 +312,7 @@ func (t *Tree) parseCloseBracket(ctx *InlineContext) *ast.Node {
 					if 0 < refsLen {
 						refId += ":" + strconv.Itoa(refsLen+1)
 					}
-					ref := &ast.Node{Type: ast.NodeFootnotesRef, Tokens: reflabel, FootnotesRefId: refId, FootnotesRefLabel: bytes.ReplaceAll(reflabel, editor.CaretTokens, nil)}
+					ref := &ast.Node{Type: ast.NodeFootnotesRef, Tokens: reflabel, FootnotesRefId: refId, FootnotesRefLabel: bytes.ReplaceAll(reflabel, editor.CaretTokens, nil), SourceStart: opener.node.SourceStart}
 					footnotesDef.FootnotesRefs = append(footnotesDef.FootnotesRefs, ref)
 					return ref
 				}
@@ -291,7 +332,7 @@ func (t *Tree) parseCloseBracket(ctx *InlineContext) *ast.Node {
 	}
 
 	if matched {
-		node := &ast.Node{Type: ast.NodeLink, LinkType: linkType, LinkRefLabel: reflabel}
+		node := &ast.Node{Type: ast.NodeLink, LinkType: linkType, LinkRefLabel: reflabel, SourceStart: opener.node.SourceStart}
 		if isImage {
 			node.Type = ast.NodeImage
 			node.AppendChild(&ast.Node{Type: ast.NodeBang, Tokens: opener.node.Tokens[:1]})
@@ -320,6 +361,13 @@ func (t *Tree) parseCloseBracket(ctx *InlineContext) *ast.Node {
 			node.AppendChild(&ast.Node{Type: ast.NodeLinkTitle, Tokens: title})
 		}
 		node.AppendChild(&ast.Node{Type: ast.NodeCloseParen, Tokens: closeParen})
+		if t.Context.ParseOption.SourcePos {
+			openBracket := opener.index // 图片的 opener.index 为 ![ 之后的位置
+			if isImage {
+				openBracket--
+			}
+			t.markLinkSourcePos(node, block, openBracket, startPos-1, destStart, destEnd, titleEnd, closeParenStart)
+		}
 		t.processEmphasis(opener.previousDelimiter, ctx)
 		t.removeBracket(ctx)
 		opener.node.Unlink()
@@ -355,6 +403,10 @@ func (t *Tree) parseOpenBracket(ctx *InlineContext) (ret *ast.Node) {
 }
 
 func (t *Tree) addBracket(node *ast.Node, index int, image bool, ctx *InlineContext) {
+	if t.Context.inlineDelimitersExceeded(ctx) {
+		return
+	}
+
 	if nil != ctx.brackets {
 		ctx.brackets.bracketAfter = true
 	}
@@ -10,17 +10,26 @@ import (
 	"github.com/pafthang/md/util"
 )
 
-func (context *Context) parseInlineLinkDest(tokens []byte) (passed, remains, destination []byte) {
+// maxLinkDestParens 是链接地址中允许嵌套的括号层数，和 cmark 保持一致，用于避免 [a]( 重复出现时链接地址的扫描时间呈平方增长。
+const maxLinkDestParens = 32
+
+// parseInlineLinkDest 解析 tokens 开头的 ( 之后的链接地址，tokens 是 ctx.tokens 的后缀。
+func (context *Context) parseInlineLinkDest(ctx *InlineContext, tokens []byte) (passed, remains, destination []byte) {
 	remains = tokens
 	length := len(tokens)
 	if 2 > length {
 		return
 	}
 
+	isPointyBrackets := lex.ItemLess == tokens[1]
+	offset := ctx.tokensLen - length
+	if isPointyBrackets && offset < ctx.unclosedDest {
+		// 之前从更靠前的位置开始的扫描已经确认到行尾都没有 >
+		return
+	}
+
 	passed = make([]byte, 0, 256)
 	destination = make([]byte, 0, 256)
-
-	isPointyBrackets := lex.ItemLess == tokens[1]
 	if isPointyBrackets {
 		matchEnd := false
 		passed = append(passed, tokens[0], tokens[1])
@@ -28,10 +37,16 @@ func (context *Context) parseInlineLinkDest(tokens []byte) (passed, remains, des
 		size := 1
 		var r rune
 		var dest, runes []byte
-		for ; i < length; i += size {
+		for steps := 0; i < length; i += size {
+			if steps++; 0 == steps%1024 && context.canceled() {
+				passed = nil
+				return
+			}
+
 			size = 1
 			token := tokens[i]
 			if lex.ItemNewline == token {
+				ctx.unclosedDest = offset + i
 				passed = nil
 				return
 			}
@@ -54,6 +69,9 @@ func (context *Context) parseInlineLinkDest(tokens []byte) (passed, remains, des
 			}
 		}
 
+		if !matchEnd {
+			ctx.unclosedDest = ctx.tokensLen
+		}
 		if !matchEnd || length <= i+1 {
 			passed = nil
 			return
@@ -72,7 +90,12 @@ func (context *Context) parseInlineLinkDest(tokens []byte) (passed, remains, des
 		var r rune
 		var dest, runes []byte
 		destStarted := false
-		for ; i < length; i += size {
+		for steps := 0; i < length; i += size {
+			if steps++; 0 == steps%1024 && context.canceled() {
+				passed = nil
+				return
+			}
+
 			size = 1
 			token := tokens[i]
 			if token < utf8.RuneSelf {
@@ -110,7 +133,10 @@ func (context *Context) parseInlineLinkDest(tokens []byte) (passed, remains, des
 				}
 			}
 			if lex.ItemOpenParen == token && !lex.IsBackslashEscapePunct(tokens, i) {
-				openParens++
+				if openParens++; maxLinkDestParens < openParens-1 { // 不计算最外层的 (
+					passed = nil
+					return
+				}
 			}
 			if lex.ItemCloseParen == token && !lex.IsBackslashEscapePunct(tokens, i) {
 				openParens--
@@ -0,0 +1,123 @@
+package parse
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/pafthang/md/ast"
+)
+
+// InlineParser 描述了用户自定义的行级解析器，按触发字节进行分派，用于支持 @提及、议题引用等新的行级语法。
+//
+// 自定义行级节点需要配合渲染器的 ExtRendererFuncs 进行渲染。
+type InlineParser struct {
+	NodeType ast.NodeType    // 自定义节点类型，必须大于 ast.NodeTypeMaxVal
+	Name     string          // 节点类型名称，比如 NodeMention
+	Triggers []byte          // 触发字节，解析到这些字节时会尝试调用 Parse
+	Priority int             // 优先级，大于等于 0 时在内置解析器之前尝试，小于 0 时仅在内置解析器将触发字节解析为普通文本时尝试；同一阶段按优先级从高到低尝试
+	Parse    InlineParseFunc // 解析函数，必须设置
+}
+
+// InlineParseFunc 定义了行级解析函数签名。ctx.Pos() 指向触发字节，匹配时需要通过 ctx.Advance() 移动到节点结尾并返回节点，
+// 不匹配时返回 nil 且不能移动位置。
+type InlineParseFunc func(t *Tree, block *ast.Node, ctx *InlineContext) *ast.Node
+
+// RegisterInlineParser 注册自定义行级解析器。
+func (options *Options) RegisterInlineParser(parser *InlineParser) error {
+	if nil == parser.Parse {
+		return errors.New("inline parser parse func is nil [name=" + parser.Name + "]")
+	}
+	if 1 > len(parser.Triggers) {
+		return errors.New("inline parser triggers is empty [name=" + parser.Name + "]")
+	}
+	if ast.NodeTypeMaxVal >= parser.NodeType {
+		return errors.New("inline parser node type must be greater than NodeTypeMaxVal [name=" + parser.Name + "]")
+	}
+
+	replaced := false
+	for i, p := range options.InlineParsers {
+		if p.NodeType == parser.NodeType {
+			options.InlineParsers[i] = parser
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		options.InlineParsers = append(options.InlineParsers, parser)
+	}
+	sort.SliceStable(options.InlineParsers, func(i, j int) bool {
+		return options.InlineParsers[i].Priority > options.InlineParsers[j].Priority
+	})
+	return nil
+}
+
+// initInlineTriggers 根据注册的自定义行级解析器初始化触发字节表。
+func (t *Tree) initInlineTriggers() {
+	t.inlineTriggers = nil
+	if 1 > len(t.Context.ParseOption.InlineParsers) {
+		return
+	}
+
+	t.inlineTriggers = &[256]bool{}
+	for _, p := range t.Context.ParseOption.InlineParsers {
+		for _, trigger := range p.Triggers {
+			t.inlineTriggers[trigger] = true
+		}
+	}
+}
+
+// isInlineTrigger 判断 token 是否为自定义行级解析器的触发字节。
+func (t *Tree) isInlineTrigger(token byte) bool {
+	return nil != t.inlineTriggers && t.inlineTriggers[token]
+}
+
+// parseCustomInline 使用触发字节为 token 的自定义行级解析器进行解析，before 指定了尝试内置解析器之前还是之后的阶段。
+func (t *Tree) parseCustomInline(block *ast.Node, ctx *InlineContext, token byte, before bool) (ret *ast.Node) {
+	for _, p := range t.Context.ParseOption.InlineParsers {
+		if before != (0 <= p.Priority) {
+			continue
+		}
+		for _, trigger := range p.Triggers {
+			if trigger != token {
+				continue
+			}
+
+			pos := ctx.pos
+			if ret = p.Parse(t, block, ctx); nil != ret {
+				if pos == ctx.pos {
+					// 解析函数没有移动位置的话视为不匹配，避免死循环
+					ret = nil
+					continue
+				}
+				return
+			}
+			ctx.pos = pos
+		}
+	}
+	return
+}
+
+// 以下方法用于自定义行级解析器访问和推进行级解析上下文。
+
+// Tokens 返回当前块节点的全部行级内容。
+func (ctx *InlineContext) Tokens() []byte {
+	return ctx.tokens
+}
+
+// Pos 返回当前解析到的位置。
+func (ctx *InlineContext) Pos() int {
+	return ctx.pos
+}
+
+// Remains 返回从当前位置开始的剩余内容。
+func (ctx *InlineContext) Remains() []byte {
+	return ctx.tokens[ctx.pos:]
+}
+
+// Advance 向后移动 n 个字节。
+func (ctx *InlineContext) Advance(n int) {
+	ctx.pos += n
+	if ctx.pos > ctx.tokensLen {
+		ctx.pos = ctx.tokensLen
+	}
+}
@@ -0,0 +1,126 @@
+package parse_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/pafthang/md/ast"
+	"github.com/pafthang/md/parse"
+)
+
+// 测试用的自定义行级节点类型。
+const (
+	mention     = ast.NodeTypeMaxVal + 200
+	twinMention = ast.NodeTypeMaxVal + 201
+	bang        = ast.NodeTypeMaxVal + 202
+	star        = ast.NodeTypeMaxVal + 203
+	stuck       = ast.NodeTypeMaxVal + 204
+)
+
+// prefixParser 返回匹配 prefix 开头、后跟至少一个字母的行级解析函数。
+func prefixParser(typ ast.NodeType, prefix string) parse.InlineParseFunc {
+	return func(t *parse.Tree, block *ast.Node, ctx *parse.InlineContext) *ast.Node {
+		remains := ctx.Remains()
+		if !bytes.HasPrefix(remains, []byte(prefix)) {
+			return nil
+		}
+		i := len(prefix)
+		for ; i < len(remains) && ('a' <= remains[i] && 'z' >= remains[i]); i++ {
+		}
+		if len(prefix) == i {
+			return nil
+		}
+		n := &ast.Node{Type: typ, Tokens: remains[:i]}
+		ctx.Advance(i)
+		return n
+	}
+}
+
+// inlineString 返回第一个块节点的行级子节点描述，自定义节点使用 [] 括起，其他节点使用类型名。
+func inlineString(tree *parse.Tree) string {
+	var ret []string
+	for c := tree.Root.FirstChild.FirstChild; nil != c; c = c.Next {
+		switch {
+		case ast.NodeText == c.Type:
+			ret = append(ret, string(c.Tokens))
+		case ast.NodeTypeMaxVal < c.Type:
+			ret = append(ret, "["+string(c.Tokens)+"]")
+		default:
+			ret = append(ret, c.Type.String())
+		}
+	}
+	return strings.Join(ret, "|")
+}
+
+func TestRegisterInlineParser(t *testing.T) {
+	options := parse.NewOptions()
+	stuckCalls := 0
+	for _, parser := range []*parse.InlineParser{
+		{NodeType: mention, Name: "NodeMention", Triggers: []byte{'@'}, Priority: 1, Parse: prefixParser(mention, "@")},
+		{NodeType: twinMention, Name: "NodeTwinMention", Triggers: []byte{'@'}, Priority: 10, Parse: prefixParser(twinMention, "@@")},
+		{NodeType: bang, Name: "NodeBang", Triggers: []byte{'!'}, Priority: -1, Parse: prefixParser(bang, "!")},
+		{NodeType: star, Name: "NodeStar", Triggers: []byte{'*'}, Priority: 0, Parse: prefixParser(star, "*")},
+		{NodeType: stuck, Name: "NodeStuck", Triggers: []byte{'@', '%'}, Priority: 100, Parse: func(t *parse.Tree, block *ast.Node, ctx *parse.InlineContext) *ast.Node {
+			// 返回节点但是不移动位置，视为不匹配
+			stuckCalls++
+			return &ast.Node{Type: stuck}
+		}},
+	} {
+		if err := options.RegisterInlineParser(parser); nil != err {
+			t.Fatalf("register inline parser failed: %s", err)
+		}
+	}
+
+	for i, test := range []struct {
+		markdown string
+		expected string
+	}{
+		// 文本在触发字节处断开，优先级高的解析器先尝试
+		{"a @bob b\n", "a |[@bob]| b"},
+		{"a@@bob\n", "a|[@@bob]"},
+		// 高优先级解析器不匹配时回退到低优先级解析器
+		{"@@ @x\n", "@@ |[@x]"},
+		// 所有自定义解析器都不匹配时作为普通文本
+		{"a @ b %c\n", "a @ b %c"},
+		// 低优先级解析器仅在内置解析器产生普通文本时尝试
+		{"!x ![a](b)\n", "[!x]| |NodeImage"},
+		// 优先级大于等于 0 的解析器先于内置解析器尝试
+		{"*a* *b\n", "[*a]|* |[*b]"},
+	} {
+		if actual := inlineString(parse.Parse("", []byte(test.markdown), options)); test.expected != actual {
+			t.Errorf("test #%d [%q], expected [%s] but got [%s]", i, test.markdown, test.expected, actual)
+		}
+	}
+	if 1 > stuckCalls {
+		t.Fatalf("parser with the highest priority is not called")
+	}
+
+	// 注册不影响其他解析选项
+	if actual := inlineString(parse.Parse("", []byte("a @bob\n"), parse.NewOptions())); "a @bob" != actual {
+		t.Fatalf("custom inline parser leaked into other options [%s]", actual)
+	}
+}
+
+func TestRegisterInlineParserErrors(t *testing.T) {
+	options := parse.NewOptions()
+	for _, parser := range []*parse.InlineParser{
+		{NodeType: mention, Name: "NodeMention", Triggers: []byte{'@'}},
+		{NodeType: mention, Name: "NodeMention", Parse: prefixParser(mention, "@")},
+		{NodeType: ast.NodeText, Name: "NodeMention", Triggers: []byte{'@'}, Parse: prefixParser(mention, "@")},
+	} {
+		if err := options.RegisterInlineParser(parser); nil == err {
+			t.Fatalf("invalid inline parser [%+v] is registered", parser)
+		}
+	}
+
+	// 同一节点类型重复注册时替换
+	options.RegisterInlineParser(&parse.InlineParser{NodeType: mention, Name: "NodeMention", Triggers: []byte{'@'}, Parse: prefixParser(mention, "@")})
+	options.RegisterInlineParser(&parse.InlineParser{NodeType: mention, Name: "NodeMention", Triggers: []byte{'#'}, Parse: prefixParser(mention, "#")})
+	if 1 != len(options.InlineParsers) {
+		t.Fatalf("expected 1 inline parser but got %d", len(options.InlineParsers))
+	}
+	if actual := inlineString(parse.Parse("", []byte("@a #b\n"), options)); "@a |[#b]" != actual {
+		t.Fatalf("unexpected replaced parser result [%s]", actual)
+	}
+}
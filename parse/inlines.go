@@ -6,6 +6,7 @@ import (
 
 // parseInlines 解析并生成行级节点。
 func (t *Tree) parseInlines() {
+	t.initInlineTriggers()
 	t.walkParseInline(t.Root)
 
 	if t.Context.ParseOption.KramdownSpanIAL {
@@ -32,7 +33,11 @@ func (t *Tree) walkParseInline(node *ast.Node) {
 	}
 
 	// 只有如下几种类型的块节点需要生成行级子节点
-	if ast.NodeParagraph == typ || ast.NodeHeading == typ || ast.NodeTableCell == typ {
+	customInline := false
+	if p := t.Context.ParseOption.blockParser(typ); nil != p {
+		customInline = p.InlineContent && !p.Container
+	}
+	if ast.NodeParagraph == typ || ast.NodeHeading == typ || ast.NodeTableCell == typ || ast.NodeDefinitionTerm == typ || (ast.NodeDefinitionDescription == typ && nil == node.FirstChild) || customInline {
 		tokens := node.Tokens
 		if ast.NodeParagraph == typ {
 			if nil == tokens {
@@ -62,6 +67,13 @@ func (t *Tree) walkParseInline(node *ast.Node) {
 			return
 		}
 
+		if t.Context.textBlocks[node] || t.Context.canceled() {
+			// 超出资源限制或者解析被取消，不再进行行级解析
+			node.AppendChild(&ast.Node{Type: ast.NodeText, Tokens: tokens})
+			node.Tokens = nil
+			return
+		}
+
 		ctx := &InlineContext{tokens: tokens, tokensLen: length}
 
 		// 生成该块节点的行级子节点
@@ -95,11 +107,18 @@ func (t *Tree) walkParseInline(node *ast.Node) {
 			node.AppendChild(info)
 			code := &ast.Node{Type: ast.NodeCodeBlockCode, Tokens: node.Tokens}
 			node.AppendChild(code)
+			closed := nil != node.CodeBlockCloseFence
 			if nil == node.CodeBlockCloseFence {
 				node.CodeBlockCloseFence = node.CodeBlockOpenFence
 			}
 			closeMarker := &ast.Node{Type: ast.NodeCodeBlockFenceCloseMarker, Tokens: node.CodeBlockCloseFence, CodeBlockFenceLen: node.CodeBlockFenceLen}
 			node.AppendChild(closeMarker)
+			if nil != node.SourceStart && nil != node.SourceEnd {
+				openMarker.SourceStart, openMarker.SourceEnd = node.SourceStart, node.SourceStart.Advance(node.CodeBlockFenceLen)
+				if closed && node.SourceEnd.Line > node.SourceStart.Line {
+					closeMarker.SourceStart, closeMarker.SourceEnd = node.SourceEnd.Advance(-len(node.CodeBlockCloseFence)), node.SourceEnd
+				}
+			}
 		} else {
 			// 细化缩进代码块子节点
 			code := &ast.Node{Type: ast.NodeCodeBlockCode, Tokens: node.Tokens}
@@ -0,0 +1,179 @@
+package parse
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/pafthang/md/ast"
+	"github.com/pafthang/md/lex"
+	"github.com/pafthang/md/util"
+)
+
+// ErrLimitExceeded 表示解析时超出了 Options 中设置的资源限制，超出部分已经降级为文本。
+var ErrLimitExceeded = errors.New("parse limit exceeded")
+
+// 资源限制名称。
+const (
+	LimitMaxDepth            = "MaxDepth"
+	LimitMaxInputBytes       = "MaxInputBytes"
+	LimitMaxInlineDelimiters = "MaxInlineDelimiters"
+)
+
+// LimitError 描述了解析时超出的资源限制，可以使用 errors.Is(err, ErrLimitExceeded) 判断。
+type LimitError struct {
+	Limit string // 超出的限制，比如 LimitMaxDepth
+	Max   int    // 限制值
+}
+
+func (e *LimitError) Error() string {
+	return ErrLimitExceeded.Error() + ": " + e.Limit + " " + strconv.Itoa(e.Max)
+}
+
+func (e *LimitError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+// ParseWithContext 会将 markdown 原始文本字节数组解析为一棵语法树，和 Parse 的区别在于可以通过 ctx 取消解析并返回错误。
+//
+// 超出 options 中的资源限制或者 ctx 被取消时仍然会返回降级后的语法树（未能解析的部分作为文本），err 为第一个 *LimitError 或者 ctx.Err()。
+// 解析过程中发生 panic 时 tree 为 nil。
+func ParseWithContext(ctx context.Context, name string, markdown []byte, options *Options) (tree *Tree, err error) {
+	if err = ctx.Err(); nil != err {
+		return
+	}
+
+	defer util.RecoverPanic(&err)
+	t := &Tree{Name: name, Context: &Context{ParseOption: options, cancelCtx: ctx}}
+	t.parse(markdown)
+	tree, err = t, t.Context.err
+	return
+}
+
+// parse 解析 markdown，超出 MaxInputBytes 的部分作为文本段落追加到文末。
+func (t *Tree) parse(markdown []byte) {
+	t.parseInput(markdown, 0, nil)
+}
+
+// parseInput 解析完整输入中从字节偏移 offset 处开始的片段 markdown，完整输入中超出 MaxInputBytes 的部分作为文本段落追加到文末。
+// beforeInlines 不为 nil 时会在块级解析完成后、行级解析开始前调用。
+func (t *Tree) parseInput(markdown []byte, offset int, beforeInlines func()) {
+	t.Context.Tree = t
+	var tail []byte
+	if max := t.Context.ParseOption.MaxInputBytes; 0 < max && max < offset+len(markdown) {
+		if max <= offset {
+			markdown, tail = nil, markdown
+		} else if head, rest := splitInput(markdown, max-offset); 0 < offset && (1 > len(head) || lex.ItemNewline != head[len(head)-1]) {
+			// 片段从行首开始，片段内没有可以切分的换行时应该在片段开头切分，和一次性解析时保持一致
+			markdown, tail = nil, markdown
+		} else {
+			markdown, tail = head, rest
+		}
+		t.Context.limitExceeded(LimitMaxInputBytes, max)
+	}
+
+	t.lexer = lex.NewLexer(markdown)
+	t.Root = &ast.Node{Type: ast.NodeDocument}
+	t.parseBlocks()
+	t.appendTextBlock(tail)
+	if nil != beforeInlines {
+		beforeInlines()
+	}
+	t.parseInlines()
+	t.finalParseBlockIAL()
+	t.lexer = nil
+	t.Context.sourceSegments = nil
+}
+
+// splitInput 在 max 字节内的最后一个换行处切分 markdown，没有换行时在 max 字节内的最后一个完整字符处切分。
+func splitInput(markdown []byte, max int) (head, tail []byte) {
+	end := max
+	for i := max - 1; 0 <= i; i-- {
+		if lex.ItemNewline == markdown[i] {
+			end = i + 1
+			break
+		}
+	}
+	if max == end {
+		for 0 < end && !utf8.RuneStart(markdown[end]) {
+			end--
+		}
+	}
+	return markdown[:end:end], markdown[end:] // 限制 head 的容量，避免词法分析器在其末尾追加换行时覆盖 tail
+}
+
+// appendTextBlock 将 tokens 作为不进行行级解析的文本段落追加到文末。
+func (t *Tree) appendTextBlock(tokens []byte) {
+	if 1 > len(tokens) {
+		return
+	}
+
+	paragraph := &ast.Node{Type: ast.NodeParagraph, Tokens: bytes.TrimRight(tokens, "\r\n")}
+	t.Root.AppendChild(paragraph)
+	if nil == t.Context.textBlocks {
+		t.Context.textBlocks = map[*ast.Node]bool{}
+	}
+	t.Context.textBlocks[paragraph] = true
+}
+
+// limitExceeded 记录超出的资源限制 limit，只保留第一个错误。
+func (context *Context) limitExceeded(limit string, max int) {
+	if nil == context.err {
+		context.err = &LimitError{Limit: limit, Max: max}
+	}
+}
+
+// canceled 判断 ParseWithContext 传入的 ctx 是否已经被取消，取消后剩余内容都会作为文本处理。
+func (context *Context) canceled() bool {
+	if nil == context.cancelCtx {
+		return false
+	}
+	if context.stopped {
+		return true
+	}
+
+	if err := context.cancelCtx.Err(); nil != err {
+		context.stopped = true
+		if nil == context.err {
+			context.err = err
+		}
+	}
+	return context.stopped
+}
+
+// blockDepthExceeded 判断在块级节点 container 中起始新的块级节点是否会超出嵌套深度限制，深度按容器块层数计算。
+func (context *Context) blockDepthExceeded(container *ast.Node) bool {
+	max := context.ParseOption.MaxDepth
+	if 1 > max {
+		return false
+	}
+
+	depth := 0
+	for n := container; nil != n && ast.NodeDocument != n.Type; n = n.Parent {
+		if n.IsContainerBlock() {
+			depth++
+		}
+	}
+	if depth < max {
+		return false
+	}
+	context.limitExceeded(LimitMaxDepth, max)
+	return true
+}
+
+// inlineDelimitersExceeded 判断当前块中入栈的强调分隔符和链接括号是否已经达到上限，达到上限后新的分隔符作为文本。
+func (context *Context) inlineDelimitersExceeded(ctx *InlineContext) bool {
+	max := context.ParseOption.MaxInlineDelimiters
+	if 1 > max {
+		return false
+	}
+
+	if ctx.delimiterCount < max {
+		ctx.delimiterCount++
+		return false
+	}
+	context.limitExceeded(LimitMaxInlineDelimiters, max)
+	return true
+}
@@ -0,0 +1,102 @@
+package parse_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pafthang/md/ast"
+	"github.com/pafthang/md/parse"
+)
+
+func TestParseWithContextLimits(t *testing.T) {
+	cases := []struct {
+		name     string
+		markdown string
+		options  func(*parse.Options)
+		limit    string
+		check    func(*testing.T, *parse.Tree)
+	}{
+		{"depth", strings.Repeat(">", 100) + " foo\n", func(o *parse.Options) { o.MaxDepth = 8 }, parse.LimitMaxDepth, func(t *testing.T, tree *parse.Tree) {
+			depth := 0
+			ast.Walk(tree.Root, func(n *ast.Node, entering bool) ast.WalkStatus {
+				if entering && ast.NodeBlockquote == n.Type {
+					depth++
+				}
+				return ast.WalkContinue
+			})
+			if 8 != depth {
+				t.Fatalf("expected 8 blockquotes but got %d", depth)
+			}
+		}},
+		{"input", "# foo\n\nbar *baz*\n", func(o *parse.Options) { o.MaxInputBytes = 8 }, parse.LimitMaxInputBytes, func(t *testing.T, tree *parse.Tree) {
+			last := tree.Root.LastChild
+			if ast.NodeHeading != tree.Root.FirstChild.Type || ast.NodeParagraph != last.Type || "bar *baz*" != string(last.FirstChild.Tokens) || nil != last.FirstChild.Next {
+				t.Fatalf("unexpected last block %s", last.Type)
+			}
+		}},
+		{"delimiters", strings.Repeat("[", 1000) + strings.Repeat("*a", 1000), func(o *parse.Options) { o.MaxInlineDelimiters = 16 }, parse.LimitMaxInlineDelimiters, nil},
+	}
+
+	for _, c := range cases {
+		options := parse.NewOptions()
+		c.options(options)
+		tree, err := parse.ParseWithContext(context.Background(), "", []byte(c.markdown), options)
+		var limitErr *parse.LimitError
+		if !errors.Is(err, parse.ErrLimitExceeded) || !errors.As(err, &limitErr) || c.limit != limitErr.Limit {
+			t.Fatalf("case [%s] expected limit error [%s] but got [%v]", c.name, c.limit, err)
+		}
+		if nil != c.check {
+			c.check(t, tree)
+		}
+
+		if _, err = parse.ParseWithContext(context.Background(), "", []byte("foo\n"), options); nil != err {
+			t.Fatalf("case [%s] unexpected error [%v]", c.name, err)
+		}
+	}
+}
+
+func TestParseWithContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	<-ctx.Done()
+
+	if tree, err := parse.ParseWithContext(ctx, "", []byte("foo\n"), parse.NewOptions()); nil != tree || context.DeadlineExceeded != err {
+		t.Fatalf("expected deadline exceeded but got [%v]", err)
+	}
+}
+
+func TestParseInlineLinkDest(t *testing.T) {
+	options := parse.NewOptions()
+	for _, c := range []struct {
+		markdown string
+		links    int
+	}{
+		{"[a](<b\n[c](<d>)\n", 1},
+		{"[a](" + strings.Repeat("(", 32) + "b" + strings.Repeat(")", 32) + ")\n", 1},
+		{"[a](" + strings.Repeat("(", 33) + "b" + strings.Repeat(")", 33) + ")\n", 0},
+	} {
+		tree := parse.Parse("", []byte(c.markdown), options)
+		links := 0
+		ast.Walk(tree.Root, func(n *ast.Node, entering bool) ast.WalkStatus {
+			if entering && ast.NodeLink == n.Type {
+				links++
+			}
+			return ast.WalkContinue
+		})
+		if c.links != links {
+			t.Fatalf("expected [%d] links in [%q] but got [%d]", c.links, c.markdown, links)
+		}
+	}
+
+	// 未闭合的链接地址不会被反复扫描
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for _, prefix := range []string{"[a](", "[a](<", "[a](x", "![a]((", "[a]"} {
+		if _, err := parse.ParseWithContext(ctx, "", []byte(strings.Repeat(prefix, 20000)+")"), options); nil != err {
+			t.Fatalf("parse [%q] failed [%v]", prefix, err)
+		}
+	}
+}
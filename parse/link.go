@@ -297,12 +297,12 @@ func (context *Context) parseLinkLabel(tokens []byte) (n int, remains, label []b
 		return
 	}
 
-	passed := make([]byte, 0, len(tokens))
+	passed := make([]byte, 0, 256)
 	passed = append(passed, tokens[0])
 
 	closed := false
 	i := 1
-	for i < length {
+	for i < length && 999 >= len(label) { // 超长的标签无效，不需要继续扫描
 		token := tokens[i]
 		passed = append(passed, token)
 		r, size := utf8.DecodeRune(tokens[i:])
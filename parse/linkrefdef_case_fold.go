@@ -19,16 +19,23 @@ func (t *Tree) FindLinkRefDefLink(label []byte) (link *ast.Node) {
 	if t.Context.ParseOption.EditorIR || t.Context.ParseOption.EditorSV || t.Context.ParseOption.EditorWYSIWYG || t.Context.ParseOption.ProtyleWYSIWYG {
 		label = bytes.ReplaceAll(label, editor.CaretTokens, nil)
 	}
+	var folded []byte
 	ast.Walk(t.Root, func(n *ast.Node, entering bool) ast.WalkStatus {
-		if !entering || ast.NodeLinkRefDef != n.Type {
+		if !entering {
 			return ast.WalkContinue
 		}
+		if ast.NodeLinkRefDef != n.Type {
+			return skipDefSearch(n)
+		}
 		if bytes.EqualFold(n.Tokens, label) {
 			link = n.FirstChild
 			return ast.WalkStop
 		}
 
-		if c := cases.Fold(); bytes.EqualFold(c.Bytes(label), n.Tokens) || bytes.EqualFold(c.Bytes(n.Tokens), label) {
+		if nil == folded {
+			folded = foldLinkLabel(label)
+		}
+		if bytes.Equal(folded, foldLinkLabel(n.Tokens)) {
 			link = n.FirstChild
 			return ast.WalkStop
 		}
@@ -36,3 +43,8 @@ func (t *Tree) FindLinkRefDefLink(label []byte) (link *ast.Node) {
 	})
 	return
 }
+
+// foldLinkLabel 返回链接引用标签 label 进行 Unicode case fold 后的形式，case fold 后相同的标签匹配同一个链接引用定义。
+func foldLinkLabel(label []byte) []byte {
+	return bytes.ToLower(cases.Fold().Bytes(label))
+}
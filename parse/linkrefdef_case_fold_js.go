@@ -19,9 +19,12 @@ func (t *Tree) FindLinkRefDefLink(label []byte) (link *ast.Node) {
 		label = bytes.ReplaceAll(label, editor.CaretTokens, nil)
 	}
 	ast.Walk(t.Root, func(n *ast.Node, entering bool) ast.WalkStatus {
-		if !entering || ast.NodeLinkRefDef != n.Type {
+		if !entering {
 			return ast.WalkContinue
 		}
+		if ast.NodeLinkRefDef != n.Type {
+			return skipDefSearch(n)
+		}
 		if bytes.EqualFold(n.Tokens, label) {
 			link = n.FirstChild
 			return ast.WalkStop
@@ -32,3 +35,8 @@ func (t *Tree) FindLinkRefDefLink(label []byte) (link *ast.Node) {
 	})
 	return
 }
+
+// foldLinkLabel 返回链接引用标签 label 转换为小写后的形式，JS 版不支持 Unicode case fold。
+func foldLinkLabel(label []byte) []byte {
+	return bytes.ToLower(label)
+}
@@ -20,6 +20,8 @@ func paragraphFinalize(p *ast.Node, context *Context) (insertTable bool) {
 	if context.ParseOption.ParagraphBeginningSpace {
 		_, p.Tokens = lex.TrimRight(p.Tokens)
 	} else {
+		leftWhitespaces, _ := lex.TrimLeft(p.Tokens)
+		context.shiftSourceSegments(p, len(leftWhitespaces))
 		p.Tokens = lex.TrimWhitespace(p.Tokens)
 	}
 
@@ -27,6 +29,7 @@ func paragraphFinalize(p *ast.Node, context *Context) (insertTable bool) {
 	hasReferenceDefs := false
 	for tokens := p.Tokens; 0 < len(tokens) && lex.ItemOpenBracket == tokens[0]; tokens = p.Tokens {
 		if tokens = context.parseLinkRefDef(tokens); nil != tokens {
+			context.shiftSourceSegments(p, len(p.Tokens)-len(tokens))
 			p.Tokens = tokens
 			hasReferenceDefs = true
 			continue
@@ -35,6 +38,8 @@ func paragraphFinalize(p *ast.Node, context *Context) (insertTable bool) {
 	}
 	if hasReferenceDefs && lex.IsBlankLine(p.Tokens) {
 		p.Unlink()
+	} else if hasReferenceDefs && context.ParseOption.SourcePos {
+		p.SourceStart = context.sourcePos(p, 0)
 	}
 
 	if context.ParseOption.KramdownBlockIAL && nil != context.Tip.Parent && ast.NodeListItem == context.Tip.Parent.Type && p == context.Tip.Parent.FirstChild {
@@ -92,6 +97,7 @@ func paragraphFinalize(p *ast.Node, context *Context) (insertTable bool) {
 						} else {
 							p.PrependChild(taskListItemMarker)
 						}
+						context.shiftSourceSegments(p, len(p.Tokens)-len(tokens)+3)
 						p.Tokens = tokens[3:] // 剔除开头的 [ ]、[x] 或者 [X]
 						if isEditor {
 							p.Tokens = bytes.TrimSpace(p.Tokens)
@@ -146,6 +152,10 @@ func paragraphFinalize(p *ast.Node, context *Context) (insertTable bool) {
 		}
 	}
 
+	if context.ParseOption.DefinitionList && context.definitionListFinalize(p) {
+		return
+	}
+
 	if context.ParseOption.GFMTable {
 		if paragraph, table := context.parseTable(p); nil != table {
 			if nil != paragraph {
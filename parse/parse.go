@@ -1,6 +1,7 @@
 package parse
 
 import (
+	"context"
 	"sync"
 
 	"github.com/pafthang/md/ast"
@@ -8,15 +9,11 @@ import (
 )
 
 // Parse 会将 markdown 原始文本字节数组解析为一棵语法树。
+//
+// 超出 options 中的资源限制时超出部分会降级为文本，需要获取错误时请使用 ParseWithContext。
 func Parse(name string, markdown []byte, options *Options) (tree *Tree) {
 	tree = &Tree{Name: name, Context: &Context{ParseOption: options}}
-	tree.Context.Tree = tree
-	tree.lexer = lex.NewLexer(markdown)
-	tree.Root = &ast.Node{Type: ast.NodeDocument}
-	tree.parseBlocks()
-	tree.parseInlines()
-	tree.finalParseBlockIAL()
-	tree.lexer = nil
+	tree.parse(markdown)
 	return
 }
 
@@ -113,6 +110,7 @@ func Block(name string, markdown []byte, options *Options) (tree *Tree) {
 	tree.parseBlocks()
 	tree.finalParseBlockIAL()
 	tree.lexer = nil
+	tree.Context.sourceSegments = nil
 	return
 }
 
@@ -141,6 +139,17 @@ type Context struct {
 	lastMatchedContainer                                     *ast.Node // 最后一个匹配的块节点
 
 	rootIAL *ast.Node // 根节点 kramdown IAL
+
+	lineNum, lineOffset int                           // 当前行行号及其在原始输入中的起始字节偏移量
+	blockStartOffset    int                           // 当前行上正在尝试起始的块节点的起始下标
+	sourceSegments      map[*ast.Node][]sourceSegment // 块节点 Tokens 到原始输入位置的映射，仅在 SourcePos 开启时使用
+
+	cancelCtx  context.Context    // ParseWithContext 传入的上下文
+	stopped    bool               // cancelCtx 是否已经被取消
+	err        error              // 第一个超出的资源限制或者 cancelCtx 取消原因
+	textBlocks map[*ast.Node]bool // 不进行行级解析，直接作为文本的块节点
+
+	footnotesDefs []*ast.Node // 流式解析时之前分段中的脚注定义，查找脚注定义时排在语法树中的定义之前
 }
 
 // InlineContext 描述了行级元素解析上下文。
@@ -150,6 +159,9 @@ type InlineContext struct {
 	pos        int        // 当前解析到的 token 位置
 	delimiters *delimiter // 分隔符栈，用于强调解析
 	brackets   *delimiter // 括号栈，用于图片和链接解析
+
+	delimiterCount int // 已经入栈的分隔符和括号数，用于 Options.MaxInlineDelimiters 限制
+	unclosedDest   int // 在此位置之前开始的 <链接地址> 都无法闭合，用于避免重复扫描
 }
 
 // advanceOffset 用于移动 count 个字符位置，columns 指定了遇到 tab 时是否需要空格进行补偿偏移。
@@ -247,6 +259,10 @@ func (context *Context) finalize(block *ast.Node) {
 	switch block.Type {
 	case ast.NodeCodeBlock:
 		context.codeBlockFinalize(block)
+		if !block.IsFencedCodeBlock && context.ParseOption.DefinitionList && context.definitionDescriptionBlocks(block) {
+			context.Tip = parent
+			return
+		}
 	case ast.NodeHTMLBlock, ast.NodeIFrame, ast.NodeVideo, ast.NodeAudio, ast.NodeWidget:
 		context.htmlBlockFinalize(block)
 	case ast.NodeParagraph:
@@ -266,6 +282,17 @@ func (context *Context) finalize(block *ast.Node) {
 		context.gitConflictFinalize(block)
 	case ast.NodeCustomBlock:
 		context.customBlockFinalize(block)
+	case ast.NodeBlockquote:
+		context.blockquoteFinalize(block)
+	default:
+		if p := context.ParseOption.blockParser(block.Type); nil != p {
+			if p.InlineContent {
+				_, block.Tokens = lex.TrimRight(block.Tokens)
+			}
+			if nil != p.Finalize {
+				p.Finalize(block, context)
+			}
+		}
 	}
 
 	context.Tip = parent
@@ -275,6 +302,10 @@ func (context *Context) finalize(block *ast.Node) {
 func (context *Context) addChildMarker(nodeType ast.NodeType, tokens []byte) (ret *ast.Node) {
 	ret = &ast.Node{Type: nodeType, Tokens: tokens, Close: true}
 	context.Tip.AppendChild(ret)
+	if context.ParseOption.SourcePos {
+		ret.SourceStart = context.linePos(context.blockStartOffset)
+		ret.SourceEnd = context.linePos(context.offset)
+	}
 	return
 }
 
@@ -286,8 +317,12 @@ func (context *Context) addChild(nodeType ast.NodeType) (ret *ast.Node) {
 	}
 
 	ret = &ast.Node{Type: nodeType}
+	if p := context.ParseOption.blockParser(nodeType); nil != p {
+		ret.TypeFlags = p.typeFlags()
+	}
 	context.Tip.AppendChild(ret)
 	context.Tip = ret
+	context.markSourceStart(ret)
 	return
 }
 
@@ -300,10 +335,12 @@ func (context *Context) listsMatch(listData, itemData *ast.ListData) bool {
 
 // Tree 描述了 Markdown 抽象语法树结构。
 type Tree struct {
-	Root          *ast.Node      // 根节点
-	Context       *Context       // 块级解析上下文
-	lexer         *lex.Lexer     // 词法分析器
-	inlineContext *InlineContext // 行级解析上下文
+	Root           *ast.Node        // 根节点
+	Context        *Context         // 块级解析上下文
+	lexer          *lex.Lexer       // 词法分析器
+	inlineContext  *InlineContext   // 行级解析上下文
+	inlineTriggers *[256]bool       // 自定义行级解析器触发字节表
+	blockStarts    []BlockStartFunc // 合并了自定义块级解析器的起始函数
 
 	Name    string   // 名称
 	ID      string   // ID
@@ -334,10 +371,11 @@ type Options struct {
 	ToC bool
 	// Emoji 设置是否对 Emoji 别名替换为原生 Unicode 字符。
 	Emoji bool
-	// AliasEmoji 存储 ASCII 别名到表情 Unicode 映射。
-	AliasEmoji map[string]string
-	// EmojiAlias 存储表情 Unicode 到 ASCII 别名映射。
-	EmojiAlias map[string]string
+	// AliasEmoji 存储 ASCII 别名到表情 Unicode 映射，默认和其他 Options 共享全局字典 EmojiAliasUnicode，解析时只读。
+	// 需要修改时请替换为新的字典（比如 MD.PutEmojis），不要直接修改共享的字典。
+	AliasEmoji map[string]string `json:"-"`
+	// EmojiAlias 存储表情 Unicode 到 ASCII 别名映射，默认共享全局字典 EmojiUnicodeAlias，修改方式同 AliasEmoji。
+	EmojiAlias map[string]string `json:"-"`
 	// EmojiSite 设置图片 Emoji URL 的路径前缀。
 	EmojiSite string
 	// Editor 所见即所得支持。
@@ -358,6 +396,12 @@ type Options struct {
 	BlockRef bool
 	// FileAnnotationRef 设置是否开启文件注解引用支持。
 	FileAnnotationRef bool
+	// Admonition 设置是否开启提示块支持，包括 GFM 提示 > [!NOTE] 和围栏容器 :::type title。
+	Admonition bool
+	// DefinitionList 设置是否开启定义列表（Term\n: Definition）支持。
+	DefinitionList bool
+	// WikiLink 设置是否开启 [[维基链接]] 支持，包括 [[Page#Heading|Alias]] 和嵌入 ![[Page]]。
+	WikiLink bool
 	// Mark 设置是否打开 ==标记== 支持。
 	Mark bool
 	// KramdownBlockIAL 设置是否打开 kramdown 块级内联属性列表支持。 https://kramdown.gettalong.org/syntax.html#inline-attribute-lists
@@ -396,8 +440,23 @@ type Options struct {
 	// 其他情况，比如标题块软换行分块 https://github.com/siyuan-note/siyuan/issues/5723 以及软换行空行分块 https://ld246.com/article/1703839312585
 	// 的场景需要移动 IAL 节点，但是 API 输入 markdown https://github.com/siyuan-note/siyuan/issues/6725）无需移动
 	Spin bool
+	// SourcePos 设置是否记录节点在原始输入中的起止位置（行号、列号和字节偏移量）。
+	SourcePos bool
+	// BlockParsers 存储通过 RegisterBlockParser 注册的自定义块级解析器。
+	BlockParsers []*BlockParser `json:"-"`
+	// InlineParsers 存储通过 RegisterInlineParser 注册的自定义行级解析器，按优先级从高到低排列。
+	InlineParsers []*InlineParser `json:"-"`
+	// MaxDepth 设置块级容器（块引用、列表、列表项等）的最大嵌套深度，超出后的内容作为段落文本，0 表示不限制。
+	MaxDepth int
+	// MaxInputBytes 设置 Parse 和 ParseWithContext 解析的最大输入字节数，超出的部分作为文本段落追加到文末，0 表示不限制。
+	MaxInputBytes int
+	// MaxInlineDelimiters 设置每个块中强调分隔符和链接括号的最大入栈数，超出后的分隔符作为文本，0 表示不限制。
+	MaxInlineDelimiters int
 }
 
+// EmojiLock 曾用于保护全局 Emoji 字典。
+//
+// Deprecated: Emoji 字典改为写时复制，解析时不再加锁，请不要直接修改全局字典 EmojiAliasUnicode 和 EmojiUnicodeAlias。
 var EmojiLock = sync.Mutex{}
 
 func NewOptions() *Options {
@@ -415,6 +474,9 @@ func NewOptions() *Options {
 		YamlFrontMatter:   true,
 		BlockRef:          false,
 		FileAnnotationRef: false,
+		WikiLink:          false,
+		Admonition:        false,
+		DefinitionList:    false,
 		Mark:              false,
 		KramdownBlockIAL:  false,
 		HeadingID:         true,
@@ -0,0 +1,158 @@
+package parse
+
+import (
+	"github.com/pafthang/md/ast"
+	"github.com/pafthang/md/lex"
+)
+
+// sourceSegment 记录了块节点 Tokens 中某一段内容在原始输入中的起始位置。
+type sourceSegment struct {
+	tokenOffset int     // 该段在节点 Tokens 中的偏移量
+	pos         ast.Pos // 该段在原始输入中的起始位置
+}
+
+// linePos 返回当前行上第 offset 个字节在原始输入中的位置。
+func (context *Context) linePos(offset int) *ast.Pos {
+	return &ast.Pos{Line: context.lineNum, Column: offset + 1, Offset: context.lineOffset + offset}
+}
+
+// lineEndPos 返回当前行行尾（不包含换行符）在原始输入中的位置。
+func (context *Context) lineEndPos() *ast.Pos {
+	length := context.currentLineLen
+	if 0 < length && lex.ItemNewline == context.currentLine[length-1] {
+		length--
+	}
+	return context.linePos(length)
+}
+
+// markSourceStart 将当前行上块起始位置设置为节点 n 的起始位置。
+func (context *Context) markSourceStart(n *ast.Node) {
+	if !context.ParseOption.SourcePos {
+		return
+	}
+	n.SourceStart = context.linePos(context.blockStartOffset)
+}
+
+// markSourceEnd 将当前行行尾设置为节点 n 及其所有祖先节点的结束位置。
+func (context *Context) markSourceEnd(n *ast.Node) {
+	if !context.ParseOption.SourcePos {
+		return
+	}
+	end := context.lineEndPos()
+	for ; nil != n; n = n.Parent {
+		if nil == n.SourceEnd {
+			n.SourceEnd = &ast.Pos{}
+		}
+		*n.SourceEnd = *end
+	}
+}
+
+// addSourceSegment 记录节点 n 的 Tokens 从 tokenOffset 开始的内容对应当前行的第 offset 个字节。
+func (context *Context) addSourceSegment(n *ast.Node, tokenOffset, offset int) {
+	if !context.ParseOption.SourcePos {
+		return
+	}
+	if nil == context.sourceSegments {
+		context.sourceSegments = map[*ast.Node][]sourceSegment{}
+	}
+	context.sourceSegments[n] = append(context.sourceSegments[n], sourceSegment{tokenOffset: tokenOffset, pos: *context.linePos(offset)})
+}
+
+// shiftSourceSegments 在节点 n 的 Tokens 开头被剔除 count 个字节后修正位置记录。
+func (context *Context) shiftSourceSegments(n *ast.Node, count int) {
+	if 1 > count {
+		return
+	}
+	segments := context.sourceSegments[n]
+	for i := range segments {
+		segments[i].tokenOffset -= count
+	}
+}
+
+// moveSourceSegments 将节点 from 的位置记录转移给节点 to。
+func (context *Context) moveSourceSegments(from, to *ast.Node) {
+	if segments, ok := context.sourceSegments[from]; ok {
+		context.sourceSegments[to] = segments
+		delete(context.sourceSegments, from)
+	}
+}
+
+// sliceSourceSegments 将节点 from 的 Tokens 从 start 开始的内容的位置记录复制给节点 to。
+func (context *Context) sliceSourceSegments(from, to *ast.Node, start int) {
+	segments := context.sourceSegments[from]
+	if 1 > len(segments) {
+		return
+	}
+
+	ret := []sourceSegment{{tokenOffset: 0, pos: *context.sourcePos(from, start)}}
+	for _, s := range segments {
+		if s.tokenOffset > start {
+			ret = append(ret, sourceSegment{tokenOffset: s.tokenOffset - start, pos: s.pos})
+		}
+	}
+	context.sourceSegments[to] = ret
+}
+
+// sourcePos 返回块节点 n 的 Tokens 中第 tokenOffset 个字节在原始输入中的位置，没有位置记录时返回 nil。
+func (context *Context) sourcePos(n *ast.Node, tokenOffset int) *ast.Pos {
+	segments := context.sourceSegments[n]
+	if 1 > len(segments) {
+		return nil
+	}
+
+	segment := segments[0]
+	for _, s := range segments[1:] {
+		if s.tokenOffset > tokenOffset {
+			break
+		}
+		segment = s
+	}
+	return segment.pos.Advance(tokenOffset - segment.tokenOffset)
+}
+
+// markInlineSourcePos 设置行级节点 n 的起止位置，start 和 end 为 n 在块节点 block 的 Tokens 中的起止偏移量。
+func (t *Tree) markInlineSourcePos(n, block *ast.Node, start, end int) {
+	if !t.Context.ParseOption.SourcePos {
+		return
+	}
+	if nil == n.SourceStart {
+		n.SourceStart = t.Context.sourcePos(block, start)
+	}
+	if nil == n.SourceEnd {
+		n.SourceEnd = t.Context.sourcePos(block, end)
+	}
+}
+
+// markLinkSourcePos 设置链接或者图片节点 link 的标记符、地址和标题子节点的起止位置，参数均为在块节点 block 的 Tokens 中的偏移量。
+//
+// 地址和标题的位置包含 <>、引号等定界符。closeParen 为 0 时表示引用链接，引用链接的地址和标题来自链接引用定义，不设置位置。
+func (t *Tree) markLinkSourcePos(link, block *ast.Node, openBracket, closeBracket, destStart, destEnd, titleEnd, closeParen int) {
+	titleStart := destEnd
+	for n := link.FirstChild; nil != n; n = n.Next {
+		switch n.Type {
+		case ast.NodeBang:
+			t.markInlineSourcePos(n, block, openBracket-1, openBracket)
+		case ast.NodeOpenBracket:
+			t.markInlineSourcePos(n, block, openBracket, openBracket+1)
+		case ast.NodeCloseBracket:
+			t.markInlineSourcePos(n, block, closeBracket, closeBracket+1)
+		}
+		if 1 > closeParen {
+			continue
+		}
+
+		switch n.Type {
+		case ast.NodeOpenParen:
+			t.markInlineSourcePos(n, block, closeBracket+1, closeBracket+2)
+		case ast.NodeLinkDest:
+			t.markInlineSourcePos(n, block, destStart, destEnd)
+		case ast.NodeLinkSpace:
+			titleStart = destEnd + len(n.Tokens)
+			t.markInlineSourcePos(n, block, destEnd, titleStart)
+		case ast.NodeLinkTitle:
+			t.markInlineSourcePos(n, block, titleStart, titleEnd)
+		case ast.NodeCloseParen:
+			t.markInlineSourcePos(n, block, closeParen, closeParen+1)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package parse_test
+
+import (
+	"testing"
+
+	"github.com/pafthang/md/ast"
+	"github.com/pafthang/md/parse"
+)
+
+func TestSourcePos(t *testing.T) {
+	options := parse.NewOptions()
+	options.SourcePos = true
+	cases := []struct {
+		markdown string
+		expected []string // 按照遍历顺序的节点类型和位置
+	}{
+		{"- a\n- b\n\npara\n", []string{"NodeList 1:1-2:4", "NodeParagraph 4:1-4:5"}},
+		{"<!-- a\nb -->\nc\n", []string{"NodeHTMLBlock 1:1-2:6", "NodeParagraph 3:1-3:2"}},
+		{"![i](<d e> 'ti') [l]( /u )\n", []string{"NodeImage 1:1-1:17", "NodeBang 1:1-1:2", "NodeOpenBracket 1:2-1:3", "NodeLinkText 1:3-1:4",
+			"NodeCloseBracket 1:4-1:5", "NodeOpenParen 1:5-1:6", "NodeLinkDest 1:6-1:11", "NodeLinkSpace 1:11-1:12", "NodeLinkTitle 1:12-1:16",
+			"NodeCloseParen 1:16-1:17", "NodeLink 1:18-1:27", "NodeOpenBracket 1:18-1:19", "NodeLinkText 1:19-1:20", "NodeCloseBracket 1:20-1:21",
+			"NodeOpenParen 1:21-1:22", "NodeLinkDest 1:23-1:25", "NodeLinkSpace 1:25-1:26", "NodeCloseParen 1:26-1:27"}},
+		{"a `` `c` ``\n", []string{"NodeCodeSpan 1:3-1:12", "NodeCodeSpanOpenMarker 1:3-1:6", "NodeCodeSpanContent 1:6-1:9", "NodeCodeSpanCloseMarker 1:9-1:12"}},
+	}
+	for _, c := range cases {
+		tree := parse.Parse("", []byte(c.markdown), options)
+		var got []string
+		ast.Walk(tree.Root, func(n *ast.Node, entering bool) ast.WalkStatus {
+			if entering && nil != n.SourceStart {
+				got = append(got, n.Type.String()+" "+n.SourceStart.String()+"-"+n.SourceEnd.String())
+			}
+			return ast.WalkContinue
+		})
+		for _, expected := range c.expected {
+			for 0 < len(got) && expected != got[0] {
+				got = got[1:]
+			}
+			if 1 > len(got) {
+				t.Fatalf("expected [%s] in [%q]", expected, c.markdown)
+			}
+		}
+	}
+}
@@ -0,0 +1,640 @@
+package parse
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+
+	"github.com/pafthang/md/ast"
+	"github.com/pafthang/md/lex"
+)
+
+// DefaultStreamMaxPendingBytes 是流式解析器暂缓返回的分段总字节数的默认上限。
+const DefaultStreamMaxPendingBytes = 64 * 1024
+
+// Stream 描述了流式解析器。
+//
+// 流式解析器从 io.Reader 中逐行读取 Markdown 文本，在遇到不会再被后续内容影响的顶层块边界（空行）时立即将已读取的内容解析为一棵语法树返回，
+// 以便调用方边解析边渲染输出，避免将整个输入以及完整的语法树同时驻留在内存中。
+//
+// 脚注定义在整个文档内共享：分段中的脚注引用可以使用之前分段中的脚注定义，引用在定义之前出现时引用和定义之间的分段会合并为一个分段返回，
+// 返回的语法树中脚注引用的编号和 ID 与一次性解析一致，之前分段中脚注定义的 FootnotesRefs 也会追加后续分段中的引用。
+// 目录和标题 ID 去重等需要完整文档的功能需要调用方在分段之间自行处理。
+type Stream struct {
+	Name string // 名称
+
+	// MaxPendingBytes 设置暂缓返回的分段总字节数上限，超出后最早的分段即使仍然存在未能解析的链接引用或者脚注引用也会返回，
+	// 小于 1 时不限制。NewStream 默认设置为 DefaultStreamMaxPendingBytes。
+	MaxPendingBytes int
+
+	options      *Options
+	body         *Options // 非首个分段使用的解析选项
+	reader       *bufio.Reader
+	carry        []byte           // 上次读取到的下一分段的首行
+	fences       []*streamFence   // 当前未闭合的围栏结构栈
+	linkRefDefs  []*streamLinkRef // 之前分段中出现的链接引用定义
+	pending      []*streamSegment // 已经解析但尚未返回的分段
+	footnotes    []*ast.Node      // 已经返回的分段中的脚注定义
+	pendingBytes int              // 暂缓返回的分段总字节数
+	line         int              // 下一分段首行在原始输入中的行号，从 0 开始
+	offset       int              // 下一分段首行在原始输入中的字节偏移
+	started      bool             // 是否已经读取过首行
+	eof          bool             // 是否已经读取完毕
+}
+
+// streamFence 描述了一个跨越空行的围栏结构，在其闭合前不能进行分段。
+type streamFence struct {
+	marker    byte // 围栏标记符，HTML 块为 <，YAML Front Matter 为 -
+	length    int  // 围栏标记符长度，HTML 块为 HTML 块类型
+	container bool // 是否是可以嵌套其他围栏的容器块（提示块、超级块）
+}
+
+// streamSegment 描述了一个分段。
+type streamSegment struct {
+	data         []byte          // 原始文本，在返回前保留以便重新解析或者和后续分段合并
+	line, offset int             // 首行在原始输入中的行号（从 0 开始）和字节偏移
+	tree         *Tree           // 解析得到的语法树
+	labels       map[string]bool // 文本中残留的 [label]，即后续出现同名定义时可能变为链接引用或者脚注引用的标签
+	footnotes    []*ast.Node     // 分段中的脚注定义
+}
+
+// streamLinkRef 记录了链接引用定义的标签、地址和标题。
+type streamLinkRef struct {
+	label, dest, title []byte
+}
+
+// NewStream 创建一个从 reader 中读取 Markdown 文本的流式解析器。
+func NewStream(name string, reader io.Reader, options *Options) *Stream {
+	return &Stream{Name: name, MaxPendingBytes: DefaultStreamMaxPendingBytes, options: options, reader: bufio.NewReader(reader)}
+}
+
+// Next 读取下一个分段并将其解析为语法树返回，所有内容读取完毕后返回 io.EOF。
+//
+// 文本中残留 [label] 的分段会被暂缓返回，直到后续分段中出现了同名的链接引用定义或者脚注定义、读取完毕或者暂缓的分段超过 MaxPendingBytes，
+// 出现定义时只重新解析使用了该标签的分段。超出 Options 中的资源限制时同时返回降级后的语法树和 *LimitError。
+//
+// 语法树中节点的源码位置已经换算为在完整输入中的位置。
+func (s *Stream) Next() (tree *Tree, err error) {
+	for {
+		if 0 < len(s.pending) {
+			first := s.pending[0]
+			if s.eof && nil == s.carry || 1 > len(first.labels) || (0 < s.MaxPendingBytes && s.MaxPendingBytes < s.pendingBytes) {
+				s.pending = s.pending[1:]
+				s.pendingBytes -= len(first.data)
+				s.commitFootnotes(first)
+				tree, err = first.tree, first.tree.Context.err
+				return
+			}
+		}
+
+		data, line, offset, readErr := s.readSegment()
+		if io.EOF == readErr {
+			if 0 < len(s.pending) {
+				continue
+			}
+			err = readErr
+			return
+		}
+		if nil != readErr {
+			err = readErr
+			return
+		}
+
+		segment := &streamSegment{data: data, line: line, offset: offset}
+		defs := s.parseSegment(segment)
+		if merged := s.mergeFootnotes(segment); nil != merged {
+			segment = merged
+			defs = append(defs, s.parseSegment(segment)...)
+		}
+		if 0 < len(defs) {
+			// 出现了新的链接引用定义，重新解析之前暂缓的使用了这些标签的分段
+			for _, p := range s.pending {
+				for _, def := range defs {
+					if p.labels[def] {
+						s.parseSegment(p)
+						break
+					}
+				}
+			}
+		}
+		s.pending = append(s.pending, segment)
+		s.pendingBytes += len(segment.data)
+	}
+}
+
+// mergeFootnotes 如果分段 segment 中的脚注定义被暂缓的分段引用，则将从第一个引用的分段开始到 segment 的所有分段合并为一个分段返回。
+func (s *Stream) mergeFootnotes(segment *streamSegment) (ret *streamSegment) {
+	if 1 > len(segment.footnotes) {
+		return
+	}
+
+	start := -1
+	for i, p := range s.pending {
+		for _, def := range segment.footnotes {
+			if p.labels[streamLabel(def.Tokens)] {
+				start = i
+				break
+			}
+		}
+		if 0 <= start {
+			break
+		}
+	}
+	if 0 > start {
+		return
+	}
+
+	ret = &streamSegment{line: s.pending[start].line, offset: s.pending[start].offset}
+	for _, p := range s.pending[start:] {
+		ret.data = append(ret.data, p.data...)
+		s.pendingBytes -= len(p.data)
+	}
+	ret.data = append(ret.data, segment.data...)
+	s.pending = s.pending[:start]
+	return
+}
+
+// parseSegment 解析分段 segment，返回其中新出现的链接引用定义标签。
+func (s *Stream) parseSegment(segment *streamSegment) (defs []string) {
+	options := s.options
+	if 0 < segment.line && options.YamlFrontMatter {
+		// YAML Front Matter 只能出现在文档开头，后续分段以 --- 开头时应该解析为分隔线
+		options = s.bodyOptions()
+	}
+	tree := &Tree{Name: s.Name, Context: &Context{ParseOption: options}}
+	if s.options.Footnotes && bytes.Contains(segment.data, []byte("[^")) {
+		tree.Context.footnotesDefs = s.previousFootnotes(segment)
+	}
+	var defBlock *ast.Node
+	tree.parseInput(segment.data, segment.offset, func() {
+		defBlock = s.prependLinkRefDefs(tree, segment.data)
+	})
+	if nil != defBlock {
+		defBlock.Unlink()
+	}
+	defs = s.collectLinkRefDefs(tree)
+	shiftSourcePos(tree.Root, segment.line, segment.offset)
+
+	segment.tree = tree
+	segment.labels, segment.footnotes = nil, nil
+	if s.options.LinkRef || s.options.Footnotes {
+		segment.labels = bracketLabels(tree, segment.data)
+	}
+	if s.options.Footnotes {
+		ast.Walk(tree.Root, func(n *ast.Node, entering bool) ast.WalkStatus {
+			if entering && ast.NodeFootnotesDef == n.Type {
+				segment.footnotes = append(segment.footnotes, n)
+				return ast.WalkSkipChildren
+			}
+			return ast.WalkContinue
+		})
+	}
+	return
+}
+
+// previousFootnotes 返回在分段 segment 之前出现的所有脚注定义的占位节点，用于解析 segment 中引用之前定义的脚注引用。
+//
+// 暂缓的分段可能被重新解析，因此解析时只使用占位节点，返回分段时再由 commitFootnotes 将引用关联到实际的脚注定义上。
+func (s *Stream) previousFootnotes(segment *streamSegment) (ret []*ast.Node) {
+	defs := s.footnotes
+	for _, p := range s.pending {
+		if p == segment {
+			break
+		}
+		defs = append(defs[:len(defs):len(defs)], p.footnotes...)
+	}
+	for _, def := range defs {
+		ret = append(ret, &ast.Node{Type: ast.NodeFootnotesDef, Tokens: def.Tokens})
+	}
+	return
+}
+
+// commitFootnotes 在返回分段 segment 前将其中的脚注引用关联到整个文档的脚注定义上，并按照一次性解析的规则重新生成脚注引用 ID。
+func (s *Stream) commitFootnotes(segment *streamSegment) {
+	if !s.options.Footnotes {
+		return
+	}
+
+	tree := segment.tree
+	tree.Context.footnotesDefs = s.footnotes[:len(s.footnotes):len(s.footnotes)]
+	for _, def := range segment.footnotes {
+		def.FootnotesRefs = nil
+	}
+	ast.Walk(tree.Root, func(n *ast.Node, entering bool) ast.WalkStatus {
+		if !entering || ast.NodeFootnotesRef != n.Type {
+			return ast.WalkContinue
+		}
+
+		idx, def := tree.FindFootnotesDef(n.Tokens)
+		if nil == def {
+			return ast.WalkContinue
+		}
+		n.FootnotesRefId = strconv.Itoa(idx)
+		if refsLen := len(def.FootnotesRefs); 0 < refsLen {
+			n.FootnotesRefId += ":" + strconv.Itoa(refsLen+1)
+		}
+		def.FootnotesRefs = append(def.FootnotesRefs, n)
+		return ast.WalkContinue
+	})
+	s.footnotes = append(s.footnotes, segment.footnotes...)
+}
+
+// bodyOptions 返回用于解析非首个分段的解析选项。
+func (s *Stream) bodyOptions() *Options {
+	if nil == s.body {
+		body := *s.options
+		body.YamlFrontMatter = false
+		s.body = &body
+	}
+	return s.body
+}
+
+// bracketLabels 返回分段中残留的 [label] 标签，tree 是分段原文 data 解析得到的语法树。
+//
+// 文本中（包括 GFM 自动链接吞掉的部分）残留了 ] 时，原文中所有的 [label] 都作为候选标签，
+// 这样标签中包含强调等行级元素或者转义的方括号时也能和定义匹配；另外还会加入文本中的 [label]，用于匹配跨越多行且带有引述等容器块前缀的标签。
+func bracketLabels(tree *Tree, data []byte) (ret map[string]bool) {
+	buf := &bytes.Buffer{}
+	var bracket bool
+	ast.Walk(tree.Root, func(n *ast.Node, entering bool) ast.WalkStatus {
+		if !entering {
+			return ast.WalkContinue
+		}
+
+		if n.IsBlock() {
+			ret = addBracketLabels(ret, buf.Bytes(), false)
+			buf.Reset()
+		} else if ast.NodeText == n.Type || (ast.NodeLinkText == n.Type && 2 == n.Parent.LinkType) {
+			buf.Write(n.Tokens)
+			bracket = bracket || 0 <= bytes.IndexByte(n.Tokens, lex.ItemCloseBracket)
+		} else if ast.NodeLink == n.Type && 2 != n.LinkType {
+			buf.WriteByte(lex.ItemSpace) // 链接分隔了前后的文本
+		}
+		return ast.WalkContinue
+	})
+	ret = addBracketLabels(ret, buf.Bytes(), false)
+	if bracket {
+		ret = addBracketLabels(ret, data, true)
+	}
+	return
+}
+
+// addBracketLabels 将 text 中不包含嵌套方括号的 [label] 的规范化标签添加到 labels 并返回，escapes 为 true 时跳过转义的方括号。
+func addBracketLabels(labels map[string]bool, text []byte, escapes bool) map[string]bool {
+	start := -1
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case lex.ItemBackslash:
+			if escapes {
+				i++
+			}
+		case lex.ItemOpenBracket:
+			start = i
+		case lex.ItemCloseBracket:
+			if 0 <= start {
+				if nil == labels {
+					labels = map[string]bool{}
+				}
+				labels[streamLabel(text[start+1:i])] = true
+				start = -1
+			}
+		}
+	}
+	return labels
+}
+
+// streamLabel 返回标签 label 的规范化形式：合并空白后按照解析链接引用时的规则进行 case fold。
+func streamLabel(label []byte) string {
+	return string(foldLinkLabel(bytes.Join(bytes.Fields(label), []byte{lex.ItemSpace})))
+}
+
+// readSegment 读取下一个分段，返回分段内容以及分段首行在原始输入中的行号和字节偏移。
+func (s *Stream) readSegment() (segment []byte, line, offset int, err error) {
+	if s.eof && nil == s.carry {
+		err = io.EOF
+		return
+	}
+
+	line, offset = s.line, s.offset
+	blank := false
+	for {
+		var ln []byte
+		if nil != s.carry {
+			ln, s.carry = s.carry, nil
+		} else {
+			if s.eof {
+				break
+			}
+			ln, err = s.reader.ReadBytes(lex.ItemNewline)
+			if nil != err {
+				if io.EOF != err {
+					return
+				}
+				err = nil
+				s.eof = true
+				if 1 > len(ln) {
+					break
+				}
+			}
+		}
+
+		first := !s.started
+		s.started = true
+		if 0 == len(s.fences) {
+			if isBlankLine(ln) {
+				blank = true
+			} else if blank && 0 < len(segment) && s.splittable(ln) {
+				s.carry = ln
+				break
+			} else {
+				blank = false
+			}
+		}
+		s.scanFences(ln, first)
+		segment = append(segment, ln...)
+		s.line++
+		s.offset += len(ln)
+	}
+
+	if 1 > len(segment) {
+		err = io.EOF
+	}
+	return
+}
+
+// splittable 判断在空行后出现的 ln 是否开始了一个新的顶层块，即前面的内容不会再被 ln 及其后续内容影响。
+func (s *Stream) splittable(ln []byte) bool {
+	if s.options.DefinitionList && s.nextLineIsDefinition() { // 定义列表的后续术语
+		return false
+	}
+
+	switch ln[0] {
+	case lex.ItemSpace, lex.ItemTab: // 列表项等容器块的延续内容
+		return false
+	case lex.ItemColon: // 定义列表的描述
+		return false
+	case lex.ItemOpenBrace: // 块级 IAL
+		return !bytes.HasPrefix(ln, []byte("{:"))
+	case lex.ItemHyphen, lex.ItemPlus, lex.ItemAsterisk: // 松散列表的后续列表项
+		return 2 > len(ln) || !lex.IsWhitespace(ln[1])
+	}
+
+	// 松散有序列表的后续列表项
+	i := 0
+	for ; i < len(ln) && lex.IsDigit(ln[i]); i++ {
+	}
+	if 0 < i && i < len(ln) && (lex.ItemDot == ln[i] || lex.ItemCloseParen == ln[i]) {
+		return !(i+1 == len(ln) || lex.IsWhitespace(ln[i+1]))
+	}
+	return true
+}
+
+// nextLineIsDefinition 判断 reader 中的下一行是否是定义列表的描述（: 开头）。
+func (s *Stream) nextLineIsDefinition() bool {
+	next, _ := s.reader.Peek(4)
+	next = bytes.TrimLeft(next, " ")
+	return 0 < len(next) && lex.ItemColon == next[0]
+}
+
+// scanFences 根据 ln 更新未闭合的围栏结构栈。
+func (s *Stream) scanFences(ln []byte, first bool) {
+	trimmed := lex.TrimWhitespace(ln)
+	if 1 > len(trimmed) {
+		return
+	}
+
+	if 0 < len(s.fences) {
+		top := s.fences[len(s.fences)-1]
+		if s.isFenceClose(top, ln, trimmed) {
+			s.fences = s.fences[:len(s.fences)-1]
+			return
+		}
+		if !top.container {
+			return
+		}
+	}
+
+	if first && s.options.YamlFrontMatter && bytes.Equal(trimmed, []byte("---")) {
+		s.fences = append(s.fences, &streamFence{marker: lex.ItemHyphen, length: 3})
+		return
+	}
+
+	if 3 < streamIndent(ln) {
+		return
+	}
+
+	marker := trimmed[0]
+	length := 0
+	for ; length < len(trimmed) && marker == trimmed[length]; length++ {
+	}
+	info := trimmed[length:]
+	switch marker {
+	case lex.ItemBacktick:
+		if 3 <= length && 0 > bytes.IndexByte(info, lex.ItemBacktick) {
+			s.fences = append(s.fences, &streamFence{marker: marker, length: length})
+		}
+	case lex.ItemTilde:
+		if 3 <= length {
+			s.fences = append(s.fences, &streamFence{marker: marker, length: length})
+		}
+	case lex.ItemDollar:
+		if 2 <= length && !(2 < len(info) && bytes.HasSuffix(info, MathBlockMarker)) {
+			s.fences = append(s.fences, &streamFence{marker: marker, length: 2})
+		}
+	case lex.ItemSemicolon:
+		if 3 <= length {
+			s.fences = append(s.fences, &streamFence{marker: marker, length: length})
+		}
+	case lex.ItemColon:
+		if s.options.Admonition && 3 <= length && 0 < len(info) {
+			s.fences = append(s.fences, &streamFence{marker: marker, length: length, container: true})
+		}
+	case lex.ItemOpenBrace:
+		if s.options.SuperBlock && 3 == length {
+			s.fences = append(s.fences, &streamFence{marker: marker, length: length, container: true})
+		}
+	case lex.ItemLess:
+		if s.options.GitConflict && bytes.HasPrefix(ln, []byte("<<<<<<<")) {
+			s.fences = append(s.fences, &streamFence{marker: lex.ItemGreater, length: 7})
+			return
+		}
+		if typ := streamHTMLBlockType(trimmed); 0 < typ && !isStreamHTMLBlockClose(trimmed[1:], typ) {
+			s.fences = append(s.fences, &streamFence{marker: marker, length: typ})
+		}
+	}
+}
+
+// isFenceClose 判断 ln 是否闭合了围栏结构 fence。
+func (s *Stream) isFenceClose(fence *streamFence, ln, trimmed []byte) bool {
+	switch fence.marker {
+	case lex.ItemLess:
+		return isStreamHTMLBlockClose(ln, fence.length)
+	case lex.ItemDollar:
+		return bytes.HasSuffix(trimmed, MathBlockMarker)
+	case lex.ItemGreater:
+		return bytes.HasPrefix(ln, []byte(">>>>>>>"))
+	case lex.ItemOpenBrace:
+		return bytes.Equal(trimmed, []byte("}}}"))
+	case lex.ItemHyphen:
+		return bytes.Equal(trimmed, []byte("---"))
+	}
+
+	if fence.length > len(trimmed) || 3 < streamIndent(ln) { // 缩进 4 列以上的是代码内容
+		return false
+	}
+	for _, token := range trimmed {
+		if fence.marker != token {
+			return false
+		}
+	}
+	return true
+}
+
+// streamHTMLBlockType 返回可能跨越空行的 HTML 块（类型 1-5）的类型，其他情况返回 0。
+func streamHTMLBlockType(tokens []byte) int {
+	lower := bytes.ToLower(tokens)
+	for _, tag := range htmlBlockTags1 {
+		if bytes.HasPrefix(lower, tag) {
+			if len(lower) == len(tag) || lex.IsWhitespace(lower[len(tag)]) || lex.ItemGreater == lower[len(tag)] {
+				return 1
+			}
+		}
+	}
+	switch {
+	case bytes.HasPrefix(tokens, []byte("<!--")):
+		return 2
+	case bytes.HasPrefix(tokens, []byte("<?")):
+		return 3
+	case bytes.HasPrefix(tokens, []byte("<![CDATA[")):
+		return 5
+	case 2 < len(tokens) && bytes.HasPrefix(tokens, []byte("<!")) && 'A' <= tokens[2] && 'Z' >= tokens[2]:
+		return 4
+	}
+	return 0
+}
+
+// isStreamHTMLBlockClose 判断 tokens 是否包含 HTML 块（类型 1-5）的结束条件。
+func isStreamHTMLBlockClose(tokens []byte, typ int) bool {
+	switch typ {
+	case 1:
+		lower := bytes.ToLower(tokens)
+		for _, tag := range htmlBlockCloseTags1 {
+			if bytes.Contains(lower, tag) {
+				return true
+			}
+		}
+	case 2:
+		return bytes.Contains(tokens, []byte("-->"))
+	case 3:
+		return bytes.Contains(tokens, []byte("?>"))
+	case 4:
+		return bytes.Contains(tokens, htmlBlockGreater)
+	case 5:
+		return bytes.Contains(tokens, []byte("]]>"))
+	}
+	return false
+}
+
+// prependLinkRefDefs 将之前分段中出现的链接引用定义插入到 tree 开头以便解析行级链接引用，返回插入的链接引用定义块。
+//
+// 只插入原文 data 中以 [label] 形式出现过的标签对应的定义，避免每个分段都插入全部定义。
+func (s *Stream) prependLinkRefDefs(tree *Tree, data []byte) (defBlock *ast.Node) {
+	if !s.options.LinkRef || 1 > len(s.linkRefDefs) {
+		return
+	}
+
+	labels := addBracketLabels(nil, data, true)
+	for _, ref := range s.linkRefDefs {
+		if !labels[streamLabel(ref.label)] {
+			continue
+		}
+		if nil == defBlock {
+			defBlock = &ast.Node{Type: ast.NodeLinkRefDefBlock}
+		}
+		def := &ast.Node{Type: ast.NodeLinkRefDef, Tokens: ref.label}
+		def.AppendChild(tree.newLink(ast.NodeLink, ref.label, ref.dest, ref.title, 1))
+		defBlock.AppendChild(def)
+	}
+	if nil != defBlock {
+		tree.Root.PrependChild(defBlock)
+	}
+	return
+}
+
+// collectLinkRefDefs 记录 tree 中出现的链接引用定义，同名标签以先出现的定义为准，返回新记录的定义的规范化标签。
+func (s *Stream) collectLinkRefDefs(tree *Tree) (defs []string) {
+	if !s.options.LinkRef {
+		return
+	}
+
+	ast.Walk(tree.Root, func(n *ast.Node, entering bool) ast.WalkStatus {
+		if !entering || ast.NodeLinkRefDef != n.Type {
+			return ast.WalkContinue
+		}
+
+		label := streamLabel(n.Tokens)
+		for _, ref := range s.linkRefDefs {
+			if label == streamLabel(ref.label) {
+				return ast.WalkSkipChildren
+			}
+		}
+
+		ref := &streamLinkRef{label: n.Tokens}
+		if link := n.FirstChild; nil != link {
+			if dest := link.ChildByType(ast.NodeLinkDest); nil != dest {
+				ref.dest = dest.Tokens
+			}
+			if title := link.ChildByType(ast.NodeLinkTitle); nil != title {
+				ref.title = title.Tokens
+			}
+		}
+		s.linkRefDefs = append(s.linkRefDefs, ref)
+		defs = append(defs, label)
+		return ast.WalkSkipChildren
+	})
+	return
+}
+
+// shiftSourcePos 将 root 下所有节点的源码位置换算为从第 line 行（从 0 开始）、字节偏移 offset 处开始的位置。
+func shiftSourcePos(root *ast.Node, line, offset int) {
+	if 0 == line && 0 == offset {
+		return
+	}
+
+	shifted := map[*ast.Pos]bool{}
+	shift := func(pos *ast.Pos) {
+		if nil == pos || shifted[pos] {
+			return
+		}
+		shifted[pos] = true
+		pos.Line += line
+		pos.Offset += offset
+	}
+	ast.Walk(root, func(n *ast.Node, entering bool) ast.WalkStatus {
+		if entering {
+			shift(n.SourceStart)
+			shift(n.SourceEnd)
+		}
+		return ast.WalkContinue
+	})
+}
+
+// streamIndent 返回行 ln 的缩进列数，tab 按照 4 列制表位计算。
+func streamIndent(ln []byte) (ret int) {
+	for _, token := range ln {
+		switch token {
+		case lex.ItemSpace:
+			ret++
+		case lex.ItemTab:
+			ret += 4 - ret%4
+		default:
+			return
+		}
+	}
+	return
+}
+
+func isBlankLine(ln []byte) bool {
+	return 1 > len(lex.TrimWhitespace(ln))
+}
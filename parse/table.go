@@ -113,9 +113,15 @@ func (context *Context) parseTable(paragraph *ast.Node) (retParagraph, retTable
 }
 
 func (context *Context) parseTable0(tokens []byte) (ret *ast.Node) {
-	lines := lex.Split(tokens, lex.ItemNewline)
-	length := len(lines)
-	if 2 > length {
+	// 先只切分前两行检查分隔行，避免段落的每一行都切分余下的全部内容
+	head := tokens
+	if first := bytes.IndexByte(tokens, lex.ItemNewline); 0 <= first {
+		if second := bytes.IndexByte(tokens[first+1:], lex.ItemNewline); 0 <= second {
+			head = tokens[:first+1+second+1]
+		}
+	}
+	lines := lex.Split(head, lex.ItemNewline)
+	if 2 > len(lines) {
 		return
 	}
 
@@ -130,6 +136,8 @@ func (context *Context) parseTable0(tokens []byte) (ret *ast.Node) {
 		return
 	}
 
+	lines = lex.Split(tokens, lex.ItemNewline)
+	length := len(lines)
 	if 2 == length && 1 == len(aligns) && 0 == aligns[0] && !bytes.Contains(tokens, []byte("|")) {
 		// 如果只有两行并且对齐方式是默认对齐且没有 | 时（foo\n---）就和 Setext 标题规则冲突了
 		// 但在块级解析时显然已经尝试进行解析 Setext 标题，还能走到这里说明 Setetxt 标题解析失败，
@@ -17,6 +17,10 @@ func (t *Tree) parseText(ctx *InlineContext) *ast.Node {
 			break
 		}
 	}
+	if start == ctx.pos {
+		// 自定义行级解析器的触发字节没有匹配时作为普通文本
+		ctx.pos++
+	}
 	return &ast.Node{Type: ast.NodeText, Tokens: ctx.tokens[start:ctx.pos]}
 }
 
@@ -29,7 +33,7 @@ func (t *Tree) isMarker(token byte) bool {
 	if t.Context.ParseOption.Sup && lex.ItemCaret == token {
 		return true
 	}
-	return false
+	return t.isInlineTrigger(token)
 }
 
 var backslash = util.StrToBytes("\\")
@@ -130,12 +134,18 @@ func (t *Tree) mergeText(node *ast.Node) {
 			// 逐个合并后续兄弟节点
 			for nil != next && ast.NodeText == next.Type {
 				child.AppendTokens(next.Tokens)
+				if nil != next.SourceEnd {
+					child.SourceEnd = next.SourceEnd
+				}
 				next.Unlink()
 				next = child.Next
 			}
 		} else if ast.NodeLinkText == child.Type {
 			for nil != next && ast.NodeLinkText == next.Type {
 				child.AppendTokens(next.Tokens)
+				if nil != next.SourceEnd {
+					child.SourceEnd = next.SourceEnd
+				}
 				next.Unlink()
 				next = child.Next
 			}
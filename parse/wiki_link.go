@@ -0,0 +1,107 @@
+package parse
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/pafthang/md/ast"
+	"github.com/pafthang/md/lex"
+)
+
+var (
+	wikiLinkOpenMarker      = []byte("[[")
+	wikiLinkEmbedOpenMarker = []byte("![[")
+	wikiLinkCloseMarker     = []byte("]]")
+)
+
+// parseWikiLink 解析维基链接 [[Page#Heading|Alias]] 和维基链接嵌入 ![[Page]]，不匹配时返回 nil。
+func (t *Tree) parseWikiLink(ctx *InlineContext) *ast.Node {
+	if !t.Context.ParseOption.WikiLink {
+		return nil
+	}
+
+	tokens := ctx.tokens[ctx.pos:]
+	openMarker := wikiLinkOpenMarker
+	typ := ast.NodeWikiLink
+	if lex.ItemBang == tokens[0] {
+		openMarker = wikiLinkEmbedOpenMarker
+		typ = ast.NodeWikiLinkEmbed
+	}
+	if !bytes.HasPrefix(tokens, openMarker) {
+		return nil
+	}
+
+	content := tokens[len(openMarker):]
+	end := bytes.Index(content, wikiLinkCloseMarker)
+	if 1 > end {
+		return nil
+	}
+	content = content[:end]
+	if bytes.ContainsAny(content, "[]\n") {
+		return nil
+	}
+
+	target := content
+	var alias []byte
+	if idx := bytes.IndexByte(content, lex.ItemPipe); 0 <= idx {
+		target, alias = content[:idx], content[idx+1:]
+		// 表格中的维基链接需要使用 \| 分隔别名
+		target = bytes.TrimSuffix(target, []byte{lex.ItemBackslash})
+	}
+	page := target
+	var heading []byte
+	if idx := bytes.IndexByte(target, lex.ItemCrosshatch); 0 <= idx {
+		page, heading = target[:idx], target[idx+1:]
+	}
+	page, heading, alias = bytes.TrimSpace(page), bytes.TrimSpace(heading), bytes.TrimSpace(alias)
+	if 1 > len(page) && 1 > len(heading) {
+		return nil
+	}
+
+	ctx.pos += len(openMarker) + end + len(wikiLinkCloseMarker)
+	return newWikiLink(ast.NodeWikiLinkEmbed == typ, page, heading, alias)
+}
+
+// NewWikiLink 使用目标 target（Page#Heading）和显示文本 text 构造维基链接节点，embed 为 true 时构造维基链接嵌入节点，target 无效时返回 nil。
+//
+// text 和默认的显示文本（页面名称或者 Page#Heading）不同时作为别名，用于从编辑器 DOM 还原 [[Page#Heading|Alias]]。
+func NewWikiLink(target, text string, embed bool) *ast.Node {
+	if strings.ContainsAny(target, "[]|\n") {
+		return nil
+	}
+
+	page, heading := target, ""
+	if idx := strings.IndexByte(target, lex.ItemCrosshatch); 0 <= idx {
+		page, heading = target[:idx], target[idx+1:]
+	}
+	page, heading, text = strings.TrimSpace(page), strings.TrimSpace(heading), strings.TrimSpace(text)
+	if "" == page && "" == heading {
+		return nil
+	}
+
+	alias := text
+	if strings.ContainsAny(alias, "[]\n") || page == alias || (page+"#"+heading) == alias {
+		alias = ""
+	}
+	return newWikiLink(embed, []byte(page), []byte(heading), []byte(alias))
+}
+
+func newWikiLink(embed bool, page, heading, alias []byte) *ast.Node {
+	typ, openMarker := ast.NodeWikiLink, wikiLinkOpenMarker
+	if embed {
+		typ, openMarker = ast.NodeWikiLinkEmbed, wikiLinkEmbedOpenMarker
+	}
+	ret := &ast.Node{Type: typ}
+	ret.AppendChild(&ast.Node{Type: ast.NodeWikiLinkOpenMarker, Tokens: openMarker})
+	if 0 < len(page) {
+		ret.AppendChild(&ast.Node{Type: ast.NodeWikiLinkPage, Tokens: page})
+	}
+	if 0 < len(heading) {
+		ret.AppendChild(&ast.Node{Type: ast.NodeWikiLinkHeading, Tokens: heading})
+	}
+	if 0 < len(alias) {
+		ret.AppendChild(&ast.Node{Type: ast.NodeWikiLinkAlias, Tokens: alias})
+	}
+	ret.AppendChild(&ast.Node{Type: ast.NodeWikiLinkCloseMarker, Tokens: wikiLinkCloseMarker})
+	return ret
+}
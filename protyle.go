@@ -683,7 +683,7 @@ func (md *MD) genASTByBlockDOM(n *html.Node, tree *parse.Tree) {
 		if ast.NodeCodeBlock == tree.Context.Tip.Type {
 			languageNode := n.FirstChild
 			language := ""
-			if nil != languageNode.FirstChild {
+			if nil != languageNode && nil != languageNode.FirstChild {
 				language = languageNode.FirstChild.Data
 			}
 			tree.Context.Tip.AppendChild(&ast.Node{Type: ast.NodeCodeBlockFenceInfoMarker, CodeBlockInfo: util.StrToBytes(language)})
@@ -816,7 +816,7 @@ func (md *MD) genASTByBlockDOM(n *html.Node, tree *parse.Tree) {
 			return
 		}
 
-		level := util.DomAttrValue(n, "data-subtype")[1:]
+		level := strings.TrimPrefix(util.DomAttrValue(n, "data-subtype"), "h")
 		tmp := strings.TrimPrefix(text, " ")
 		if strings.HasPrefix(tmp, "#") {
 			// Allow changing headings with `#` https://github.com/siyuan-note/siyuan/issues/7924
@@ -832,6 +832,9 @@ func (md *MD) genASTByBlockDOM(n *html.Node, tree *parse.Tree) {
 
 		node.Type = ast.NodeHeading
 		node.HeadingLevel, _ = strconv.Atoi(level)
+		if 1 > node.HeadingLevel || 6 < node.HeadingLevel { // 缺少或者错误的 data-subtype
+			node.HeadingLevel = 1
+		}
 		tree.Context.Tip.AppendChild(node)
 		tree.Context.Tip = node
 		defer tree.Context.ParentTip()
@@ -848,6 +851,14 @@ func (md *MD) genASTByBlockDOM(n *html.Node, tree *parse.Tree) {
 		tree.Context.Tip.AppendChild(node)
 		tree.Context.Tip = node
 		defer tree.Context.ParentTip()
+	case ast.NodeAdmonition:
+		node.Type = ast.NodeAdmonition
+		node.AdmonitionKind = util.DomAttrValue(n, "data-kind")
+		node.AdmonitionTitle = util.DomAttrValue(n, "data-title")
+		node.AdmonitionFenceLen, _ = strconv.Atoi(util.DomAttrValue(n, "data-fence-len"))
+		tree.Context.Tip.AppendChild(node)
+		tree.Context.Tip = node
+		defer tree.Context.ParentTip()
 	case ast.NodeList:
 		node.Type = ast.NodeList
 		marker := util.DomAttrValue(n, "data-marker")
@@ -876,7 +887,7 @@ func (md *MD) genASTByBlockDOM(n *html.Node, tree *parse.Tree) {
 				parent.ListData.BulletChar = '*'
 			} else if "o" == subType {
 				parent.ListData.Typ = 1
-				parent.ListData.Num, _ = strconv.Atoi(marker[:len(marker)-1])
+				parent.ListData.Num, _ = strconv.Atoi(strings.TrimRight(marker, ".)"))
 				parent.ListData.Delimiter = '.'
 			} else if "t" == subType {
 				parent.ListData.Typ = 3
@@ -894,7 +905,7 @@ func (md *MD) genASTByBlockDOM(n *html.Node, tree *parse.Tree) {
 			node.ListData.BulletChar = '*'
 		} else if "o" == subType {
 			node.ListData.Typ = 1
-			node.ListData.Num, _ = strconv.Atoi(marker[:len(marker)-1])
+			node.ListData.Num, _ = strconv.Atoi(strings.TrimRight(marker, ".)"))
 			node.ListData.Delimiter = '.'
 		} else if "t" == subType {
 			node.ListData.Typ = 3
@@ -943,7 +954,10 @@ func (md *MD) genASTByBlockDOM(n *html.Node, tree *parse.Tree) {
 		defer tree.Context.ParentTip()
 	case ast.NodeHTMLBlock:
 		node.Type = ast.NodeHTMLBlock
-		content := util.DomAttrValue(n.FirstChild.NextSibling.FirstChild, "data-content")
+		var content string
+		if nil != n.FirstChild && nil != n.FirstChild.NextSibling {
+			content = util.DomAttrValue(n.FirstChild.NextSibling.FirstChild, "data-content")
+		}
 		content = html.UnescapeHTMLStr(content)
 		node.Tokens = util.StrToBytes(content)
 		tree.Context.Tip.AppendChild(node)
@@ -1330,6 +1344,13 @@ func (md *MD) genASTContenteditable(n *html.Node, tree *parse.Tree) {
 				break
 			}
 
+			if target := util.DomAttrValue(n, "data-wiki-link"); "" != target && md.ParseOptions.WikiLink {
+				if wikiLink := parse.NewWikiLink(target, util.DomText(n), "wiki-embed" == util.DomAttrValue(n, "data-subtype")); nil != wikiLink {
+					tree.Context.Tip.AppendChild(wikiLink)
+					return
+				}
+			}
+
 			if md.ParseOptions.TextMark {
 				tree.Context.Tip.AppendChild(node)
 				parse.SetTextMarkNode(node, n, md.ParseOptions)
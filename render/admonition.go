@@ -0,0 +1,32 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/pafthang/md/ast"
+)
+
+// AdmonitionTitle 返回提示块 node 的标题，没有设置标题时使用首字母大写的类型名，比如 Note。
+func AdmonitionTitle(node *ast.Node) string {
+	if "" != node.AdmonitionTitle {
+		return node.AdmonitionTitle
+	}
+	kind := node.AdmonitionKind
+	if "" == kind {
+		return ""
+	}
+	return strings.ToUpper(kind[:1]) + kind[1:]
+}
+
+// admonitionOpenMarker 返回提示块 node 的 Markdown 开始标记，GFM 提示为 [!NOTE] title，围栏容器为 :::note title。
+func admonitionOpenMarker(node *ast.Node) (ret string) {
+	if 0 == node.AdmonitionFenceLen {
+		ret = "[!" + strings.ToUpper(node.AdmonitionKind) + "]"
+	} else {
+		ret = strings.Repeat(":", node.AdmonitionFenceLen) + node.AdmonitionKind
+	}
+	if "" != node.AdmonitionTitle {
+		ret += " " + node.AdmonitionTitle
+	}
+	return
+}
@@ -0,0 +1,752 @@
+package render
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pafthang/md/html"
+)
+
+// CSSPolicy 描述了基于白名单的 CSS 过滤策略，用于清理 style 属性和 <style> 元素内容。
+//
+// CSS 会先按照 CSS Syntax Level 3 进行分词（转义序列和注释在这一步被还原或移除，因此无法通过转义绕过检查），然后逐条检查声明：
+//   - 属性名必须在 Properties 中，并且属性值需要通过对应的校验器；
+//   - 属性值中只能使用 Functions 中列出的函数，expression() 等其他函数会导致整条声明被移除；
+//   - url() 中的地址必须是相对地址或者使用 URLSchemes 中的协议，data: 协议仅允许非 SVG 的图片；
+//   - @import、@font-face 等 at 规则会被移除，@media 和 @supports 中的规则会被递归清理。
+type CSSPolicy struct {
+	// Properties 为允许的 CSS 属性名（小写）到属性值校验器的映射，校验器为 nil 时仅进行通用的安全检查。校验器的参数为小写的属性值。
+	Properties map[string]func(value string) bool
+	// Functions 为属性值中允许使用的函数名（小写），url 函数由 URLSchemes 控制。
+	Functions []string
+	// URLSchemes 为 url() 中允许的 URL 协议（小写，不含冒号）。
+	URLSchemes []string
+	// AllowRelativeURLs 设置 url() 中是否允许不带协议的相对地址。
+	AllowRelativeURLs bool
+}
+
+// NewCSSPolicy 创建默认的 CSS 过滤策略，仅允许排版相关的属性，position 仅允许 static 和 relative 以避免覆盖页面的浮层。
+func NewCSSPolicy() *CSSPolicy {
+	ret := &CSSPolicy{
+		Properties:        map[string]func(string) bool{},
+		Functions:         []string{"rgb", "rgba", "hsl", "hsla", "calc", "var", "min", "max", "clamp", "linear-gradient", "radial-gradient", "repeating-linear-gradient", "repeating-radial-gradient"},
+		URLSchemes:        []string{"http", "https", "data"},
+		AllowRelativeURLs: true,
+	}
+	for _, property := range []string{
+		"color", "background", "background-color", "background-image", "background-position", "background-repeat", "background-size",
+		"font", "font-family", "font-size", "font-style", "font-variant", "font-weight",
+		"text-align", "text-decoration", "text-decoration-color", "text-decoration-line", "text-decoration-style", "text-indent", "text-shadow", "text-transform",
+		"vertical-align", "line-height", "letter-spacing", "word-spacing", "white-space", "word-break", "word-wrap", "overflow-wrap", "direction",
+		"width", "height", "max-width", "max-height", "min-width", "min-height",
+		"margin", "margin-top", "margin-right", "margin-bottom", "margin-left",
+		"padding", "padding-top", "padding-right", "padding-bottom", "padding-left",
+		"border", "border-top", "border-right", "border-bottom", "border-left", "border-color", "border-style", "border-width", "border-radius", "border-collapse", "border-spacing",
+		"box-shadow", "box-sizing", "opacity", "outline", "overflow", "overflow-x", "overflow-y",
+		"display", "float", "clear", "visibility", "list-style", "list-style-type", "list-style-position", "table-layout", "caption-side",
+		"flex", "flex-direction", "flex-wrap", "flex-grow", "flex-shrink", "flex-basis", "justify-content", "align-items", "align-self", "gap", "order",
+		"top", "right", "bottom", "left",
+	} {
+		ret.Properties[property] = nil
+	}
+	ret.Properties["position"] = cssKeywordValidator("static", "relative")
+	return ret
+}
+
+var defaultCSSPolicy = NewCSSPolicy()
+
+// cssKeywordValidator 返回仅允许指定关键字的属性值校验器。
+func cssKeywordValidator(keywords ...string) func(string) bool {
+	return func(value string) bool {
+		value = strings.TrimSpace(strings.TrimSuffix(value, "!important"))
+		for _, keyword := range keywords {
+			if keyword == value {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// SanitizeStyle 清理 style 属性值 style，移除不满足策略的声明后返回剩余的声明，没有剩余声明时返回 ""。
+func (p *CSSPolicy) SanitizeStyle(style string) string {
+	tokens := newCSSTokenizer(style).tokenize()
+	return p.sanitizeDeclarations(tokens)
+}
+
+// SanitizeStylesheet 清理 <style> 元素内容 css，返回清理后的样式表。
+func (p *CSSPolicy) SanitizeStylesheet(css string) string {
+	tokens := newCSSTokenizer(css).tokenize()
+	buf := &strings.Builder{}
+	p.sanitizeRules(tokens, buf)
+	return buf.String()
+}
+
+// sanitizeRules 清理规则列表 tokens 并将结果写入 buf。
+func (p *CSSPolicy) sanitizeRules(tokens []*cssToken, buf *strings.Builder) {
+	for i := 0; i < len(tokens); {
+		token := tokens[i]
+		if cssWhitespace == token.typ || cssCDO == token.typ || cssCDC == token.typ || cssSemicolon == token.typ {
+			i++
+			continue
+		}
+
+		// 规则前导部分（选择器或者 at 规则参数）截止到 { 或者 ;
+		start := i
+		for ; i < len(tokens) && cssOpenCurly != tokens[i].typ && !(cssSemicolon == tokens[i].typ && cssAtKeyword == token.typ); i++ {
+		}
+		prelude := tokens[start:i]
+		if i >= len(tokens) || cssSemicolon == tokens[i].typ {
+			// @import 等没有块的 at 规则或者不完整的规则直接丢弃
+			i++
+			continue
+		}
+		end := cssBlockEnd(tokens, i)
+		block := tokens[i+1 : end]
+		i = end + 1
+
+		if cssAtKeyword == token.typ {
+			name := strings.ToLower(token.value)
+			if "media" != name && "supports" != name || !p.safePrelude(prelude[1:]) {
+				continue
+			}
+			nested := &strings.Builder{}
+			p.sanitizeRules(block, nested)
+			if 0 < nested.Len() {
+				buf.WriteString("@" + name + " " + strings.TrimSpace(serializeCSS(prelude[1:])) + " {\n" + nested.String() + "}\n")
+			}
+			continue
+		}
+
+		if !p.safePrelude(prelude) {
+			continue
+		}
+		if declarations := p.sanitizeDeclarations(block); "" != declarations {
+			buf.WriteString(strings.TrimSpace(serializeCSS(prelude)) + " { " + declarations + " }\n")
+		}
+	}
+}
+
+// safePrelude 判断选择器或者 at 规则参数中是否包含 url() 或者不完整的记号。
+func (p *CSSPolicy) safePrelude(tokens []*cssToken) bool {
+	for _, token := range tokens {
+		switch token.typ {
+		case cssURL, cssBadURL, cssBadString, cssAtKeyword, cssOpenCurly, cssCloseCurly:
+			return false
+		case cssFunction:
+			if "url" == strings.ToLower(token.value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// sanitizeDeclarations 清理声明列表 tokens，返回以 ; 分隔的安全声明。
+func (p *CSSPolicy) sanitizeDeclarations(tokens []*cssToken) string {
+	var declarations []string
+	depth := 0
+	start := 0
+	for i := 0; i <= len(tokens); i++ {
+		if i < len(tokens) {
+			switch tokens[i].typ {
+			case cssFunction, cssOpenParen, cssOpenSquare, cssOpenCurly:
+				depth++
+				continue
+			case cssCloseParen, cssCloseSquare, cssCloseCurly:
+				depth--
+				continue
+			case cssSemicolon:
+				if 0 < depth {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+
+		if declaration := p.sanitizeDeclaration(tokens[start:i]); "" != declaration {
+			declarations = append(declarations, declaration)
+		}
+		start = i + 1
+	}
+	return strings.Join(declarations, "; ")
+}
+
+// sanitizeDeclaration 清理单条声明 tokens，不满足策略时返回 ""。
+func (p *CSSPolicy) sanitizeDeclaration(tokens []*cssToken) string {
+	tokens = trimCSSWhitespace(tokens)
+	if 3 > len(tokens) || cssIdent != tokens[0].typ {
+		return ""
+	}
+
+	property := strings.ToLower(tokens[0].value)
+	validator, ok := p.Properties[property]
+	if !ok {
+		return ""
+	}
+
+	i := 1
+	for ; i < len(tokens) && cssWhitespace == tokens[i].typ; i++ {
+	}
+	if i >= len(tokens) || cssColon != tokens[i].typ {
+		return ""
+	}
+	values := trimCSSWhitespace(tokens[i+1:])
+	if 1 > len(values) {
+		return ""
+	}
+
+	for j, token := range values {
+		switch token.typ {
+		case cssBadURL, cssBadString, cssAtKeyword, cssOpenCurly, cssCloseCurly, cssCDO, cssCDC, cssColon:
+			return ""
+		case cssURL:
+			if !p.allowURL(token.value) {
+				return ""
+			}
+		case cssFunction:
+			name := strings.ToLower(token.value)
+			if "url" == name {
+				// url("...") 形式，参数为字符串
+				next := trimCSSWhitespace(values[j+1:])
+				if 1 > len(next) || cssString != next[0].typ || !p.allowURL(next[0].value) {
+					return ""
+				}
+				continue
+			}
+			if !p.allowFunction(name) {
+				return ""
+			}
+		}
+	}
+
+	value := strings.TrimSpace(serializeCSS(values))
+	if nil != validator && !validator(strings.ToLower(value)) {
+		return ""
+	}
+	return property + ": " + value
+}
+
+func (p *CSSPolicy) allowFunction(name string) bool {
+	for _, function := range p.Functions {
+		if function == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *CSSPolicy) allowURL(dest string) bool {
+	policy := &SanitizePolicy{URLSchemes: p.URLSchemes, AllowRelativeURLs: p.AllowRelativeURLs}
+	return policy.AllowURL(dest)
+}
+
+// cssBlockEnd 返回从 tokens[open]（{）开始的块对应的 } 的位置，没有闭合时返回 len(tokens)。
+func cssBlockEnd(tokens []*cssToken, open int) int {
+	depth := 0
+	for i := open; i < len(tokens); i++ {
+		switch tokens[i].typ {
+		case cssOpenCurly:
+			depth++
+		case cssCloseCurly:
+			depth--
+			if 0 == depth {
+				return i
+			}
+		}
+	}
+	return len(tokens)
+}
+
+func trimCSSWhitespace(tokens []*cssToken) []*cssToken {
+	for 0 < len(tokens) && cssWhitespace == tokens[0].typ {
+		tokens = tokens[1:]
+	}
+	for 0 < len(tokens) && cssWhitespace == tokens[len(tokens)-1].typ {
+		tokens = tokens[:len(tokens)-1]
+	}
+	return tokens
+}
+
+// serializeCSS 将记号序列化为 CSS 文本，标识符和字符串会被重新转义，< 总是被转义以免提前闭合 <style> 元素。
+func serializeCSS(tokens []*cssToken) string {
+	buf := &strings.Builder{}
+	for _, token := range tokens {
+		switch token.typ {
+		case cssIdent:
+			buf.WriteString(escapeCSSIdent(token.value))
+		case cssFunction:
+			buf.WriteString(escapeCSSIdent(token.value) + "(")
+		case cssAtKeyword:
+			buf.WriteString("@" + escapeCSSIdent(token.value))
+		case cssHash:
+			buf.WriteString("#" + escapeCSSIdent(token.value))
+		case cssString:
+			buf.WriteString(escapeCSSString(token.value))
+		case cssURL:
+			buf.WriteString("url(" + escapeCSSString(token.value) + ")")
+		case cssNumber:
+			buf.WriteString(token.value)
+		case cssPercentage:
+			buf.WriteString(token.value + "%")
+		case cssDimension:
+			buf.WriteString(token.value + escapeCSSIdent(token.unit))
+		case cssWhitespace:
+			buf.WriteString(" ")
+		case cssColon:
+			buf.WriteString(":")
+		case cssSemicolon:
+			buf.WriteString(";")
+		case cssComma:
+			buf.WriteString(",")
+		case cssOpenSquare:
+			buf.WriteString("[")
+		case cssCloseSquare:
+			buf.WriteString("]")
+		case cssOpenParen:
+			buf.WriteString("(")
+		case cssCloseParen:
+			buf.WriteString(")")
+		case cssOpenCurly:
+			buf.WriteString("{")
+		case cssCloseCurly:
+			buf.WriteString("}")
+		case cssDelim:
+			switch token.value {
+			case "<", "\\":
+				buf.WriteString(`\` + strconv.FormatInt(int64(token.value[0]), 16) + " ")
+			default:
+				buf.WriteString(token.value)
+			}
+		}
+	}
+	return buf.String()
+}
+
+var cssIdentSafe = regexp.MustCompile(`^[a-zA-Z0-9_\-\x{80}-\x{10FFFF}]*$`)
+
+func escapeCSSIdent(ident string) string {
+	if cssIdentSafe.MatchString(ident) && (1 > len(ident) || !isCSSDigitRune(rune(ident[0]))) {
+		return ident
+	}
+	buf := &strings.Builder{}
+	for i, c := range ident {
+		if 'a' <= c && 'z' >= c || 'A' <= c && 'Z' >= c || '_' == c || '-' == c || 0x80 <= c || (0 < i && '0' <= c && '9' >= c) {
+			buf.WriteRune(c)
+			continue
+		}
+		buf.WriteString(`\` + strconv.FormatInt(int64(c), 16) + " ")
+	}
+	return buf.String()
+}
+
+func escapeCSSString(str string) string {
+	buf := &strings.Builder{}
+	buf.WriteByte('"')
+	for _, c := range str {
+		if '"' == c || '\\' == c || '<' == c || '>' == c || '&' == c || 0x20 > c || 0x7f == c {
+			buf.WriteString(`\` + strconv.FormatInt(int64(c), 16) + " ")
+			continue
+		}
+		buf.WriteRune(c)
+	}
+	buf.WriteByte('"')
+	return buf.String()
+}
+
+// sanitizeStyleAttr 使用策略 policy 清理经过 HTML 转义的 style 属性值 style，返回同样经过转义的结果。
+func sanitizeStyleAttr(policy *CSSPolicy, style string) string {
+	return html.EscapeString(policy.SanitizeStyle(html.UnescapeString(style)))
+}
+
+type cssTokenType int
+
+const (
+	cssIdent cssTokenType = iota
+	cssFunction
+	cssAtKeyword
+	cssHash
+	cssString
+	cssBadString
+	cssURL
+	cssBadURL
+	cssDelim
+	cssNumber
+	cssPercentage
+	cssDimension
+	cssWhitespace
+	cssCDO
+	cssCDC
+	cssColon
+	cssSemicolon
+	cssComma
+	cssOpenSquare
+	cssCloseSquare
+	cssOpenParen
+	cssCloseParen
+	cssOpenCurly
+	cssCloseCurly
+)
+
+// cssToken 描述了 CSS 记号，value 为还原转义后的名称、字符串、地址或者数值文本。
+type cssToken struct {
+	typ   cssTokenType
+	value string
+	unit  string
+}
+
+// cssTokenizer 实现了 https://www.w3.org/TR/css-syntax-3/#tokenization 中的分词算法。
+type cssTokenizer struct {
+	input []rune
+	pos   int
+}
+
+func newCSSTokenizer(css string) *cssTokenizer {
+	css = strings.NewReplacer("\r\n", "\n", "\r", "\n", "\f", "\n", "\x00", "�").Replace(css)
+	if !utf8.ValidString(css) {
+		css = strings.ToValidUTF8(css, "�")
+	}
+	return &cssTokenizer{input: []rune(css)}
+}
+
+func (t *cssTokenizer) tokenize() (ret []*cssToken) {
+	for {
+		token := t.next()
+		if nil == token {
+			return
+		}
+		ret = append(ret, token)
+	}
+}
+
+func (t *cssTokenizer) peek(n int) rune {
+	if t.pos+n < len(t.input) {
+		return t.input[t.pos+n]
+	}
+	return -1
+}
+
+func (t *cssTokenizer) next() *cssToken {
+	// 注释不产生记号
+	for '/' == t.peek(0) && '*' == t.peek(1) {
+		t.pos += 2
+		for t.pos < len(t.input) && !('*' == t.peek(0) && '/' == t.peek(1)) {
+			t.pos++
+		}
+		t.pos += 2
+	}
+	if t.pos >= len(t.input) {
+		return nil
+	}
+
+	c := t.input[t.pos]
+	switch {
+	case isCSSWhitespace(c):
+		for t.pos < len(t.input) && isCSSWhitespace(t.input[t.pos]) {
+			t.pos++
+		}
+		return &cssToken{typ: cssWhitespace}
+	case '"' == c || '\'' == c:
+		t.pos++
+		return t.consumeString(c)
+	case '#' == c:
+		if isCSSNameChar(t.peek(1)) || t.validEscape(1) {
+			t.pos++
+			return &cssToken{typ: cssHash, value: t.consumeName()}
+		}
+	case '(' == c:
+		t.pos++
+		return &cssToken{typ: cssOpenParen}
+	case ')' == c:
+		t.pos++
+		return &cssToken{typ: cssCloseParen}
+	case '[' == c:
+		t.pos++
+		return &cssToken{typ: cssOpenSquare}
+	case ']' == c:
+		t.pos++
+		return &cssToken{typ: cssCloseSquare}
+	case '{' == c:
+		t.pos++
+		return &cssToken{typ: cssOpenCurly}
+	case '}' == c:
+		t.pos++
+		return &cssToken{typ: cssCloseCurly}
+	case ',' == c:
+		t.pos++
+		return &cssToken{typ: cssComma}
+	case ':' == c:
+		t.pos++
+		return &cssToken{typ: cssColon}
+	case ';' == c:
+		t.pos++
+		return &cssToken{typ: cssSemicolon}
+	case '+' == c || '.' == c:
+		if t.startsNumber(0) {
+			return t.consumeNumeric()
+		}
+	case '-' == c:
+		if t.startsNumber(0) {
+			return t.consumeNumeric()
+		}
+		if '-' == t.peek(1) && '>' == t.peek(2) {
+			t.pos += 3
+			return &cssToken{typ: cssCDC}
+		}
+		if t.startsIdent(0) {
+			return t.consumeIdentLike()
+		}
+	case '<' == c:
+		if '!' == t.peek(1) && '-' == t.peek(2) && '-' == t.peek(3) {
+			t.pos += 4
+			return &cssToken{typ: cssCDO}
+		}
+	case '@' == c:
+		if t.startsIdent(1) {
+			t.pos++
+			return &cssToken{typ: cssAtKeyword, value: t.consumeName()}
+		}
+	case '\\' == c:
+		if t.validEscape(0) {
+			return t.consumeIdentLike()
+		}
+	case isCSSDigitRune(c):
+		return t.consumeNumeric()
+	case isCSSNameStart(c):
+		return t.consumeIdentLike()
+	}
+
+	t.pos++
+	return &cssToken{typ: cssDelim, value: string(c)}
+}
+
+func (t *cssTokenizer) consumeString(quote rune) *cssToken {
+	buf := &strings.Builder{}
+	for t.pos < len(t.input) {
+		c := t.input[t.pos]
+		switch {
+		case quote == c:
+			t.pos++
+			return &cssToken{typ: cssString, value: buf.String()}
+		case '\n' == c:
+			return &cssToken{typ: cssBadString}
+		case '\\' == c:
+			if '\n' == t.peek(1) {
+				t.pos += 2
+				continue
+			}
+			if t.pos+1 >= len(t.input) {
+				t.pos++
+				continue
+			}
+			t.pos++
+			buf.WriteRune(t.consumeEscape())
+		default:
+			buf.WriteRune(c)
+			t.pos++
+		}
+	}
+	return &cssToken{typ: cssString, value: buf.String()}
+}
+
+func (t *cssTokenizer) consumeNumeric() *cssToken {
+	start := t.pos
+	if '+' == t.peek(0) || '-' == t.peek(0) {
+		t.pos++
+	}
+	t.consumeDigits()
+	if '.' == t.peek(0) && isCSSDigitRune(t.peek(1)) {
+		t.pos++
+		t.consumeDigits()
+	}
+	if ('e' == t.peek(0) || 'E' == t.peek(0)) && (isCSSDigitRune(t.peek(1)) || (('+' == t.peek(1) || '-' == t.peek(1)) && isCSSDigitRune(t.peek(2)))) {
+		t.pos += 2
+		t.consumeDigits()
+	}
+	number := string(t.input[start:t.pos])
+
+	if t.startsIdent(0) {
+		return &cssToken{typ: cssDimension, value: number, unit: t.consumeName()}
+	}
+	if '%' == t.peek(0) {
+		t.pos++
+		return &cssToken{typ: cssPercentage, value: number}
+	}
+	return &cssToken{typ: cssNumber, value: number}
+}
+
+func (t *cssTokenizer) consumeDigits() {
+	for isCSSDigitRune(t.peek(0)) {
+		t.pos++
+	}
+}
+
+func (t *cssTokenizer) consumeIdentLike() *cssToken {
+	name := t.consumeName()
+	if '(' != t.peek(0) {
+		return &cssToken{typ: cssIdent, value: name}
+	}
+	t.pos++
+	if "url" != strings.ToLower(name) {
+		return &cssToken{typ: cssFunction, value: name}
+	}
+
+	for isCSSWhitespace(t.peek(0)) && isCSSWhitespace(t.peek(1)) {
+		t.pos++
+	}
+	if next := t.peek(0); '"' == next || '\'' == next || (isCSSWhitespace(next) && ('"' == t.peek(1) || '\'' == t.peek(1))) {
+		return &cssToken{typ: cssFunction, value: name}
+	}
+	return t.consumeURL()
+}
+
+func (t *cssTokenizer) consumeURL() *cssToken {
+	buf := &strings.Builder{}
+	for isCSSWhitespace(t.peek(0)) {
+		t.pos++
+	}
+	for t.pos < len(t.input) {
+		c := t.input[t.pos]
+		switch {
+		case ')' == c:
+			t.pos++
+			return &cssToken{typ: cssURL, value: buf.String()}
+		case isCSSWhitespace(c):
+			for isCSSWhitespace(t.peek(0)) {
+				t.pos++
+			}
+			if ')' == t.peek(0) || -1 == t.peek(0) {
+				t.pos++
+				return &cssToken{typ: cssURL, value: buf.String()}
+			}
+			t.consumeBadURL()
+			return &cssToken{typ: cssBadURL}
+		case '"' == c || '\'' == c || '(' == c || isCSSNonPrintable(c):
+			t.consumeBadURL()
+			return &cssToken{typ: cssBadURL}
+		case '\\' == c:
+			if !t.validEscape(0) {
+				t.consumeBadURL()
+				return &cssToken{typ: cssBadURL}
+			}
+			t.pos++
+			buf.WriteRune(t.consumeEscape())
+		default:
+			buf.WriteRune(c)
+			t.pos++
+		}
+	}
+	return &cssToken{typ: cssURL, value: buf.String()}
+}
+
+func (t *cssTokenizer) consumeBadURL() {
+	for t.pos < len(t.input) {
+		c := t.input[t.pos]
+		t.pos++
+		if ')' == c {
+			return
+		}
+		if '\\' == c && t.pos < len(t.input) {
+			t.pos++
+		}
+	}
+}
+
+func (t *cssTokenizer) consumeName() string {
+	buf := &strings.Builder{}
+	for t.pos < len(t.input) {
+		c := t.input[t.pos]
+		if isCSSNameChar(c) {
+			buf.WriteRune(c)
+			t.pos++
+			continue
+		}
+		if t.validEscape(0) {
+			t.pos++
+			buf.WriteRune(t.consumeEscape())
+			continue
+		}
+		break
+	}
+	return buf.String()
+}
+
+// consumeEscape 消费 \ 之后的转义序列并返回对应的字符。
+func (t *cssTokenizer) consumeEscape() rune {
+	if t.pos >= len(t.input) {
+		return utf8.RuneError
+	}
+
+	c := t.input[t.pos]
+	if !isCSSHex(c) {
+		t.pos++
+		return c
+	}
+
+	hex := 0
+	for i := 0; i < 6 && isCSSHex(t.peek(0)); i++ {
+		v, _ := strconv.ParseInt(string(t.input[t.pos]), 16, 32)
+		hex = hex*16 + int(v)
+		t.pos++
+	}
+	if isCSSWhitespace(t.peek(0)) {
+		t.pos++
+	}
+	if 0 == hex || (0xD800 <= hex && 0xDFFF >= hex) || 0x10FFFF < hex {
+		return utf8.RuneError
+	}
+	return rune(hex)
+}
+
+func (t *cssTokenizer) validEscape(n int) bool {
+	return '\\' == t.peek(n) && '\n' != t.peek(n+1) && -1 != t.peek(n+1)
+}
+
+func (t *cssTokenizer) startsIdent(n int) bool {
+	c := t.peek(n)
+	switch {
+	case '-' == c:
+		return isCSSNameStart(t.peek(n+1)) || '-' == t.peek(n+1) || t.validEscape(n+1)
+	case '\\' == c:
+		return t.validEscape(n)
+	}
+	return isCSSNameStart(c)
+}
+
+func (t *cssTokenizer) startsNumber(n int) bool {
+	c := t.peek(n)
+	switch c {
+	case '+', '-':
+		return isCSSDigitRune(t.peek(n+1)) || ('.' == t.peek(n+1) && isCSSDigitRune(t.peek(n+2)))
+	case '.':
+		return isCSSDigitRune(t.peek(n + 1))
+	}
+	return isCSSDigitRune(c)
+}
+
+func isCSSWhitespace(c rune) bool {
+	return ' ' == c || '\t' == c || '\n' == c
+}
+
+func isCSSDigitRune(c rune) bool {
+	return '0' <= c && '9' >= c
+}
+
+func isCSSHex(c rune) bool {
+	return isCSSDigitRune(c) || ('a' <= c && 'f' >= c) || ('A' <= c && 'F' >= c)
+}
+
+func isCSSNameStart(c rune) bool {
+	return ('a' <= c && 'z' >= c) || ('A' <= c && 'Z' >= c) || '_' == c || utf8.RuneSelf <= c
+}
+
+func isCSSNameChar(c rune) bool {
+	return isCSSNameStart(c) || isCSSDigitRune(c) || '-' == c
+}
+
+func isCSSNonPrintable(c rune) bool {
+	return (0 <= c && 0x08 >= c) || 0x0B == c || (0x0E <= c && 0x1F >= c) || 0x7F == c
+}
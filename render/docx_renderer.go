@@ -0,0 +1,1088 @@
+package render
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pafthang/md/ast"
+	"github.com/pafthang/md/parse"
+	"github.com/pafthang/md/util"
+)
+
+// DocxRenderer 描述了 DOCX 渲染器。
+//
+// 和 ProtyleExportDocxRenderer 输出 HTML 再交由外部工具（比如 Pandoc）转换不同，DocxRenderer 直接从语法树生成 Office Open XML 包（.docx），
+// 包内包含 document.xml、styles.xml、numbering.xml、footnotes.xml 以及图片和超链接的关系文件。
+//
+// 数学公式按照原始文本输出，HTML 块和行级 HTML 会被忽略。
+type DocxRenderer struct {
+	*BaseRenderer
+
+	// ImageLoader 用于加载图片数据，参数 dest 为经过 LinkBase、LinkPrefix 处理后的图片地址，加载失败时图片将渲染为替代文本。
+	// 图片地址来自 Markdown 原文，所以默认为 nil 不加载任何图片，需要嵌入本地图片时可以使用 NewLocalImageLoader 限定目录和大小。
+	ImageLoader func(dest string) (data []byte, err error)
+
+	paragraph    bool                  // 是否已经打开段落 <w:p>
+	runProps     map[string]int        // 行级格式计数，比如加粗、倾斜等，嵌套时进行累加
+	lists        []*docxList           // 列表栈
+	quotes       int                   // 引述（包括提示块）嵌套深度
+	tables       int                   // 表格嵌套深度
+	tableHead    bool                  // 是否正在渲染表头
+	nums         []string              // numbering.xml 中的编号实例 <w:num>
+	rels         []string              // word/_rels/document.xml.rels 中的动态关系
+	footnoteRels []string              // word/_rels/footnotes.xml.rels 中的关系，脚注中的链接和图片需要使用脚注部件自己的关系
+	media        []*docxMedia          // word/media 下的图片
+	footnoteIDs  map[*ast.Node]int     // 脚注定义到脚注 ID 的映射
+	footnoteDefs []*ast.Node           // 按照引用顺序排列的脚注定义
+	footnoteRef  bool                  // 是否需要在下一个段落开头输出脚注标号
+	bookmarkID   int                   // 书签 ID
+	drawingID    int                   // 图片 ID
+	mediaIndex   map[string]*docxMedia // 图片地址到图片的映射，避免重复打包
+}
+
+// docxList 描述了正在渲染的列表。
+type docxList struct {
+	numID   int  // 编号实例 ID，0 表示不使用编号（任务列表）
+	level   int  // 嵌套层级
+	pending bool // 当前列表项的编号是否还未输出
+}
+
+// docxPart 描述了 .docx 包内的一个部件。
+type docxPart struct {
+	name string // 包内路径
+	data []byte // 部件内容
+}
+
+// docxMedia 描述了打包的图片。
+type docxMedia struct {
+	name   string // 包内文件名，比如 image1.png
+	relID  string // 正文中使用的关系 ID
+	fnRel  string // 脚注中使用的关系 ID
+	data   []byte // 图片数据
+	width  int    // 宽度（像素）
+	height int    // 高度（像素）
+}
+
+const (
+	docxBulletNumID  = 1       // 无序列表编号实例 ID
+	docxTextWidth    = 9360    // 正文宽度（缇），Letter 纸张减去左右各 1 英寸页边距
+	docxMaxImageEMU  = 5943600 // 图片最大宽度（EMU），即正文宽度 6.5 英寸
+	docxEMUPerPixel  = 9525    // 96 DPI 下每像素对应的 EMU
+	docxIndentStep   = 720     // 每级缩进（缇）
+	docxHangingWidth = 360     // 列表编号悬挂缩进（缇）
+	docxFirstDynRel  = 5       // 第一个动态关系 ID 序号，前面的序号被 styles、numbering、footnotes 和 settings 占用
+	docxNamespaces   = ` xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships" xmlns:wp="http://schemas.openxmlformats.org/drawingml/2006/wordprocessingDrawing" xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" xmlns:pic="http://schemas.openxmlformats.org/drawingml/2006/picture"`
+)
+
+// NewDocxRenderer 创建一个 DOCX 渲染器。
+func NewDocxRenderer(tree *parse.Tree, options *Options) *DocxRenderer {
+	ret := &DocxRenderer{BaseRenderer: NewBaseRenderer(tree, options)}
+	ret.RendererFuncs[ast.NodeDocument] = ret.renderDocument
+	ret.RendererFuncs[ast.NodeParagraph] = ret.renderParagraph
+	ret.RendererFuncs[ast.NodeText] = ret.renderText
+	ret.RendererFuncs[ast.NodeCodeSpan] = ret.renderCodeSpan
+	ret.RendererFuncs[ast.NodeCodeSpanContent] = ret.renderText
+	ret.RendererFuncs[ast.NodeCodeBlockCode] = ret.renderCodeBlockCode
+	ret.RendererFuncs[ast.NodeMathBlockContent] = ret.renderMathBlockContent
+	ret.RendererFuncs[ast.NodeInlineMath] = ret.renderInlineMath
+	ret.RendererFuncs[ast.NodeInlineMathContent] = ret.renderText
+	ret.RendererFuncs[ast.NodeEmphasis] = ret.renderEmphasis
+	ret.RendererFuncs[ast.NodeStrong] = ret.renderStrong
+	ret.RendererFuncs[ast.NodeBlockquote] = ret.renderBlockquote
+	ret.RendererFuncs[ast.NodeAdmonition] = ret.renderAdmonition
+	ret.RendererFuncs[ast.NodeHeading] = ret.renderHeading
+	ret.RendererFuncs[ast.NodeList] = ret.renderList
+	ret.RendererFuncs[ast.NodeListItem] = ret.renderListItem
+	ret.RendererFuncs[ast.NodeTaskListItemMarker] = ret.renderTaskListItemMarker
+	ret.RendererFuncs[ast.NodeThematicBreak] = ret.renderThematicBreak
+	ret.RendererFuncs[ast.NodeHardBreak] = ret.renderHardBreak
+	ret.RendererFuncs[ast.NodeSoftBreak] = ret.renderSoftBreak
+	ret.RendererFuncs[ast.NodeBr] = ret.renderHardBreak
+	ret.RendererFuncs[ast.NodeHTMLBlock] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeInlineHTML] = ret.renderInlineHTML
+	ret.RendererFuncs[ast.NodeLink] = ret.renderLink
+	ret.RendererFuncs[ast.NodeImage] = ret.renderImage
+	ret.RendererFuncs[ast.NodeLinkText] = ret.renderLinkText
+	ret.RendererFuncs[ast.NodeHTMLEntity] = ret.renderText
+	ret.RendererFuncs[ast.NodeBackslashContent] = ret.renderText
+	ret.RendererFuncs[ast.NodeStrikethrough] = ret.renderStrikethrough
+	ret.RendererFuncs[ast.NodeTable] = ret.renderTable
+	ret.RendererFuncs[ast.NodeTableHead] = ret.renderTableHead
+	ret.RendererFuncs[ast.NodeTableRow] = ret.renderTableRow
+	ret.RendererFuncs[ast.NodeTableCell] = ret.renderTableCell
+	ret.RendererFuncs[ast.NodeEmojiUnicode] = ret.renderText
+	ret.RendererFuncs[ast.NodeEmojiImg] = ret.renderEmojiImg
+	ret.RendererFuncs[ast.NodeFootnotesDefBlock] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeFootnotesDef] = ret.renderFootnotesDef
+	ret.RendererFuncs[ast.NodeFootnotesRef] = ret.renderFootnotesRef
+	ret.RendererFuncs[ast.NodeToC] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeYamlFrontMatter] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeLinkRefDefBlock] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeBlockRefText] = ret.renderText
+	ret.RendererFuncs[ast.NodeBlockRefDynamicText] = ret.renderText
+	ret.RendererFuncs[ast.NodeFileAnnotationRefText] = ret.renderText
+	ret.RendererFuncs[ast.NodeWikiLink] = ret.renderWikiLink
+	ret.RendererFuncs[ast.NodeWikiLinkEmbed] = ret.renderWikiLink
+	ret.RendererFuncs[ast.NodeMark] = ret.renderMark
+	ret.RendererFuncs[ast.NodeSup] = ret.renderSup
+	ret.RendererFuncs[ast.NodeSub] = ret.renderSub
+	ret.RendererFuncs[ast.NodeKbd] = ret.renderKbd
+	ret.RendererFuncs[ast.NodeUnderline] = ret.renderUnderline
+	ret.RendererFuncs[ast.NodeTextMark] = ret.renderTextMark
+	ret.RendererFuncs[ast.NodeKramdownBlockIAL] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeKramdownSpanIAL] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeBlockQueryEmbed] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeIFrame] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeAudio] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeVideo] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeWidget] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeAttributeView] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeGitConflict] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeCustomBlock] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeDefinitionTerm] = ret.renderDefinitionTerm
+	ret.RendererFuncs[ast.NodeDefinitionDescription] = ret.renderDefinitionDescription
+	ret.DefaultRendererFunc = ret.renderDefault
+	return ret
+}
+
+// Render 渲染并打包输出 .docx 文件字节数组。
+func (r *DocxRenderer) Render() (output []byte) {
+	r.runProps = map[string]int{}
+	r.lists, r.quotes, r.tables, r.paragraph = nil, 0, 0, false
+	r.nums = []string{`<w:num w:numId="1"><w:abstractNumId w:val="1"/></w:num>`}
+	r.rels, r.footnoteRels, r.media, r.mediaIndex = nil, nil, nil, map[string]*docxMedia{}
+	r.footnoteIDs, r.footnoteDefs = map[*ast.Node]int{}, nil
+
+	body := r.BaseRenderer.Render()
+
+	r.Writer = &bytes.Buffer{}
+	r.RenderingFootnotes = true
+	for i := 0; i < len(r.footnoteDefs); i++ { // 渲染脚注定义时可能还会引用新的脚注定义
+		def := r.footnoteDefs[i]
+		r.WriteString("<w:footnote w:id=\"" + strconv.Itoa(r.footnoteIDs[def]) + "\">")
+		r.footnoteRef = true
+		r.walk(def)
+		if r.footnoteRef {
+			r.beginParagraph("FootnoteText", "", "")
+		}
+		r.endParagraph()
+		r.WriteString("</w:footnote>")
+	}
+	r.RenderingFootnotes = false
+	footnotes := r.Writer.Bytes()
+
+	output, err := r.pack(body, footnotes)
+	if nil != err {
+		r.setErr(err)
+		return nil
+	}
+	return
+}
+
+// walk 使用和 BaseRenderer.Render 相同的分派规则渲染以 node 为根的子树。
+func (r *DocxRenderer) walk(node *ast.Node) {
+	ast.Walk(node, func(n *ast.Node, entering bool) ast.WalkStatus {
+		if extRender := r.ExtRendererFuncs[n.Type]; nil != extRender {
+			output, status := extRender(n, entering)
+			r.WriteString(output)
+			return status
+		}
+		if render := r.RendererFuncs[n.Type]; nil != render {
+			return render(n, entering)
+		}
+		return r.DefaultRendererFunc(n, entering)
+	})
+}
+
+// pack 将正文和脚注打包为 .docx 文件。
+func (r *DocxRenderer) pack(body, footnotes []byte) (ret []byte, err error) {
+	buf := &bytes.Buffer{}
+	w := zip.NewWriter(buf)
+	parts := []*docxPart{
+		{"[Content_Types].xml", []byte(docxContentTypes)},
+		{"_rels/.rels", []byte(docxPackageRels)},
+		{"word/document.xml", r.documentXML(body)},
+		{"word/styles.xml", []byte(docxStyles)},
+		{"word/numbering.xml", r.numberingXML()},
+		{"word/footnotes.xml", footnotesXML(footnotes)},
+		{"word/settings.xml", []byte(docxSettings)},
+		{"word/_rels/document.xml.rels", r.documentRelsXML()},
+	}
+	if 0 < len(r.footnoteRels) {
+		parts = append(parts, &docxPart{"word/_rels/footnotes.xml.rels", relsXML(r.footnoteRels)})
+	}
+	for _, m := range r.media {
+		parts = append(parts, &docxPart{"word/media/" + m.name, m.data})
+	}
+
+	for _, part := range parts {
+		var pw io.Writer
+		if pw, err = w.Create(part.name); nil != err {
+			return
+		}
+		if _, err = pw.Write(part.data); nil != err {
+			return
+		}
+	}
+	if err = w.Close(); nil != err {
+		return
+	}
+	ret = buf.Bytes()
+	return
+}
+
+func (r *DocxRenderer) documentXML(body []byte) []byte {
+	buf := &bytes.Buffer{}
+	buf.WriteString(xml.Header)
+	buf.WriteString("<w:document" + docxNamespaces + "><w:body>")
+	buf.Write(body)
+	buf.WriteString(`<w:sectPr><w:pgSz w:w="12240" w:h="15840"/><w:pgMar w:top="1440" w:right="1440" w:bottom="1440" w:left="1440" w:header="720" w:footer="720" w:gutter="0"/></w:sectPr>`)
+	buf.WriteString("</w:body></w:document>")
+	return buf.Bytes()
+}
+
+func (r *DocxRenderer) numberingXML() []byte {
+	buf := &bytes.Buffer{}
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<w:numbering xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">`)
+	bullets := []string{"•", "◦", "▪"}
+	buf.WriteString(`<w:abstractNum w:abstractNumId="1"><w:multiLevelType w:val="hybridMultilevel"/>`)
+	for i := 0; i < 9; i++ {
+		buf.WriteString(`<w:lvl w:ilvl="` + strconv.Itoa(i) + `"><w:start w:val="1"/><w:numFmt w:val="bullet"/><w:lvlText w:val="` + bullets[i%len(bullets)] + `"/><w:lvlJc w:val="left"/>`)
+		buf.WriteString(`<w:pPr><w:ind w:left="` + strconv.Itoa(docxIndentStep*(i+1)) + `" w:hanging="` + strconv.Itoa(docxHangingWidth) + `"/></w:pPr></w:lvl>`)
+	}
+	buf.WriteString(`</w:abstractNum>`)
+	buf.WriteString(`<w:abstractNum w:abstractNumId="2"><w:multiLevelType w:val="hybridMultilevel"/>`)
+	for i := 0; i < 9; i++ {
+		buf.WriteString(`<w:lvl w:ilvl="` + strconv.Itoa(i) + `"><w:start w:val="1"/><w:numFmt w:val="decimal"/><w:lvlText w:val="%` + strconv.Itoa(i+1) + `."/><w:lvlJc w:val="left"/>`)
+		buf.WriteString(`<w:pPr><w:ind w:left="` + strconv.Itoa(docxIndentStep*(i+1)) + `" w:hanging="` + strconv.Itoa(docxHangingWidth) + `"/></w:pPr></w:lvl>`)
+	}
+	buf.WriteString(`</w:abstractNum>`)
+	for _, num := range r.nums {
+		buf.WriteString(num)
+	}
+	buf.WriteString(`</w:numbering>`)
+	return buf.Bytes()
+}
+
+func footnotesXML(footnotes []byte) []byte {
+	buf := &bytes.Buffer{}
+	buf.WriteString(xml.Header)
+	buf.WriteString("<w:footnotes" + docxNamespaces + ">")
+	buf.WriteString(`<w:footnote w:type="separator" w:id="-1"><w:p><w:pPr><w:spacing w:after="0" w:line="240" w:lineRule="auto"/></w:pPr><w:r><w:separator/></w:r></w:p></w:footnote>`)
+	buf.WriteString(`<w:footnote w:type="continuationSeparator" w:id="0"><w:p><w:pPr><w:spacing w:after="0" w:line="240" w:lineRule="auto"/></w:pPr><w:r><w:continuationSeparator/></w:r></w:p></w:footnote>`)
+	buf.Write(footnotes)
+	buf.WriteString("</w:footnotes>")
+	return buf.Bytes()
+}
+
+func (r *DocxRenderer) documentRelsXML() []byte {
+	rels := []string{
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`,
+		`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/numbering" Target="numbering.xml"/>`,
+		`<Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/footnotes" Target="footnotes.xml"/>`,
+		`<Relationship Id="rId4" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/settings" Target="settings.xml"/>`,
+	}
+	return relsXML(append(rels, r.rels...))
+}
+
+func relsXML(rels []string) []byte {
+	buf := &bytes.Buffer{}
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for _, rel := range rels {
+		buf.WriteString(rel)
+	}
+	buf.WriteString(`</Relationships>`)
+	return buf.Bytes()
+}
+
+// addRel 添加一个动态关系并返回关系 ID，渲染脚注时关系添加到脚注部件的关系文件中。
+func (r *DocxRenderer) addRel(typ, target string, external bool) (id string) {
+	rels, first := &r.rels, docxFirstDynRel
+	if r.RenderingFootnotes {
+		rels, first = &r.footnoteRels, 1
+	}
+	id = "rId" + strconv.Itoa(first+len(*rels))
+	rel := `<Relationship Id="` + id + `" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/` + typ + `" Target="` + docxEscape(target) + `"`
+	if external {
+		rel += ` TargetMode="External"`
+	}
+	*rels = append(*rels, rel+"/>")
+	return
+}
+
+// beginParagraph 打开一个段落，段落属性由 style、边框 pBdr、对齐 jc 以及当前所处的列表、引述上下文共同决定。
+func (r *DocxRenderer) beginParagraph(style, pBdr, jc string) {
+	r.endParagraph()
+	r.WriteString("<w:p><w:pPr>")
+	if "" == style && 0 < r.quotes && 1 > r.tables {
+		style = "Quote"
+	}
+	if "" != style {
+		r.WriteString(`<w:pStyle w:val="` + style + `"/>`)
+	}
+
+	var left, hanging int
+	if 1 > r.tables {
+		left = docxIndentStep * r.quotes
+		if length := len(r.lists); 0 < length {
+			list := r.lists[length-1]
+			if list.pending && 0 < list.numID {
+				r.WriteString(`<w:numPr><w:ilvl w:val="` + strconv.Itoa(list.level) + `"/><w:numId w:val="` + strconv.Itoa(list.numID) + `"/></w:numPr>`)
+				hanging = docxHangingWidth
+			}
+			list.pending = false
+			left += docxIndentStep * length
+		}
+	}
+	r.WriteString(pBdr)
+	if 0 < left && (0 < r.quotes || 0 == hanging) {
+		r.WriteString(`<w:ind w:left="` + strconv.Itoa(left) + `"`)
+		if 0 < hanging {
+			r.WriteString(` w:hanging="` + strconv.Itoa(hanging) + `"`)
+		}
+		r.WriteString("/>")
+	}
+	if "" != jc {
+		r.WriteString(`<w:jc w:val="` + jc + `"/>`)
+	}
+	r.WriteString("</w:pPr>")
+	r.paragraph = true
+
+	if r.footnoteRef {
+		r.footnoteRef = false
+		r.WriteString(`<w:r><w:rPr><w:rStyle w:val="FootnoteReference"/></w:rPr><w:footnoteRef/></w:r><w:r><w:t xml:space="preserve"> </w:t></w:r>`)
+	}
+}
+
+// endParagraph 关闭当前段落。
+func (r *DocxRenderer) endParagraph() {
+	if r.paragraph {
+		r.WriteString("</w:p>")
+		r.paragraph = false
+	}
+}
+
+// writeRun 使用当前的行级格式输出文本 text，文本中的换行符输出为 <w:br/>，制表符输出为 <w:tab/>。
+func (r *DocxRenderer) writeRun(text string) {
+	if "" == text {
+		return
+	}
+	if !r.paragraph {
+		r.beginParagraph("", "", "")
+	}
+
+	r.WriteString("<w:r>")
+	r.WriteString(r.rPr())
+	for i, line := range strings.Split(text, "\n") {
+		if 0 < i {
+			r.WriteString("<w:br/>")
+		}
+		for j, seg := range strings.Split(line, "\t") {
+			if 0 < j {
+				r.WriteString("<w:tab/>")
+			}
+			if "" != seg {
+				r.WriteString(`<w:t xml:space="preserve">` + docxEscape(seg) + "</w:t>")
+			}
+		}
+	}
+	r.WriteString("</w:r>")
+}
+
+// rPr 根据当前行级格式计数生成 <w:rPr>。
+func (r *DocxRenderer) rPr() string {
+	buf := &bytes.Buffer{}
+	if 0 < r.runProps["link"] {
+		buf.WriteString(`<w:rStyle w:val="Hyperlink"/>`)
+	} else if 0 < r.runProps["code"] {
+		buf.WriteString(`<w:rStyle w:val="VerbatimChar"/>`)
+	}
+	if 0 < r.runProps["math"] {
+		buf.WriteString(`<w:rFonts w:ascii="Cambria Math" w:hAnsi="Cambria Math"/>`)
+	}
+	if 0 < r.runProps["bold"] {
+		buf.WriteString("<w:b/><w:bCs/>")
+	}
+	if 0 < r.runProps["italic"] || 0 < r.runProps["math"] {
+		buf.WriteString("<w:i/><w:iCs/>")
+	}
+	if 0 < r.runProps["strike"] {
+		buf.WriteString("<w:strike/>")
+	}
+	if 0 < r.runProps["mark"] {
+		buf.WriteString(`<w:highlight w:val="yellow"/>`)
+	}
+	if 0 < r.runProps["underline"] {
+		buf.WriteString(`<w:u w:val="single"/>`)
+	}
+	if 0 < r.runProps["sup"] {
+		buf.WriteString(`<w:vertAlign w:val="superscript"/>`)
+	} else if 0 < r.runProps["sub"] {
+		buf.WriteString(`<w:vertAlign w:val="subscript"/>`)
+	}
+	if 1 > buf.Len() {
+		return ""
+	}
+	return "<w:rPr>" + buf.String() + "</w:rPr>"
+}
+
+// toggle 在进入节点时增加格式 prop 的计数，在离开节点时减少计数。
+func (r *DocxRenderer) toggle(prop string, entering bool) ast.WalkStatus {
+	if entering {
+		r.runProps[prop]++
+	} else {
+		r.runProps[prop]--
+	}
+	return ast.WalkContinue
+}
+
+// beginHyperlink 打开超链接，以 # 开头的地址作为文档内书签锚点。
+func (r *DocxRenderer) beginHyperlink(dest string) {
+	if !r.paragraph {
+		r.beginParagraph("", "", "")
+	}
+	if strings.HasPrefix(dest, "#") {
+		r.WriteString(`<w:hyperlink w:anchor="` + docxEscape(dest[1:]) + `">`)
+	} else {
+		r.WriteString(`<w:hyperlink r:id="` + r.addRel("hyperlink", dest, true) + `">`)
+	}
+	r.runProps["link"]++
+}
+
+func (r *DocxRenderer) endHyperlink() {
+	r.runProps["link"]--
+	r.WriteString("</w:hyperlink>")
+}
+
+// linkDest 返回链接节点 node 的链接地址，启用 Sanitize 时将过滤 javascript: 地址。
+func (r *DocxRenderer) linkDest(node *ast.Node) string {
+	dest := node.ChildByType(ast.NodeLinkDest)
+	if nil == dest {
+		return ""
+	}
+	destTokens, _ := r.ResolveLink(node, dest.Tokens)
+	if 0 < len(destTokens) && nil == r.sanitizeURL(destTokens) {
+		return ""
+	}
+	return util.BytesToStr(r.LinkPath(destTokens))
+}
+
+func (r *DocxRenderer) renderDefault(node *ast.Node, entering bool) ast.WalkStatus {
+	return ast.WalkContinue
+}
+
+func (r *DocxRenderer) renderSkip(node *ast.Node, entering bool) ast.WalkStatus {
+	return ast.WalkSkipChildren
+}
+
+func (r *DocxRenderer) renderDocument(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering {
+		r.endParagraph()
+	}
+	return ast.WalkContinue
+}
+
+func (r *DocxRenderer) renderParagraph(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if !r.paragraph { // 任务列表项标记可能已经打开了段落
+			style := ""
+			if r.RenderingFootnotes {
+				style = "FootnoteText"
+			}
+			r.beginParagraph(style, "", "")
+		}
+	} else {
+		r.endParagraph()
+	}
+	return ast.WalkContinue
+}
+
+func (r *DocxRenderer) renderText(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.writeRun(util.BytesToStr(node.Tokens))
+	}
+	return ast.WalkContinue
+}
+
+func (r *DocxRenderer) renderCodeSpan(node *ast.Node, entering bool) ast.WalkStatus {
+	return r.toggle("code", entering)
+}
+
+func (r *DocxRenderer) renderKbd(node *ast.Node, entering bool) ast.WalkStatus {
+	return r.toggle("code", entering)
+}
+
+func (r *DocxRenderer) renderInlineMath(node *ast.Node, entering bool) ast.WalkStatus {
+	return r.toggle("math", entering)
+}
+
+func (r *DocxRenderer) renderEmphasis(node *ast.Node, entering bool) ast.WalkStatus {
+	return r.toggle("italic", entering)
+}
+
+func (r *DocxRenderer) renderStrong(node *ast.Node, entering bool) ast.WalkStatus {
+	return r.toggle("bold", entering)
+}
+
+func (r *DocxRenderer) renderStrikethrough(node *ast.Node, entering bool) ast.WalkStatus {
+	return r.toggle("strike", entering)
+}
+
+func (r *DocxRenderer) renderMark(node *ast.Node, entering bool) ast.WalkStatus {
+	return r.toggle("mark", entering)
+}
+
+func (r *DocxRenderer) renderSup(node *ast.Node, entering bool) ast.WalkStatus {
+	return r.toggle("sup", entering)
+}
+
+func (r *DocxRenderer) renderSub(node *ast.Node, entering bool) ast.WalkStatus {
+	return r.toggle("sub", entering)
+}
+
+func (r *DocxRenderer) renderUnderline(node *ast.Node, entering bool) ast.WalkStatus {
+	return r.toggle("underline", entering)
+}
+
+func (r *DocxRenderer) renderCodeBlockCode(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.beginParagraph("SourceCode", "", "")
+		code := strings.TrimSuffix(util.BytesToStr(node.Tokens), "\n")
+		r.writeRun(code)
+		r.endParagraph()
+	}
+	return ast.WalkContinue
+}
+
+func (r *DocxRenderer) renderMathBlockContent(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.beginParagraph("MathBlock", "", "")
+		r.runProps["math"]++
+		r.writeRun(strings.TrimSpace(util.BytesToStr(node.Tokens)))
+		r.runProps["math"]--
+		r.endParagraph()
+	}
+	return ast.WalkContinue
+}
+
+func (r *DocxRenderer) renderHeading(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.beginParagraph("Heading"+strconv.Itoa(node.HeadingLevel), "", "")
+		r.bookmarkID++
+		r.WriteString(`<w:bookmarkStart w:id="` + strconv.Itoa(r.bookmarkID) + `" w:name="` + docxEscape(r.HeadingID(node)) + `"/>`)
+	} else {
+		r.WriteString(`<w:bookmarkEnd w:id="` + strconv.Itoa(r.bookmarkID) + `"/>`)
+		r.endParagraph()
+	}
+	return ast.WalkContinue
+}
+
+func (r *DocxRenderer) renderThematicBreak(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.beginParagraph("", `<w:pBdr><w:bottom w:val="single" w:sz="6" w:space="1" w:color="auto"/></w:pBdr>`, "")
+		r.endParagraph()
+	}
+	return ast.WalkContinue
+}
+
+func (r *DocxRenderer) renderHardBreak(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering && r.paragraph {
+		r.WriteString("<w:r><w:br/></w:r>")
+	}
+	return ast.WalkContinue
+}
+
+func (r *DocxRenderer) renderSoftBreak(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if r.Options.SoftBreak2HardBreak {
+			return r.renderHardBreak(node, entering)
+		}
+		r.writeRun(" ")
+	}
+	return ast.WalkContinue
+}
+
+func (r *DocxRenderer) renderInlineHTML(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering && bytes.HasPrefix(bytes.ToLower(node.Tokens), []byte("<br")) {
+		return r.renderHardBreak(node, entering)
+	}
+	return ast.WalkContinue
+}
+
+func (r *DocxRenderer) renderBlockquote(node *ast.Node, entering bool) ast.WalkStatus {
+	r.endParagraph()
+	if entering {
+		r.quotes++
+	} else {
+		r.quotes--
+	}
+	return ast.WalkContinue
+}
+
+func (r *DocxRenderer) renderAdmonition(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.beginParagraph("AdmonitionTitle", "", "")
+		r.writeRun(AdmonitionTitle(node))
+		r.endParagraph()
+	}
+	return r.renderBlockquote(node, entering)
+}
+
+func (r *DocxRenderer) renderDefinitionTerm(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.beginParagraph("DefinitionTerm", "", "")
+	} else {
+		r.endParagraph()
+	}
+	return ast.WalkContinue
+}
+
+func (r *DocxRenderer) renderDefinitionDescription(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.beginParagraph("Definition", "", "")
+	} else {
+		r.endParagraph()
+	}
+	return ast.WalkContinue
+}
+
+func (r *DocxRenderer) renderList(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if length := len(r.lists); 0 < length && r.lists[length-1].pending {
+			// 列表项直接以子列表开头时需要先输出一个空的编号段落
+			r.beginParagraph("", "", "")
+		}
+		r.endParagraph()
+
+		list := &docxList{level: len(r.lists)}
+		switch {
+		case 1 == node.ListData.Typ || (3 == node.ListData.Typ && 0 == node.ListData.BulletChar):
+			list.numID = len(r.nums) + 1
+			start := node.ListData.Start
+			if 1 > start {
+				start = 1
+			}
+			r.nums = append(r.nums, `<w:num w:numId="`+strconv.Itoa(list.numID)+`"><w:abstractNumId w:val="2"/><w:lvlOverride w:ilvl="`+strconv.Itoa(list.level)+`"><w:startOverride w:val="`+strconv.Itoa(start)+`"/></w:lvlOverride></w:num>`)
+		case 3 == node.ListData.Typ:
+			list.numID = 0 // 任务列表使用复选框字符代替项目符号
+		default:
+			list.numID = docxBulletNumID
+		}
+		if 8 < list.level {
+			list.level = 8
+		}
+		r.lists = append(r.lists, list)
+	} else {
+		r.endParagraph()
+		r.lists = r.lists[:len(r.lists)-1]
+	}
+	return ast.WalkContinue
+}
+
+func (r *DocxRenderer) renderListItem(node *ast.Node, entering bool) ast.WalkStatus {
+	list := r.lists[len(r.lists)-1]
+	if entering {
+		r.endParagraph()
+		list.pending = true
+	} else {
+		if list.pending { // 空列表项
+			r.beginParagraph("", "", "")
+		}
+		r.endParagraph()
+	}
+	return ast.WalkContinue
+}
+
+func (r *DocxRenderer) renderTaskListItemMarker(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if node.TaskListItemChecked {
+			r.writeRun("☒")
+		} else {
+			r.writeRun("☐")
+		}
+	}
+	return ast.WalkContinue
+}
+
+func (r *DocxRenderer) renderTable(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.endParagraph()
+		cols := len(node.TableAligns)
+		if 1 > cols {
+			cols = 1
+		}
+		colWidth := docxTextWidth / cols
+		r.WriteString(`<w:tbl><w:tblPr><w:tblStyle w:val="Table"/><w:tblW w:w="` + strconv.Itoa(docxTextWidth) + `" w:type="dxa"/>`)
+		r.WriteString(`<w:tblBorders><w:top w:val="single" w:sz="4" w:space="0" w:color="auto"/><w:left w:val="single" w:sz="4" w:space="0" w:color="auto"/><w:bottom w:val="single" w:sz="4" w:space="0" w:color="auto"/><w:right w:val="single" w:sz="4" w:space="0" w:color="auto"/><w:insideH w:val="single" w:sz="4" w:space="0" w:color="auto"/><w:insideV w:val="single" w:sz="4" w:space="0" w:color="auto"/></w:tblBorders>`)
+		r.WriteString(`<w:tblLook w:val="0020" w:firstRow="1" w:lastRow="0" w:firstColumn="0" w:lastColumn="0" w:noHBand="0" w:noVBand="0"/></w:tblPr><w:tblGrid>`)
+		for i := 0; i < cols; i++ {
+			r.WriteString(`<w:gridCol w:w="` + strconv.Itoa(colWidth) + `"/>`)
+		}
+		r.WriteString("</w:tblGrid>")
+		r.tables++
+	} else {
+		r.tables--
+		r.WriteString("</w:tbl>")
+	}
+	return ast.WalkContinue
+}
+
+func (r *DocxRenderer) renderTableHead(node *ast.Node, entering bool) ast.WalkStatus {
+	r.tableHead = entering
+	return ast.WalkContinue
+}
+
+func (r *DocxRenderer) renderTableRow(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString("<w:tr>")
+		if r.tableHead {
+			r.WriteString("<w:trPr><w:tblHeader/></w:trPr>")
+		}
+	} else {
+		r.WriteString("</w:tr>")
+	}
+	return ast.WalkContinue
+}
+
+func (r *DocxRenderer) renderTableCell(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		colWidth := docxTextWidth
+		table := node.Parent
+		for ; nil != table && ast.NodeTable != table.Type; table = table.Parent {
+		}
+		if nil != table && 0 < len(table.TableAligns) {
+			colWidth /= len(table.TableAligns)
+		}
+		r.WriteString(`<w:tc><w:tcPr><w:tcW w:w="` + strconv.Itoa(colWidth) + `" w:type="dxa"/></w:tcPr>`)
+		var jc string
+		switch node.TableCellAlign {
+		case 1:
+			jc = "left"
+		case 2:
+			jc = "center"
+		case 3:
+			jc = "right"
+		}
+		r.beginParagraph("Compact", "", jc)
+		if r.tableHead {
+			r.runProps["bold"]++
+		}
+	} else {
+		if r.tableHead {
+			r.runProps["bold"]--
+		}
+		r.endParagraph()
+		r.WriteString("</w:tc>")
+	}
+	return ast.WalkContinue
+}
+
+func (r *DocxRenderer) renderEmojiImg(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if alias := node.ChildByType(ast.NodeEmojiAlias); nil != alias {
+			r.writeRun(util.BytesToStr(alias.Tokens))
+		}
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *DocxRenderer) renderLink(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		dest := r.linkDest(node)
+		if "" == dest {
+			return ast.WalkContinue
+		}
+		r.beginHyperlink(dest)
+		if nil == node.ChildByType(ast.NodeLinkText) {
+			r.writeRun(dest)
+		}
+		return ast.WalkContinue
+	}
+
+	if "" != r.linkDest(node) {
+		r.endHyperlink()
+	}
+	return ast.WalkContinue
+}
+
+func (r *DocxRenderer) renderLinkText(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering && ast.NodeImage != node.Parent.Type {
+		r.writeRun(util.BytesToStr(node.Tokens))
+	}
+	return ast.WalkContinue
+}
+
+func (r *DocxRenderer) renderImage(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		var alt string
+		if text := node.ChildByType(ast.NodeLinkText); nil != text {
+			alt = util.BytesToStr(text.Tokens)
+		}
+		if !r.writeImage(r.linkDest(node), alt) {
+			r.writeRun(alt)
+		}
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *DocxRenderer) renderWikiLink(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		dest := r.WikiLinkDest(node)
+		text := node.WikiLinkText()
+		if isWikiLinkImage(node) && r.writeImage(dest, text) {
+			return ast.WalkSkipChildren
+		}
+		if "" == dest {
+			r.writeRun(text)
+			return ast.WalkSkipChildren
+		}
+		r.beginHyperlink(dest)
+		r.writeRun(text)
+		r.endHyperlink()
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *DocxRenderer) renderTextMark(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering {
+		return ast.WalkContinue
+	}
+
+	props := map[string]string{"strong": "bold", "em": "italic", "s": "strike", "u": "underline", "mark": "mark", "sup": "sup", "sub": "sub", "code": "code", "kbd": "code", "inline-math": "math"}
+	var applied []string
+	for _, typ := range strings.Split(node.TextMarkType, " ") {
+		if prop := props[typ]; "" != prop {
+			r.runProps[prop]++
+			applied = append(applied, prop)
+		}
+	}
+
+	content := node.TextMarkTextContent
+	if node.IsTextMarkType("inline-math") {
+		content = node.TextMarkInlineMathContent
+	}
+	dest := r.ResolveLinkStr(node, node.TextMarkAHref)
+	if "" != dest && nil == r.sanitizeURL(util.StrToBytes(dest)) {
+		dest = ""
+	}
+	if node.IsTextMarkType("a") && "" != dest {
+		r.beginHyperlink(util.BytesToStr(r.LinkPath(util.StrToBytes(dest))))
+		r.writeRun(content)
+		r.endHyperlink()
+	} else {
+		r.writeRun(content)
+	}
+
+	for _, prop := range applied {
+		r.runProps[prop]--
+	}
+	return ast.WalkContinue
+}
+
+func (r *DocxRenderer) renderFootnotesDef(node *ast.Node, entering bool) ast.WalkStatus {
+	if !r.RenderingFootnotes {
+		return ast.WalkSkipChildren
+	}
+	return ast.WalkContinue
+}
+
+func (r *DocxRenderer) renderFootnotesRef(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering {
+		return ast.WalkContinue
+	}
+
+	_, def := r.Tree.FindFootnotesDef(node.Tokens)
+	if nil == def || r.RenderingFootnotes {
+		// Word 不支持在脚注中引用脚注，这里和找不到定义的情况一样输出上标文本
+		r.runProps["sup"]++
+		r.writeRun(strings.TrimPrefix(util.BytesToStr(node.Tokens), "^"))
+		r.runProps["sup"]--
+		return ast.WalkContinue
+	}
+
+	id, ok := r.footnoteIDs[def]
+	if !ok {
+		id = len(r.footnoteDefs) + 1
+		r.footnoteIDs[def] = id
+		r.footnoteDefs = append(r.footnoteDefs, def)
+	}
+	if !r.paragraph {
+		r.beginParagraph("", "", "")
+	}
+	r.WriteString(`<w:r><w:rPr><w:rStyle w:val="FootnoteReference"/></w:rPr><w:footnoteReference w:id="` + strconv.Itoa(id) + `"/></w:r>`)
+	return ast.WalkContinue
+}
+
+// writeImage 加载 dest 处的图片并以嵌入图片的形式输出，加载或者解码失败时返回 false。
+func (r *DocxRenderer) writeImage(dest, alt string) bool {
+	if "" == dest || nil == r.ImageLoader {
+		return false
+	}
+
+	media := r.mediaIndex[dest]
+	if nil == media {
+		data, err := r.ImageLoader(dest)
+		if nil != err || 1 > len(data) {
+			return false
+		}
+		config, format, err := image.DecodeConfig(bytes.NewReader(data))
+		if nil != err || 1 > config.Width || 1 > config.Height {
+			return false
+		}
+		media = &docxMedia{name: "image" + strconv.Itoa(len(r.media)+1) + "." + format, data: data, width: config.Width, height: config.Height}
+		r.media = append(r.media, media)
+		r.mediaIndex[dest] = media
+	}
+	relID := &media.relID
+	if r.RenderingFootnotes {
+		relID = &media.fnRel
+	}
+	if "" == *relID {
+		*relID = r.addRel("image", "media/"+media.name, false)
+	}
+
+	cx, cy := media.width*docxEMUPerPixel, media.height*docxEMUPerPixel
+	if docxMaxImageEMU < cx {
+		cy = int(int64(cy) * docxMaxImageEMU / int64(cx))
+		cx = docxMaxImageEMU
+	}
+	extent := `cx="` + strconv.Itoa(cx) + `" cy="` + strconv.Itoa(cy) + `"`
+	r.drawingID++
+	id := strconv.Itoa(r.drawingID)
+
+	if !r.paragraph {
+		r.beginParagraph("", "", "")
+	}
+	r.WriteString(`<w:r><w:drawing><wp:inline distT="0" distB="0" distL="0" distR="0"><wp:extent ` + extent + `/>`)
+	r.WriteString(`<wp:docPr id="` + id + `" name="Picture ` + id + `" descr="` + docxEscape(alt) + `"/>`)
+	r.WriteString(`<a:graphic><a:graphicData uri="http://schemas.openxmlformats.org/drawingml/2006/picture"><pic:pic>`)
+	r.WriteString(`<pic:nvPicPr><pic:cNvPr id="0" name="` + media.name + `"/><pic:cNvPicPr/></pic:nvPicPr>`)
+	r.WriteString(`<pic:blipFill><a:blip r:embed="` + *relID + `"/><a:stretch><a:fillRect/></a:stretch></pic:blipFill>`)
+	r.WriteString(`<pic:spPr><a:xfrm><a:off x="0" y="0"/><a:ext ` + extent + `/></a:xfrm><a:prstGeom prst="rect"><a:avLst/></a:prstGeom></pic:spPr>`)
+	r.WriteString(`</pic:pic></a:graphicData></a:graphic></wp:inline></w:drawing></w:r>`)
+	return true
+}
+
+// NewLocalImageLoader 返回一个只加载 baseDir 目录下本地图片的 ImageLoader，支持相对路径、绝对路径和 file:// 地址。
+//
+// 解析符号链接后位于 baseDir 之外的路径、非普通文件（比如设备文件）以及超过 maxSize 字节的文件都会加载失败。
+func NewLocalImageLoader(baseDir string, maxSize int64) func(dest string) (data []byte, err error) {
+	return func(dest string) (data []byte, err error) {
+		if strings.HasPrefix(dest, "file://") {
+			if u, parseErr := url.Parse(dest); nil == parseErr {
+				dest = u.Path
+			}
+		} else if strings.Contains(dest, "://") || strings.HasPrefix(dest, "data:") {
+			return nil, errors.New("unsupported image location [" + dest + "]")
+		} else if unescaped, unescapeErr := url.PathUnescape(dest); nil == unescapeErr {
+			dest = unescaped
+		}
+
+		base, err := filepath.EvalSymlinks(baseDir)
+		if nil != err {
+			return
+		}
+		if base, err = filepath.Abs(base); nil != err {
+			return
+		}
+		if !filepath.IsAbs(dest) {
+			dest = filepath.Join(base, dest)
+		}
+		if dest, err = filepath.EvalSymlinks(dest); nil != err {
+			return
+		}
+		if rel, relErr := filepath.Rel(base, dest); nil != relErr || ".." == rel || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, errors.New("image [" + dest + "] is outside of [" + base + "]")
+		}
+
+		f, err := os.Open(dest)
+		if nil != err {
+			return
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if nil != err {
+			return
+		}
+		if !info.Mode().IsRegular() {
+			return nil, errors.New("image [" + dest + "] is not a regular file")
+		}
+		if maxSize < info.Size() {
+			return nil, errors.New("image [" + dest + "] exceeds " + strconv.FormatInt(maxSize, 10) + " bytes")
+		}
+		if data, err = io.ReadAll(io.LimitReader(f, maxSize+1)); nil != err {
+			return
+		}
+		if maxSize < int64(len(data)) {
+			return nil, errors.New("image [" + dest + "] exceeds " + strconv.FormatInt(maxSize, 10) + " bytes")
+		}
+		return
+	}
+}
+
+// docxEscape 对 XML 文本进行转义，非法的 XML 字符会被替换为 U+FFFD。
+func docxEscape(text string) string {
+	buf := &bytes.Buffer{}
+	xml.EscapeText(buf, util.StrToBytes(text))
+	return buf.String()
+}
+
+const docxContentTypes = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Default Extension="png" ContentType="image/png"/>` +
+	`<Default Extension="jpeg" ContentType="image/jpeg"/>` +
+	`<Default Extension="gif" ContentType="image/gif"/>` +
+	`<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>` +
+	`<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>` +
+	`<Override PartName="/word/numbering.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.numbering+xml"/>` +
+	`<Override PartName="/word/footnotes.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.footnotes+xml"/>` +
+	`<Override PartName="/word/settings.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.settings+xml"/>` +
+	`</Types>`
+
+const docxPackageRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>` +
+	`</Relationships>`
+
+const docxSettings = xml.Header + `<w:settings xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+	`<w:footnotePr><w:footnote w:id="-1"/><w:footnote w:id="0"/></w:footnotePr>` +
+	`</w:settings>`
+
+const docxStyles = xml.Header + `<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+	`<w:docDefaults><w:rPrDefault><w:rPr><w:rFonts w:ascii="Calibri" w:hAnsi="Calibri" w:eastAsia="SimSun" w:cs="Calibri"/><w:sz w:val="22"/><w:szCs w:val="22"/></w:rPr></w:rPrDefault>` +
+	`<w:pPrDefault><w:pPr><w:spacing w:after="120" w:line="276" w:lineRule="auto"/></w:pPr></w:pPrDefault></w:docDefaults>` +
+	`<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/><w:qFormat/></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/><w:pPr><w:keepNext/><w:spacing w:before="360" w:after="120"/><w:outlineLvl w:val="0"/></w:pPr><w:rPr><w:b/><w:bCs/><w:sz w:val="36"/><w:szCs w:val="36"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Heading2"><w:name w:val="heading 2"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/><w:pPr><w:keepNext/><w:spacing w:before="240" w:after="120"/><w:outlineLvl w:val="1"/></w:pPr><w:rPr><w:b/><w:bCs/><w:sz w:val="32"/><w:szCs w:val="32"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Heading3"><w:name w:val="heading 3"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/><w:pPr><w:keepNext/><w:spacing w:before="240" w:after="120"/><w:outlineLvl w:val="2"/></w:pPr><w:rPr><w:b/><w:bCs/><w:sz w:val="28"/><w:szCs w:val="28"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Heading4"><w:name w:val="heading 4"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/><w:pPr><w:keepNext/><w:spacing w:before="200" w:after="80"/><w:outlineLvl w:val="3"/></w:pPr><w:rPr><w:b/><w:bCs/><w:sz w:val="24"/><w:szCs w:val="24"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Heading5"><w:name w:val="heading 5"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/><w:pPr><w:keepNext/><w:spacing w:before="200" w:after="80"/><w:outlineLvl w:val="4"/></w:pPr><w:rPr><w:b/><w:bCs/><w:sz w:val="22"/><w:szCs w:val="22"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Heading6"><w:name w:val="heading 6"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/><w:pPr><w:keepNext/><w:spacing w:before="200" w:after="80"/><w:outlineLvl w:val="5"/></w:pPr><w:rPr><w:b/><w:bCs/><w:i/><w:iCs/><w:sz w:val="22"/><w:szCs w:val="22"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Quote"><w:name w:val="Quote"/><w:basedOn w:val="Normal"/><w:qFormat/><w:pPr><w:pBdr><w:left w:val="single" w:sz="18" w:space="8" w:color="DFE2E5"/></w:pBdr></w:pPr><w:rPr><w:color w:val="6A737D"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="AdmonitionTitle"><w:name w:val="Admonition Title"/><w:basedOn w:val="Normal"/><w:next w:val="Quote"/><w:pPr><w:keepNext/></w:pPr><w:rPr><w:b/><w:bCs/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="SourceCode"><w:name w:val="Source Code"/><w:basedOn w:val="Normal"/><w:pPr><w:shd w:val="clear" w:color="auto" w:fill="F6F8FA"/><w:spacing w:after="120" w:line="240" w:lineRule="auto"/></w:pPr><w:rPr><w:rFonts w:ascii="Consolas" w:hAnsi="Consolas" w:cs="Consolas"/><w:sz w:val="20"/><w:szCs w:val="20"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="MathBlock"><w:name w:val="Math Block"/><w:basedOn w:val="Normal"/><w:pPr><w:jc w:val="center"/></w:pPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Compact"><w:name w:val="Compact"/><w:basedOn w:val="Normal"/><w:pPr><w:spacing w:before="36" w:after="36"/></w:pPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="DefinitionTerm"><w:name w:val="Definition Term"/><w:basedOn w:val="Normal"/><w:next w:val="Definition"/><w:pPr><w:keepNext/><w:spacing w:after="0"/></w:pPr><w:rPr><w:b/><w:bCs/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Definition"><w:name w:val="Definition"/><w:basedOn w:val="Normal"/><w:pPr><w:ind w:left="720"/></w:pPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="FootnoteText"><w:name w:val="footnote text"/><w:basedOn w:val="Normal"/><w:pPr><w:spacing w:after="0"/></w:pPr><w:rPr><w:sz w:val="20"/><w:szCs w:val="20"/></w:rPr></w:style>` +
+	`<w:style w:type="character" w:default="1" w:styleId="DefaultParagraphFont"><w:name w:val="Default Paragraph Font"/><w:uiPriority w:val="1"/><w:semiHidden/></w:style>` +
+	`<w:style w:type="character" w:styleId="VerbatimChar"><w:name w:val="Verbatim Char"/><w:basedOn w:val="DefaultParagraphFont"/><w:rPr><w:rFonts w:ascii="Consolas" w:hAnsi="Consolas" w:cs="Consolas"/><w:sz w:val="20"/><w:shd w:val="clear" w:color="auto" w:fill="F3F4F4"/></w:rPr></w:style>` +
+	`<w:style w:type="character" w:styleId="Hyperlink"><w:name w:val="Hyperlink"/><w:basedOn w:val="DefaultParagraphFont"/><w:rPr><w:color w:val="0563C1"/><w:u w:val="single"/></w:rPr></w:style>` +
+	`<w:style w:type="character" w:styleId="FootnoteReference"><w:name w:val="footnote reference"/><w:basedOn w:val="DefaultParagraphFont"/><w:rPr><w:vertAlign w:val="superscript"/></w:rPr></w:style>` +
+	`<w:style w:type="table" w:default="1" w:styleId="Table"><w:name w:val="Table"/><w:tblPr><w:tblCellMar><w:top w:w="0" w:type="dxa"/><w:left w:w="108" w:type="dxa"/><w:bottom w:w="0" w:type="dxa"/><w:right w:w="108" w:type="dxa"/></w:tblCellMar></w:tblPr></w:style>` +
+	`</w:styles>`
@@ -785,13 +785,7 @@ func (r *EditorIRRenderer) renderLinkDest(node *ast.Node, entering bool) ast.Wal
 
 		r.Tag("span", [][]string{{"class", "editor-ir__marker editor-ir__marker--link"}}, false)
 		dest := node.Tokens
-		if r.Options.Sanitize {
-			tokens := bytes.TrimSpace(dest)
-			tokens = bytes.ToLower(tokens)
-			if bytes.HasPrefix(tokens, []byte("javascript:")) {
-				dest = nil
-			}
-		}
+		dest = r.sanitizeURL(dest)
 		dest = html.EscapeHTML(dest)
 		r.Write(dest)
 		r.Tag("/span", nil, false)
@@ -940,6 +934,7 @@ func (r *EditorIRRenderer) renderImage(node *ast.Node, entering bool) ast.WalkSt
 			link = r.Tree.FindLinkRefDefLink(node.LinkRefLabel)
 		}
 		destTokens := link.ChildByType(ast.NodeLinkDest).Tokens
+		destTokens, _ = r.ResolveLink(node, destTokens)
 		destTokens = r.LinkPath(destTokens)
 		destTokens = bytes.ReplaceAll(destTokens, editor.CaretTokens, nil)
 		attrs := [][]string{{"src", string(destTokens)}}
@@ -954,9 +949,7 @@ func (r *EditorIRRenderer) renderImage(node *ast.Node, entering bool) ast.WalkSt
 		buf := r.Writer.Bytes()
 		idx := bytes.LastIndex(buf, []byte("<img src="))
 		imgBuf := buf[idx:]
-		if r.Options.Sanitize {
-			imgBuf = sanitize(imgBuf)
-		}
+		imgBuf = r.sanitize(imgBuf)
 		r.Writer.Truncate(idx)
 		r.Writer.Write(imgBuf)
 
@@ -985,9 +978,7 @@ func (r *EditorIRRenderer) renderHTML(node *ast.Node, entering bool) ast.WalkSta
 
 		r.Tag("pre", [][]string{{"class", "editor-ir__preview"}, {"data-render", "2"}}, false)
 		tokens = bytes.ReplaceAll(tokens, editor.CaretTokens, nil)
-		if r.Options.Sanitize {
-			tokens = sanitize(tokens)
-		}
+		tokens = r.sanitize(tokens)
 		r.Write(tokens)
 		r.WriteString("</pre></div>")
 	}
@@ -1262,7 +1253,7 @@ func (r *EditorIRRenderer) renderHeading(node *ast.Node, entering bool) ast.Walk
 			id = string(headingID.Tokens)
 		}
 		if "" == id {
-			id = HeadingID(node)
+			id = r.HeadingID(node)
 		}
 		r.WriteString(" id=\"ir-" + id + "\"")
 		if !node.HeadingSetext {
@@ -1275,7 +1266,7 @@ func (r *EditorIRRenderer) renderHeading(node *ast.Node, entering bool) ast.Walk
 			}
 		}
 		if r.Options.HeadingAnchor {
-			id := HeadingID(node)
+			id := r.HeadingID(node)
 			r.Tag("a", [][]string{{"id", "editorAnchor-" + id}, {"class", "editor-anchor"}, {"href", "#" + id}}, false)
 			r.WriteString(`<svg viewBox="0 0 16 16" version="1.1" width="16" height="16"><path fill-rule="evenodd" d="M4 9h1v1H4c-1.5 0-3-1.69-3-3.5S2.55 3 4 3h4c1.45 0 3 1.69 3 3.5 0 1.41-.91 2.72-2 3.25V8.59c.58-.45 1-1.27 1-2.09C10 5.22 8.98 4 8 4H4c-.98 0-2 1.22-2 2.5S3 9 4 9zm9-3h-1v1h1c1 0 2 1.22 2 2.5S13.98 12 13 12H9c-.98 0-2-1.22-2-2.5 0-.83.42-1.64 1-2.09V6.25c-1.09.53-2 1.84-2 3.25C6 11.31 7.55 13 9 13h4c1.45 0 3-1.69 3-3.5S14.5 6 13 6z"></path></svg>`)
 			r.Tag("/a", nil, false)
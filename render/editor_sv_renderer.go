@@ -682,13 +682,7 @@ func (r *EditorSVRenderer) renderLinkDest(node *ast.Node, entering bool) ast.Wal
 		}
 		r.Tag("span", [][]string{{"class", "editor-sv__marker--link"}}, false)
 		dest := node.Tokens
-		if r.Options.Sanitize {
-			tokens := bytes.TrimSpace(dest)
-			tokens = bytes.ToLower(tokens)
-			if bytes.HasPrefix(tokens, []byte("javascript:")) {
-				dest = nil
-			}
-		}
+		dest = r.sanitizeURL(dest)
 		dest = html.EscapeHTML(dest)
 		r.Write(dest)
 		r.Tag("/span", nil, false)
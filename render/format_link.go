@@ -0,0 +1,253 @@
+package render
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/pafthang/md/ast"
+	"github.com/pafthang/md/html"
+	"github.com/pafthang/md/lex"
+	"github.com/pafthang/md/util"
+)
+
+// formatLinkRefDef 描述了格式化时生成的链接引用定义。
+type formatLinkRefDef struct {
+	label   string
+	dest    []byte
+	title   []byte
+	section int // 第一次使用该定义的章节，即之前出现过的顶层标题数
+}
+
+// collectLinkRefs 按照 FormatStyle.LinkStyle 收集需要输出为引用链接的链接和图片。
+//
+// 行内链接和引用链接都会重新生成链接引用定义：地址和标题相同的定义合并为一个，没有被使用的定义不再输出。
+func (r *FormatRenderer) collectLinkRefs() {
+	r.linkRefLabels, r.linkRefDefs, r.linkRefSection, r.linkRefFlushed = nil, nil, 0, 0
+	if LinkStyleReference != r.style().LinkStyle || nil == r.Tree || !r.Tree.Context.ParseOption.LinkRef {
+		return
+	}
+
+	// 原文中引用链接的标签优先保留，文本中已有的 [text] 在定义同名标签后会变为链接，生成新标签时都需要避开
+	reserved := r.bracketTexts()
+	r.walkLinks(func(n *ast.Node) {
+		if nil != n && 3 == n.LinkType {
+			reserved[strings.ToLower(util.BytesToStr(n.LinkRefLabel))] = true
+		}
+	})
+
+	r.linkRefLabels = map[*ast.Node]string{}
+	defs := map[string]*formatLinkRefDef{} // 地址和标题 -> 定义
+	used := map[string]bool{}              // 已经使用的标签，忽略大小写
+	num, section := 0, 0
+	r.walkLinks(func(n *ast.Node) {
+		if nil == n {
+			section++
+			return
+		}
+
+		dest := n.ChildByType(ast.NodeLinkDest)
+		if nil == dest {
+			return
+		}
+		tokens, _ := r.ResolveLink(dest, dest.Tokens)
+		def := &formatLinkRefDef{dest: r.LinkPath(tokens), section: section}
+		if title := n.ChildByType(ast.NodeLinkTitle); nil != title {
+			def.title = title.Tokens
+		}
+
+		key := string(def.dest) + "\n" + string(def.title)
+		if existing := defs[key]; nil != existing {
+			r.linkRefLabels[n] = existing.label
+			return
+		}
+
+		if label := util.BytesToStr(n.LinkRefLabel); 3 == n.LinkType && !used[strings.ToLower(label)] {
+			def.label = label
+		} else if text := linkRefText(n); LinkRefLabelText == r.style().LinkRefLabel && "" != text {
+			def.label = text
+			for i := 2; used[strings.ToLower(def.label)] || reserved[strings.ToLower(def.label)]; i++ {
+				def.label = text + "-" + strconv.Itoa(i)
+			}
+		} else {
+			for "" == def.label || used[def.label] || reserved[def.label] {
+				num++
+				def.label = strconv.Itoa(num)
+			}
+		}
+		used[strings.ToLower(def.label)] = true
+		defs[key] = def
+		r.linkRefLabels[n] = def.label
+		r.linkRefDefs = append(r.linkRefDefs, def)
+	})
+}
+
+// bracketTexts 返回文档文本中方括号包裹的内容，内容已经转换为小写并合并了空白。
+func (r *FormatRenderer) bracketTexts() (ret map[string]bool) {
+	ret = map[string]bool{}
+	buf := &bytes.Buffer{}
+	collect := func() {
+		text := buf.String()
+		for start := strings.IndexByte(text, lex.ItemOpenBracket); 0 <= start; start = strings.IndexByte(text, lex.ItemOpenBracket) {
+			text = text[start+1:]
+			end := strings.IndexAny(text, "[]")
+			if 0 > end {
+				break
+			}
+			if lex.ItemCloseBracket == text[end] {
+				ret[strings.ToLower(strings.Join(strings.Fields(text[:end]), " "))] = true
+			}
+		}
+		buf.Reset()
+	}
+	ast.Walk(r.Tree.Root, func(n *ast.Node, entering bool) ast.WalkStatus {
+		if !entering {
+			return ast.WalkContinue
+		}
+
+		if n.IsBlock() {
+			collect()
+		} else if ast.NodeText == n.Type || ast.NodeLinkText == n.Type {
+			buf.Write(n.Tokens)
+		}
+		return ast.WalkContinue
+	})
+	collect()
+	return
+}
+
+// walkLinks 按照文档顺序遍历行内链接、自动链接、引用链接和图片，遇到开始新章节的标题时使用 nil 回调。
+func (r *FormatRenderer) walkLinks(fn func(link *ast.Node)) {
+	ast.Walk(r.Tree.Root, func(n *ast.Node, entering bool) ast.WalkStatus {
+		if !entering {
+			return ast.WalkContinue
+		}
+
+		switch n.Type {
+		case ast.NodeHeading:
+			if r.linkRefSectionStart(n) {
+				fn(nil)
+			}
+		case ast.NodeLink, ast.NodeImage:
+			if 0 == n.LinkType || 2 == n.LinkType || 3 == n.LinkType {
+				fn(n)
+			}
+		case ast.NodeLinkRefDefBlock:
+			return ast.WalkSkipChildren
+		}
+		return ast.WalkContinue
+	})
+}
+
+// linkRefSectionStart 判断标题 heading 是否开始了一个新的章节，链接引用定义按章节放置时会输出在该标题之前。
+func (r *FormatRenderer) linkRefSectionStart(heading *ast.Node) bool {
+	return LinkRefPlacementSection == r.style().LinkRefPlacement && nil != heading.Parent && ast.NodeDocument == heading.Parent.Type
+}
+
+// linkRefText 返回链接 link 的纯文本内容，用作链接引用标签。
+func linkRefText(link *ast.Node) string {
+	buf := &bytes.Buffer{}
+	ast.Walk(link, func(n *ast.Node, entering bool) ast.WalkStatus {
+		if !entering {
+			return ast.WalkContinue
+		}
+
+		switch n.Type {
+		case ast.NodeLinkDest, ast.NodeLinkTitle:
+			return ast.WalkSkipChildren
+		case ast.NodeText, ast.NodeLinkText, ast.NodeCodeSpanContent, ast.NodeInlineMathContent:
+			buf.Write(n.Tokens)
+		case ast.NodeSoftBreak, ast.NodeHardBreak:
+			buf.WriteByte(lex.ItemSpace)
+		}
+		return ast.WalkContinue
+	})
+	text := strings.NewReplacer("[", "", "]", "", "\\", "").Replace(buf.String())
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// skipLinkRefDefBlock 判断是否跳过原文中的链接引用定义块 defBlock：转换为行内链接后不再需要定义，转换为引用链接时会重新生成定义。
+//
+// 格式化时列表项之间不输出空行，松散列表项中的定义前后的空行可能是列表保持松散的唯一原因，去掉后列表会变为紧凑列表，因此这些定义总是保留。
+func (r *FormatRenderer) skipLinkRefDefBlock(defBlock *ast.Node) bool {
+	style := r.style().LinkStyle
+	if LinkStyleInline != style && (LinkStyleReference != style || nil == r.linkRefLabels) {
+		return false
+	}
+	if item := defBlock.Parent; ast.NodeListItem == item.Type && nil != item.Parent.ListData && !item.Parent.ListData.Tight {
+		return false
+	}
+	return true
+}
+
+// renderLinkRefDefs 输出第 section 个章节之前第一次使用的链接引用定义，section 为 -1 时输出剩余的全部定义。
+func (r *FormatRenderer) renderLinkRefDefs(section int) {
+	end := r.linkRefFlushed
+	for ; end < len(r.linkRefDefs) && (0 > section || r.linkRefDefs[end].section < section); end++ {
+	}
+	if end == r.linkRefFlushed {
+		return
+	}
+
+	buf := bytes.TrimRight(r.Writer.Bytes(), " \t\n")
+	r.Writer.Reset()
+	r.Write(buf)
+	if 0 < len(buf) {
+		r.WriteString("\n\n")
+	}
+	for _, def := range r.linkRefDefs[r.linkRefFlushed:end] {
+		r.WriteString("[" + def.label + "]: ")
+		r.Write(formatLinkDest(def.dest, false))
+		if 0 < len(def.title) {
+			r.WriteString(" \"")
+			r.Write(html.EscapeHTML(def.title))
+			r.WriteString("\"")
+		}
+		r.WriteString("\n")
+	}
+	if 0 <= section {
+		r.WriteByte(lex.ItemNewline)
+	}
+	r.linkRefFlushed = end
+}
+
+// formatLinkDest 返回链接地址 dest 在 Markdown 中的写法，保证重新解析后得到相同的地址，title 表示行内链接之后是否还有标题。
+//
+// 地址为空、包含空白或者尖括号、圆括号不配对时使用 <> 包裹，行内链接有标题时地址中的圆括号会导致标题无法解析，也需要包裹；
+// 包裹时转义其中的尖括号，会被当作转义的反斜杠总是需要转义。
+func formatLinkDest(dest []byte, title bool) []byte {
+	pointy := 0 == len(dest) || bytes.ContainsAny(dest, " \t\n<>")
+	if !pointy && bytes.ContainsAny(dest, "()") {
+		depth := 0
+		for _, token := range dest {
+			if lex.ItemOpenParen == token {
+				depth++
+			} else if lex.ItemCloseParen == token {
+				if depth--; 0 > depth {
+					break
+				}
+			}
+		}
+		pointy = title || 0 != depth
+	}
+
+	ret := make([]byte, 0, len(dest)+2)
+	if pointy {
+		ret = append(ret, lex.ItemLess)
+	}
+	for i, token := range dest {
+		switch token {
+		case lex.ItemBackslash:
+			if (i+1 < len(dest) && lex.IsASCIIPunct(dest[i+1])) || (i+1 == len(dest) && pointy) {
+				ret = append(ret, lex.ItemBackslash)
+			}
+		case lex.ItemLess, lex.ItemGreater:
+			ret = append(ret, lex.ItemBackslash)
+		}
+		ret = append(ret, token)
+	}
+	if pointy {
+		ret = append(ret, lex.ItemGreater)
+	}
+	return ret
+}
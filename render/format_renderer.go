@@ -2,7 +2,6 @@ package render
 
 import (
 	"bytes"
-	"strconv"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -19,6 +18,11 @@ import (
 type FormatRenderer struct {
 	*BaseRenderer
 	NodeWriterStack []*bytes.Buffer // 节点输出缓冲栈
+
+	linkRefLabels  map[*ast.Node]string // 按照 FormatStyle.LinkStyle 输出为引用链接的链接节点及其标签
+	linkRefDefs    []*formatLinkRefDef  // 重新生成的链接引用定义
+	linkRefSection int                  // 当前章节，链接引用定义按章节放置时使用
+	linkRefFlushed int                  // 已经输出的链接引用定义数
 }
 
 // NewFormatRenderer 创建一个格式化渲染器。
@@ -56,6 +60,10 @@ func NewFormatRenderer(tree *parse.Tree, options *Options) *FormatRenderer {
 	ret.RendererFuncs[ast.NodeStrongU8eCloseMarker] = ret.renderStrongU8eCloseMarker
 	ret.RendererFuncs[ast.NodeBlockquote] = ret.renderBlockquote
 	ret.RendererFuncs[ast.NodeBlockquoteMarker] = ret.renderBlockquoteMarker
+	ret.RendererFuncs[ast.NodeAdmonition] = ret.renderAdmonition
+	ret.RendererFuncs[ast.NodeDefinitionList] = ret.renderDefinitionList
+	ret.RendererFuncs[ast.NodeDefinitionTerm] = ret.renderDefinitionTerm
+	ret.RendererFuncs[ast.NodeDefinitionDescription] = ret.renderDefinitionDescription
 	ret.RendererFuncs[ast.NodeHeading] = ret.renderHeading
 	ret.RendererFuncs[ast.NodeHeadingC8hMarker] = ret.renderHeadingC8hMarker
 	ret.RendererFuncs[ast.NodeHeadingID] = ret.renderHeadingID
@@ -115,6 +123,8 @@ func NewFormatRenderer(tree *parse.Tree, options *Options) *FormatRenderer {
 	ret.RendererFuncs[ast.NodeFileAnnotationRefID] = ret.renderFileAnnotationRefID
 	ret.RendererFuncs[ast.NodeFileAnnotationRefSpace] = ret.renderFileAnnotationRefSpace
 	ret.RendererFuncs[ast.NodeFileAnnotationRefText] = ret.renderFileAnnotationRefText
+	ret.RendererFuncs[ast.NodeWikiLink] = ret.renderWikiLink
+	ret.RendererFuncs[ast.NodeWikiLinkEmbed] = ret.renderWikiLinkEmbed
 	ret.RendererFuncs[ast.NodeMark] = ret.renderMark
 	ret.RendererFuncs[ast.NodeMark1OpenMarker] = ret.renderMark1OpenMarker
 	ret.RendererFuncs[ast.NodeMark1CloseMarker] = ret.renderMark1CloseMarker
@@ -265,7 +275,7 @@ func (r *FormatRenderer) renderTextMarkAttrs(node *ast.Node) (attrs [][]string)
 			attrs = append(attrs, []string{"data-subtype", node.TextMarkBlockRefSubtype})
 			attrs = append(attrs, []string{"data-id", node.TextMarkBlockRefID})
 		} else if "a" == typ {
-			href := node.TextMarkAHref
+			href := r.ResolveLinkStr(node, node.TextMarkAHref)
 			href = string(r.LinkPath([]byte(href)))
 
 			if node.ParentIs(ast.NodeTableCell) {
@@ -473,6 +483,12 @@ func (r *FormatRenderer) renderSuperBlockCloseMarker(node *ast.Node, entering bo
 }
 
 func (r *FormatRenderer) renderLinkRefDefBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if r.skipLinkRefDefBlock(node) {
+		return ast.WalkSkipChildren
+	}
+	if !entering && nil != node.Next && ast.NodeLinkRefDefBlock != node.Next.Type {
+		r.WriteByte(lex.ItemNewline)
+	}
 	return ast.WalkContinue
 }
 
@@ -708,6 +724,17 @@ func (r *FormatRenderer) renderFileAnnotationRefText(node *ast.Node, entering bo
 	return ast.WalkContinue
 }
 
+func (r *FormatRenderer) renderWikiLink(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString(wikiLinkMarkdown(node))
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *FormatRenderer) renderWikiLinkEmbed(node *ast.Node, entering bool) ast.WalkStatus {
+	return r.renderWikiLink(node, entering)
+}
+
 func (r *FormatRenderer) renderYamlFrontMatterCloseMarker(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
 		r.Write(parse.YamlFrontMatterMarker)
@@ -830,6 +857,9 @@ func (r *FormatRenderer) renderEmoji(node *ast.Node, entering bool) ast.WalkStat
 
 func (r *FormatRenderer) renderTableCell(node *ast.Node, entering bool) ast.WalkStatus {
 	padding := node.TableCellContentMaxWidth - node.TableCellContentWidth
+	if r.style().CompactTable {
+		padding = 0
+	}
 	if entering {
 		r.WriteByte(lex.ItemPipe)
 		if !r.Options.ProtyleWYSIWYG {
@@ -864,7 +894,7 @@ func (r *FormatRenderer) renderTableRow(node *ast.Node, entering bool) ast.WalkS
 }
 
 func (r *FormatRenderer) renderTableHead(node *ast.Node, entering bool) ast.WalkStatus {
-	if !entering {
+	if !entering && nil != node.FirstChild {
 		headRow := node.FirstChild
 		for th := headRow.FirstChild; nil != th; th = th.Next {
 			if ast.NodeKramdownSpanIAL == th.Type {
@@ -872,6 +902,10 @@ func (r *FormatRenderer) renderTableHead(node *ast.Node, entering bool) ast.Walk
 			}
 
 			align := th.TableCellAlign
+			if r.style().CompactTable {
+				r.WriteString([]string{"| --- ", "| :-- ", "| :-: ", "| --: "}[align])
+				continue
+			}
 			switch align {
 			case 0:
 				r.WriteString("| -")
@@ -1009,7 +1043,13 @@ func (r *FormatRenderer) renderStrikethrough2CloseMarker(node *ast.Node, enterin
 }
 
 func (r *FormatRenderer) renderLinkTitle(node *ast.Node, entering bool) ast.WalkStatus {
+	if _, ok := r.linkRefLabels[node.Parent]; ok {
+		return ast.WalkSkipChildren
+	}
 	if entering {
+		if nil != node.Previous && ast.NodeLinkSpace != node.Previous.Type {
+			r.WriteByte(lex.ItemSpace) // 引用链接转换为行内链接时没有空白节点
+		}
 		r.WriteByte(lex.ItemDoublequote)
 		r.Write(html.EscapeHTML(node.Tokens))
 		r.WriteByte(lex.ItemDoublequote)
@@ -1018,15 +1058,24 @@ func (r *FormatRenderer) renderLinkTitle(node *ast.Node, entering bool) ast.Walk
 }
 
 func (r *FormatRenderer) renderLinkDest(node *ast.Node, entering bool) ast.WalkStatus {
+	if _, ok := r.linkRefLabels[node.Parent]; ok {
+		return ast.WalkSkipChildren
+	}
 	if entering {
-		tokens := node.Tokens
+		tokens, _ := r.ResolveLink(node, node.Tokens)
 		tokens = r.LinkPath(tokens)
+		if 2 != node.Parent.LinkType {
+			tokens = formatLinkDest(tokens, 1 != node.Parent.LinkType && nil != node.Parent.ChildByType(ast.NodeLinkTitle))
+		}
 		r.Write(tokens)
 	}
 	return ast.WalkContinue
 }
 
 func (r *FormatRenderer) renderLinkSpace(node *ast.Node, entering bool) ast.WalkStatus {
+	if _, ok := r.linkRefLabels[node.Parent]; ok {
+		return ast.WalkSkipChildren
+	}
 	if entering {
 		r.WriteByte(lex.ItemSpace)
 	}
@@ -1047,6 +1096,9 @@ func (r *FormatRenderer) renderLinkText(node *ast.Node, entering bool) ast.WalkS
 }
 
 func (r *FormatRenderer) renderCloseParen(node *ast.Node, entering bool) ast.WalkStatus {
+	if _, ok := r.linkRefLabels[node.Parent]; ok {
+		return ast.WalkSkipChildren
+	}
 	if entering {
 		r.WriteByte(lex.ItemCloseParen)
 	}
@@ -1054,6 +1106,9 @@ func (r *FormatRenderer) renderCloseParen(node *ast.Node, entering bool) ast.Wal
 }
 
 func (r *FormatRenderer) renderOpenParen(node *ast.Node, entering bool) ast.WalkStatus {
+	if _, ok := r.linkRefLabels[node.Parent]; ok {
+		return ast.WalkSkipChildren
+	}
 	if entering {
 		r.WriteByte(lex.ItemOpenParen)
 	}
@@ -1061,6 +1116,9 @@ func (r *FormatRenderer) renderOpenParen(node *ast.Node, entering bool) ast.Walk
 }
 
 func (r *FormatRenderer) renderGreater(node *ast.Node, entering bool) ast.WalkStatus {
+	if _, ok := r.linkRefLabels[node.Parent]; ok {
+		return ast.WalkSkipChildren
+	}
 	if entering {
 		r.WriteByte(lex.ItemGreater)
 	}
@@ -1068,6 +1126,9 @@ func (r *FormatRenderer) renderGreater(node *ast.Node, entering bool) ast.WalkSt
 }
 
 func (r *FormatRenderer) renderLess(node *ast.Node, entering bool) ast.WalkStatus {
+	if _, ok := r.linkRefLabels[node.Parent]; ok {
+		return ast.WalkSkipChildren
+	}
 	if entering {
 		r.WriteByte(lex.ItemLess)
 	}
@@ -1091,6 +1152,9 @@ func (r *FormatRenderer) renderOpenBrace(node *ast.Node, entering bool) ast.Walk
 func (r *FormatRenderer) renderCloseBracket(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
 		r.WriteByte(lex.ItemCloseBracket)
+		if label, ok := r.linkRefLabels[node.Parent]; ok {
+			r.WriteString("[" + label + "]")
+		}
 	}
 	return ast.WalkContinue
 }
@@ -1116,18 +1180,26 @@ func (r *FormatRenderer) renderImage(node *ast.Node, entering bool) ast.WalkStat
 func (r *FormatRenderer) renderLink(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
 		r.LinkTextAutoSpacePrevious(node)
-		if 3 == node.LinkType {
-			text := node.ChildByType(ast.NodeLinkText).Tokens
-			if bytes.Equal(text, node.LinkRefLabel) {
-				r.WriteString("[" + util.BytesToStr(text) + "]")
-			} else {
-				r.WriteString("[" + util.BytesToStr(text) + "][" + util.BytesToStr(node.LinkRefLabel) + "]")
+		if 3 == node.LinkType && LinkStyleKeep == r.style().LinkStyle {
+			if text := node.ChildByType(ast.NodeLinkText); nil != text && nil != text.Next && ast.NodeCloseBracket == text.Next.Type && bytes.Equal(text.Tokens, node.LinkRefLabel) {
+				r.WriteString("[" + util.BytesToStr(text.Tokens) + "]")
+				return ast.WalkSkipChildren
 			}
-			return ast.WalkSkipChildren
+
+			// 链接文本中包含其他行级节点时需要逐个渲染，在 ] 后输出标签
+			if nil == r.linkRefLabels {
+				r.linkRefLabels = map[*ast.Node]string{}
+			}
+			r.linkRefLabels[node] = util.BytesToStr(node.LinkRefLabel)
 		}
 		if 1 == node.LinkType {
-			dest := node.ChildByType(ast.NodeLinkDest).Tokens
-			r.Write(dest)
+			dest, _ := r.ResolveLink(node, node.ChildByType(ast.NodeLinkDest).Tokens)
+			r.Write(formatLinkDest(dest, false))
+			if title := node.ChildByType(ast.NodeLinkTitle); nil != title {
+				r.WriteString(" \"")
+				r.Write(html.EscapeHTML(title.Tokens))
+				r.WriteByte(lex.ItemDoublequote)
+			}
 			return ast.WalkSkipChildren
 		}
 	} else {
@@ -1163,8 +1235,10 @@ func (r *FormatRenderer) renderDocument(node *ast.Node, entering bool) ast.WalkS
 	if entering {
 		r.Writer = &bytes.Buffer{}
 		r.NodeWriterStack = append(r.NodeWriterStack, r.Writer)
+		r.collectLinkRefs()
 	} else {
 		r.NodeWriterStack = r.NodeWriterStack[:len(r.NodeWriterStack)-1]
+		r.renderLinkRefDefs(-1)
 		var buf []byte
 		if r.Options.KeepParagraphBeginningSpace {
 			buf = bytes.TrimRight(r.Writer.Bytes(), " \t\n")
@@ -1180,6 +1254,18 @@ func (r *FormatRenderer) renderDocument(node *ast.Node, entering bool) ast.WalkS
 }
 
 func (r *FormatRenderer) renderParagraph(node *ast.Node, entering bool) ast.WalkStatus {
+	if width := r.hardWrapWidth(); 0 < width && !node.ParentIs(ast.NodeTableCell) {
+		if entering {
+			r.Writer = &bytes.Buffer{}
+			r.NodeWriterStack = append(r.NodeWriterStack, r.Writer)
+		} else {
+			writer := r.NodeWriterStack[len(r.NodeWriterStack)-1]
+			r.NodeWriterStack = r.NodeWriterStack[:len(r.NodeWriterStack)-1]
+			r.Writer = r.NodeWriterStack[len(r.NodeWriterStack)-1]
+			r.Write(hardWrap(writer.Bytes(), width))
+		}
+	}
+
 	if !entering {
 		if !r.Options.KeepParagraphBeginningSpace && nil != node.FirstChild {
 			node.FirstChild.Tokens = bytes.TrimSpace(node.FirstChild.Tokens)
@@ -1398,7 +1484,7 @@ func (r *FormatRenderer) renderMathBlock(node *ast.Node, entering bool) ast.Walk
 func (r *FormatRenderer) renderCodeBlockCloseMarker(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
 		r.Newline()
-		r.Write(node.Tokens)
+		r.Write(r.codeFence(node.Parent, node.Tokens))
 		r.Newline()
 		if !r.isLastNode(r.Tree.Root, node) {
 			if r.withoutKramdownBlockIAL(node.Parent) {
@@ -1426,7 +1512,7 @@ func (r *FormatRenderer) renderCodeBlockInfoMarker(node *ast.Node, entering bool
 
 func (r *FormatRenderer) renderCodeBlockOpenMarker(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
-		r.Write(node.Tokens)
+		r.Write(r.codeFence(node.Parent, node.Tokens))
 	}
 	return ast.WalkContinue
 }
@@ -1435,10 +1521,11 @@ func (r *FormatRenderer) renderCodeBlock(node *ast.Node, entering bool) ast.Walk
 	if entering {
 		r.Newline()
 		if !node.IsFencedCodeBlock {
-			r.Write(bytes.Repeat([]byte{lex.ItemBacktick}, 3))
+			fence := r.codeFence(node, bytes.Repeat([]byte{lex.ItemBacktick}, 3))
+			r.Write(fence)
 			r.WriteByte(lex.ItemNewline)
 			r.Write(node.FirstChild.Tokens)
-			r.Write(bytes.Repeat([]byte{lex.ItemBacktick}, 3))
+			r.Write(fence)
 			r.Newline()
 			if !r.isLastNode(r.Tree.Root, node) {
 				if r.withoutKramdownBlockIAL(node) {
@@ -1462,28 +1549,28 @@ func (r *FormatRenderer) renderEmphasis(node *ast.Node, entering bool) ast.WalkS
 
 func (r *FormatRenderer) renderEmAsteriskOpenMarker(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
-		r.WriteByte(lex.ItemAsterisk)
+		r.WriteByte(r.emphasisMarker(node.Parent, lex.ItemAsterisk))
 	}
 	return ast.WalkContinue
 }
 
 func (r *FormatRenderer) renderEmAsteriskCloseMarker(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
-		r.WriteByte(lex.ItemAsterisk)
+		r.WriteByte(r.emphasisMarker(node.Parent, lex.ItemAsterisk))
 	}
 	return ast.WalkContinue
 }
 
 func (r *FormatRenderer) renderEmUnderscoreOpenMarker(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
-		r.WriteByte(lex.ItemUnderscore)
+		r.WriteByte(r.emphasisMarker(node.Parent, lex.ItemUnderscore))
 	}
 	return ast.WalkContinue
 }
 
 func (r *FormatRenderer) renderEmUnderscoreCloseMarker(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
-		r.WriteByte(lex.ItemUnderscore)
+		r.WriteByte(r.emphasisMarker(node.Parent, lex.ItemUnderscore))
 	}
 	return ast.WalkContinue
 }
@@ -1499,28 +1586,28 @@ func (r *FormatRenderer) renderStrong(node *ast.Node, entering bool) ast.WalkSta
 
 func (r *FormatRenderer) renderStrongA6kOpenMarker(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
-		r.WriteString("**")
+		r.Write(bytes.Repeat([]byte{r.emphasisMarker(node.Parent, lex.ItemAsterisk)}, 2))
 	}
 	return ast.WalkContinue
 }
 
 func (r *FormatRenderer) renderStrongA6kCloseMarker(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
-		r.WriteString("**")
+		r.Write(bytes.Repeat([]byte{r.emphasisMarker(node.Parent, lex.ItemAsterisk)}, 2))
 	}
 	return ast.WalkContinue
 }
 
 func (r *FormatRenderer) renderStrongU8eOpenMarker(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
-		r.WriteString("__")
+		r.Write(bytes.Repeat([]byte{r.emphasisMarker(node.Parent, lex.ItemUnderscore)}, 2))
 	}
 	return ast.WalkContinue
 }
 
 func (r *FormatRenderer) renderStrongU8eCloseMarker(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
-		r.WriteString("__")
+		r.Write(bytes.Repeat([]byte{r.emphasisMarker(node.Parent, lex.ItemUnderscore)}, 2))
 	}
 	return ast.WalkContinue
 }
@@ -1583,14 +1670,116 @@ func (r *FormatRenderer) renderBlockquoteMarker(node *ast.Node, entering bool) a
 	return ast.WalkContinue
 }
 
+func (r *FormatRenderer) renderAdmonition(node *ast.Node, entering bool) ast.WalkStatus {
+	if 0 == node.AdmonitionFenceLen {
+		// GFM 提示按块引用渲染，首行为 [!NOTE]
+		ret := r.renderBlockquote(node, entering)
+		if entering {
+			r.WriteString(admonitionOpenMarker(node))
+			r.WriteByte(lex.ItemNewline)
+		}
+		return ret
+	}
+
+	if entering {
+		r.Newline()
+		r.WriteString(admonitionOpenMarker(node))
+		r.WriteByte(lex.ItemNewline)
+	} else {
+		buf := bytes.TrimRight(r.Writer.Bytes(), "\n")
+		r.Writer.Reset()
+		r.Write(buf)
+		r.WriteByte(lex.ItemNewline)
+		r.WriteString(strings.Repeat(":", node.AdmonitionFenceLen))
+		if !node.ParentIs(ast.NodeTableCell) {
+			if r.withoutKramdownBlockIAL(node) {
+				r.WriteString("\n\n")
+			} else {
+				r.WriteByte(lex.ItemNewline)
+			}
+		}
+	}
+	return ast.WalkContinue
+}
+
+func (r *FormatRenderer) renderDefinitionList(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+	} else if !node.ParentIs(ast.NodeTableCell) && r.withoutKramdownBlockIAL(node) {
+		r.Newline()
+		r.WriteByte(lex.ItemNewline)
+	}
+	return ast.WalkContinue
+}
+
+func (r *FormatRenderer) renderDefinitionTerm(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if nil != node.Previous && ast.NodeDefinitionDescription == node.Previous.Type {
+			// 新的术语需要使用空行分隔
+			r.WriteByte(lex.ItemNewline)
+		}
+	} else {
+		r.WriteByte(lex.ItemNewline)
+	}
+	return ast.WalkContinue
+}
+
+func (r *FormatRenderer) renderDefinitionDescription(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if previous := node.Previous; nil != previous && ast.NodeDefinitionDescription == previous.Type && nil != previous.FirstChild && previous.FirstChild.IsBlock() {
+			// 包含续块的描述之后需要使用空行分隔
+			r.WriteByte(lex.ItemNewline)
+		}
+		r.Writer = &bytes.Buffer{}
+		r.NodeWriterStack = append(r.NodeWriterStack, r.Writer)
+	} else {
+		writer := r.NodeWriterStack[len(r.NodeWriterStack)-1]
+		r.NodeWriterStack = r.NodeWriterStack[:len(r.NodeWriterStack)-1]
+		r.Writer = r.NodeWriterStack[len(r.NodeWriterStack)-1]
+
+		buf := bytes.TrimSpace(writer.Bytes())
+		if nil == node.FirstChild || !node.FirstChild.IsBlock() {
+			// 描述的续行使用两个空格缩进，避免被解析为新的术语
+			buf = bytes.ReplaceAll(buf, []byte{lex.ItemNewline}, []byte("\n  "))
+			r.WriteString(": ")
+			r.Write(buf)
+			r.WriteByte(lex.ItemNewline)
+			return ast.WalkContinue
+		}
+
+		// 块级内容中第一个段落跟在 : 之后，其余的块作为续块使用空行分隔并缩进四个空格
+		lines := bytes.Split(buf, []byte{lex.ItemNewline})
+		if ast.NodeParagraph == node.FirstChild.Type {
+			r.WriteString(": ")
+			r.Write(lines[0])
+			lines = lines[1:]
+		} else {
+			r.WriteString(":\n")
+		}
+		for _, line := range lines {
+			r.WriteByte(lex.ItemNewline)
+			if 0 < len(line) {
+				r.WriteString("    ")
+				r.Write(line)
+			}
+		}
+		r.WriteByte(lex.ItemNewline)
+	}
+	return ast.WalkContinue
+}
+
 func (r *FormatRenderer) renderHeading(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
-		if !node.HeadingSetext {
+		if nil != r.linkRefLabels && r.linkRefSectionStart(node) {
+			r.linkRefSection++
+			r.renderLinkRefDefs(r.linkRefSection)
+		}
+		if !r.headingSetext(node) {
 			r.Write(bytes.Repeat([]byte{lex.ItemCrosshatch}, node.HeadingLevel))
 			r.WriteByte(lex.ItemSpace)
 		}
 	} else {
-		if node.HeadingSetext {
+		if r.headingSetext(node) {
 			r.WriteByte(lex.ItemNewline)
 			contentLen := r.setextHeadingLen(node)
 			if 1 == node.HeadingLevel {
@@ -1656,10 +1845,8 @@ func (r *FormatRenderer) renderListItem(node *ast.Node, entering bool) ast.WalkS
 	} else {
 		writer := r.NodeWriterStack[len(r.NodeWriterStack)-1]
 		r.NodeWriterStack = r.NodeWriterStack[:len(r.NodeWriterStack)-1]
-		indent := len(node.ListData.Marker) + 1
-		if 1 == node.ListData.Typ || (3 == node.ListData.Typ && 0 == node.ListData.BulletChar) {
-			indent++
-		}
+		marker := r.listItemMarker(node)
+		indent := len(marker) + 1
 		indentSpaces := bytes.Repeat([]byte{lex.ItemSpace}, indent)
 		indentedLines := bytes.Buffer{}
 		buf := writer.Bytes()
@@ -1679,11 +1866,7 @@ func (r *FormatRenderer) renderListItem(node *ast.Node, entering bool) ast.WalkS
 		}
 
 		listItemBuf := bytes.Buffer{}
-		if 1 == node.ListData.Typ || (3 == node.ListData.Typ && 0 == node.ListData.BulletChar) {
-			listItemBuf.WriteString(strconv.Itoa(node.ListData.Num) + string(node.ListData.Delimiter))
-		} else {
-			listItemBuf.Write(node.ListData.Marker)
-		}
+		listItemBuf.Write(marker)
 		listItemBuf.WriteByte(lex.ItemSpace)
 		buf = append(listItemBuf.Bytes(), buf...)
 		if node.ParentIs(ast.NodeTableCell) {
@@ -1752,8 +1935,26 @@ func (r *FormatRenderer) renderHardBreak(node *ast.Node, entering bool) ast.Walk
 	return ast.WalkContinue
 }
 
+// hardWrapWidth 返回段落折行宽度，开启 SoftBreak2HardBreak 时折行产生的换行会被渲染为 <br />，因此不折行并返回 0。
+func (r *FormatRenderer) hardWrapWidth() int {
+	if r.Options.SoftBreak2HardBreak {
+		return 0
+	}
+	return r.style().HardWrap
+}
+
 func (r *FormatRenderer) renderSoftBreak(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
+		if heading := parentHeading(node); nil != heading && !r.headingSetext(heading) {
+			// Setext 标题转换为 ATX 标题时需要合并为一行
+			r.WriteByte(lex.ItemSpace)
+			return ast.WalkContinue
+		}
+		if 0 < r.hardWrapWidth() && ast.NodeParagraph == node.Parent.Type && !node.ParentIs(ast.NodeTableCell) {
+			// 段落折行时重新排列
+			r.WriteByte(lex.ItemSpace)
+			return ast.WalkContinue
+		}
 		r.Newline()
 	}
 	return ast.WalkContinue
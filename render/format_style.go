@@ -0,0 +1,383 @@
+package render
+
+import (
+	"bytes"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pafthang/md/ast"
+	"github.com/pafthang/md/lex"
+)
+
+// FormatStyle 描述了格式化渲染器的输出风格，零值字段保持原文风格。
+type FormatStyle struct {
+	// BulletChar 设置无序列表标记符，可选 - * +。相邻的两个无序列表会交替使用另一个标记符，避免合并为一个列表。
+	BulletChar byte
+	// EmphasisChar 设置强调和加粗标记符，可选 * _。单词内部的强调仍然使用 *，因为 _ 在单词内部不能作为强调标记符。
+	EmphasisChar byte
+	// HeadingStyle 设置标题风格，Setext 风格仅适用于一级和二级标题。
+	HeadingStyle HeadingStyle
+	// FenceChar 设置代码块围栏标记符，可选 ` ~，缩进代码块也会转换为围栏代码块。信息字符串中包含 ` 时使用 ~。
+	FenceChar byte
+	// FenceLength 设置代码块围栏最小长度，代码中包含同样的围栏时会自动加长。
+	FenceLength int
+	// OrderedListNumbering 设置有序列表序号风格。
+	OrderedListNumbering OrderedListNumbering
+	// CompactTable 设置是否不再补齐表格单元格宽度。
+	CompactTable bool
+	// HardWrap 设置段落在多少列处自动折行，为 0 时不折行。列数不包含列表和引述的缩进，链接地址、行级代码和 HTML 不会被折断。
+	// 开启渲染选项 SoftBreak2HardBreak（默认开启）时折行产生的软换行会被渲染为 <br />，因此该设置仅在关闭 SoftBreak2HardBreak 后生效。
+	HardWrap int
+	// LinkStyle 设置链接风格，转换为引用链接需要开启解析选项 LinkRef。
+	LinkStyle LinkStyle
+	// LinkRefLabel 设置转换为引用链接时新生成的标签风格，原文中引用链接的标签保持不变。
+	LinkRefLabel LinkRefLabel
+	// LinkRefPlacement 设置转换为引用链接时链接引用定义的位置。
+	LinkRefPlacement LinkRefPlacement
+}
+
+// HeadingStyle 描述了标题风格。
+type HeadingStyle int
+
+const (
+	HeadingStyleKeep   HeadingStyle = iota // 保持原文风格
+	HeadingStyleATX                        // # 标题
+	HeadingStyleSetext                     // 标题下一行使用 === 或者 ---
+)
+
+// OrderedListNumbering 描述了有序列表序号风格。
+type OrderedListNumbering int
+
+const (
+	OrderedListSequential OrderedListNumbering = iota // 从起始序号开始依次递增
+	OrderedListOne                                    // 所有列表项都使用起始序号，比如都是 1.
+)
+
+// LinkStyle 描述了链接风格。
+type LinkStyle int
+
+const (
+	LinkStyleKeep      LinkStyle = iota // 保持原文风格
+	LinkStyleInline                     // 将引用链接 [text][label] 转换为行内链接 [text](dest "title")，并去掉所有链接引用定义
+	LinkStyleReference                  // 将行内链接和自动链接转换为引用链接，合并地址和标题相同的链接引用定义，去掉没有使用的定义
+)
+
+// LinkRefLabel 描述了转换为引用链接时新生成的标签风格。
+type LinkRefLabel int
+
+const (
+	LinkRefLabelNumber LinkRefLabel = iota // 使用递增的数字，比如 [text][1]
+	LinkRefLabelText                       // 使用链接文本，重复时追加 -2、-3 后缀，比如 [text][text]
+)
+
+// LinkRefPlacement 描述了转换为引用链接时链接引用定义的位置。
+type LinkRefPlacement int
+
+const (
+	LinkRefPlacementDocument LinkRefPlacement = iota // 放在文档末尾
+	LinkRefPlacementSection                          // 放在第一次使用该定义的章节末尾，即下一个顶层标题之前
+)
+
+var defaultFormatStyle = &FormatStyle{}
+
+// style 返回格式化风格，没有设置时返回零值。
+func (r *FormatRenderer) style() *FormatStyle {
+	if nil == r.Options.FormatStyle {
+		return defaultFormatStyle
+	}
+	return r.Options.FormatStyle
+}
+
+// listItemMarker 返回列表项 listItem 的标记符，有序列表包含分隔符。
+func (r *FormatRenderer) listItemMarker(listItem *ast.Node) []byte {
+	data := listItem.ListData
+	if 1 == data.Typ || (3 == data.Typ && 0 == data.BulletChar) {
+		num := data.Num
+		if OrderedListOne == r.style().OrderedListNumbering && nil != listItem.Parent && nil != listItem.Parent.ListData {
+			num = listItem.Parent.ListData.Start
+		}
+		return []byte(strconv.Itoa(num) + string(data.Delimiter))
+	}
+
+	bullet := r.style().BulletChar
+	if 0 == bullet || nil == listItem.Parent {
+		return data.Marker
+	}
+	// 紧挨着的无序列表如果使用相同的标记符会被合并，所以交替使用
+	for prev := listItem.Parent.Previous; nil != prev && ast.NodeList == prev.Type && 0 != prev.ListData.BulletChar; prev = prev.Previous {
+		if lex.ItemHyphen == bullet {
+			bullet = lex.ItemAsterisk
+		} else {
+			bullet = lex.ItemHyphen
+		}
+	}
+	return []byte{bullet}
+}
+
+// emphasisMarker 返回强调或者加粗节点 node 使用的标记符字符，marker 为原文标记符字符。
+func (r *FormatRenderer) emphasisMarker(node *ast.Node, marker byte) byte {
+	c := r.style().EmphasisChar
+	if 0 == c || c == marker {
+		return marker
+	}
+
+	// 直接嵌套的强调如果统一标记符会产生 *** 这样的歧义，保持原样
+	if emphasisNested(node) {
+		return marker
+	}
+	if lex.ItemUnderscore == c && (isWordRune(lastRune(node.Previous)) || isWordRune(firstRune(node.Next))) {
+		return marker
+	}
+	return c
+}
+
+func emphasisNested(node *ast.Node) bool {
+	isEmphasis := func(n *ast.Node) bool {
+		return nil != n && (ast.NodeEmphasis == n.Type || ast.NodeStrong == n.Type)
+	}
+	if nil != node.FirstChild && (isEmphasis(node.FirstChild.Next) || (nil != node.LastChild && isEmphasis(node.LastChild.Previous))) {
+		return true
+	}
+	if isEmphasis(node.Parent) && ((nil != node.Previous && node.Previous == node.Parent.FirstChild) || (nil != node.Next && node.Next == node.Parent.LastChild)) {
+		return true
+	}
+	return false
+}
+
+func lastRune(n *ast.Node) rune {
+	if nil == n || ast.NodeText != n.Type {
+		return utf8.RuneError
+	}
+	r, _ := utf8.DecodeLastRune(n.Tokens)
+	return r
+}
+
+func firstRune(n *ast.Node) rune {
+	if nil == n || ast.NodeText != n.Type {
+		return utf8.RuneError
+	}
+	r, _ := utf8.DecodeRune(n.Tokens)
+	return r
+}
+
+func isWordRune(r rune) bool {
+	return utf8.RuneError != r && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// headingSetext 判断标题 heading 是否输出为 Setext 风格。
+func (r *FormatRenderer) headingSetext(heading *ast.Node) bool {
+	switch r.style().HeadingStyle {
+	case HeadingStyleATX:
+		return false
+	case HeadingStyleSetext:
+		if 2 < heading.HeadingLevel || (nil != r.Tree && !r.Tree.Context.ParseOption.Setext) {
+			return false
+		}
+		// 内容以块级标记开头的标题转换为 Setext 风格后会被解析为其他块，比如 # - foo
+		first := heading.FirstChild
+		if nil != first && ast.NodeHeadingC8hMarker == first.Type {
+			first = first.Next
+		}
+		return nil != first && !(ast.NodeText == first.Type && formatBlockStart(first.Tokens))
+	}
+	return heading.HeadingSetext
+}
+
+func parentHeading(node *ast.Node) *ast.Node {
+	for p := node.Parent; nil != p; p = p.Parent {
+		if ast.NodeHeading == p.Type {
+			return p
+		}
+	}
+	return nil
+}
+
+// codeFence 返回代码块 codeBlock 使用的围栏，marker 为原文围栏。
+func (r *FormatRenderer) codeFence(codeBlock *ast.Node, marker []byte) []byte {
+	style := r.style()
+	if 0 == style.FenceChar && 0 == style.FenceLength {
+		return marker
+	}
+
+	c := style.FenceChar
+	if 0 == c {
+		c = lex.ItemBacktick
+		if 0 < len(marker) {
+			c = marker[0]
+		}
+	}
+	if lex.ItemBacktick == c && bytes.IndexByte(codeBlock.CodeBlockInfo, lex.ItemBacktick) >= 0 {
+		c = lex.ItemTilde
+	}
+
+	length := 3
+	if length < style.FenceLength {
+		length = style.FenceLength
+	}
+	if code := codeBlock.ChildByType(ast.NodeCodeBlockCode); nil != code {
+		for _, line := range bytes.Split(code.Tokens, []byte{lex.ItemNewline}) {
+			line = bytes.TrimLeft(line, " ")
+			run := 0
+			for run < len(line) && c == line[run] {
+				run++
+			}
+			if length <= run {
+				length = run + 1
+			}
+		}
+	}
+	return bytes.Repeat([]byte{c}, length)
+}
+
+// hardWrap 将段落内容 paragraph 按照 width 列折行。
+func hardWrap(paragraph []byte, width int) []byte {
+	buf := &bytes.Buffer{}
+	lines := bytes.Split(paragraph, []byte{lex.ItemNewline})
+	for i, line := range lines {
+		if 0 < i {
+			buf.WriteByte(lex.ItemNewline)
+		}
+		wrapLine(buf, line, width)
+	}
+	return buf.Bytes()
+}
+
+func wrapLine(buf *bytes.Buffer, line []byte, width int) {
+	breaks := wrapBreaks(line)
+	lineWidth, start := 0, 0
+	for i := 0; i <= len(breaks); i++ {
+		end, next := len(line), len(line)
+		if i < len(breaks) {
+			end, next = breaks[i][0], breaks[i][1]
+		}
+		word := line[start:end]
+		wordWidth := textWidth(word)
+		if 0 == start {
+			buf.Write(word)
+			lineWidth = wordWidth
+		} else if sep := line[breaks[i-1][0]:start]; width < lineWidth+len(sep)+wordWidth && !formatBlockStart(line[start:]) {
+			buf.WriteByte(lex.ItemNewline)
+			buf.Write(word)
+			lineWidth = wordWidth
+		} else {
+			buf.Write(sep)
+			buf.Write(word)
+			lineWidth += len(sep) + wordWidth
+		}
+		start = next
+	}
+}
+
+// wrapBreaks 返回行 line 中可以折行的空白区间，跳过行级代码、链接地址、自动链接和 HTML 标签。
+func wrapBreaks(line []byte) (ret [][2]int) {
+	for i := 0; i < len(line); {
+		switch c := line[i]; {
+		case lex.ItemBackslash == c:
+			i += 2
+		case lex.ItemBacktick == c:
+			n := 1
+			for i+n < len(line) && lex.ItemBacktick == line[i+n] {
+				n++
+			}
+			marker := line[i : i+n]
+			i += n
+			for j := i; j < len(line); {
+				idx := bytes.Index(line[j:], marker)
+				if 0 > idx {
+					break
+				}
+				j += idx
+				if j+n < len(line) && lex.ItemBacktick == line[j+n] {
+					for j < len(line) && lex.ItemBacktick == line[j] {
+						j++
+					}
+					continue
+				}
+				i = j + n
+				break
+			}
+		case lex.ItemCloseBracket == c && i+1 < len(line) && lex.ItemOpenParen == line[i+1]:
+			depth := 0
+			for i++; i < len(line); i++ {
+				if lex.ItemBackslash == line[i] {
+					i++
+				} else if lex.ItemOpenParen == line[i] {
+					depth++
+				} else if lex.ItemCloseParen == line[i] {
+					if depth--; 0 == depth {
+						i++
+						break
+					}
+				}
+			}
+		case lex.ItemLess == c:
+			if idx := bytes.IndexByte(line[i:], lex.ItemGreater); 0 < idx {
+				i += idx + 1
+			} else {
+				i++
+			}
+		case lex.ItemSpace == c:
+			end := i
+			for end < len(line) && lex.ItemSpace == line[end] {
+				end++
+			}
+			if 0 < i && end < len(line) {
+				ret = append(ret, [2]int{i, end})
+			}
+			i = end
+		default:
+			i++
+		}
+	}
+	return
+}
+
+// formatBlockStart 判断作为行首时 text 是否会被解析为块级节点的开始，比如标题、列表、引述和分隔线。
+func formatBlockStart(text []byte) bool {
+	if 0 == len(text) {
+		return false
+	}
+
+	followedBySpace := func(i int) bool {
+		return i >= len(text) || lex.ItemSpace == text[i] || lex.ItemTab == text[i]
+	}
+	switch c := text[0]; c {
+	case lex.ItemGreater, lex.ItemPipe:
+		return true
+	case lex.ItemCrosshatch:
+		i := 0
+		for i < len(text) && lex.ItemCrosshatch == text[i] {
+			i++
+		}
+		return 6 >= i && followedBySpace(i)
+	case lex.ItemHyphen, lex.ItemPlus, lex.ItemAsterisk, lex.ItemUnderscore, lex.ItemEqual:
+		if followedBySpace(1) {
+			return true
+		}
+		i := 0
+		for i < len(text) && (c == text[i] || lex.ItemPipe == text[i] || lex.ItemColon == text[i]) {
+			i++
+		}
+		return followedBySpace(i)
+	case lex.ItemBacktick, lex.ItemTilde, lex.ItemDollar:
+		return bytes.HasPrefix(text, bytes.Repeat([]byte{c}, 2))
+	case lex.ItemLess:
+		return 1 < len(text) && (lex.IsASCIILetter(text[1]) || lex.ItemSlash == text[1] || lex.ItemBang == text[1] || lex.ItemQuestion == text[1])
+	case lex.ItemOpenBrace:
+		return 1 < len(text) && lex.ItemColon == text[1]
+	}
+
+	i := 0
+	for i < len(text) && lex.IsDigit(text[i]) {
+		i++
+	}
+	return 0 < i && 10 > i && i < len(text) && (lex.ItemDot == text[i] || lex.ItemCloseParen == text[i]) && followedBySpace(i+1)
+}
+
+func textWidth(text []byte) (ret int) {
+	for _, c := range string(text) {
+		ret += terminalRuneWidth(c)
+	}
+	return
+}
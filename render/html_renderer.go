@@ -55,6 +55,10 @@ func NewHtmlRenderer(tree *parse.Tree, options *Options) *HtmlRenderer {
 	ret.RendererFuncs[ast.NodeStrongU8eCloseMarker] = ret.renderStrongU8eCloseMarker
 	ret.RendererFuncs[ast.NodeBlockquote] = ret.renderBlockquote
 	ret.RendererFuncs[ast.NodeBlockquoteMarker] = ret.renderBlockquoteMarker
+	ret.RendererFuncs[ast.NodeAdmonition] = ret.renderAdmonition
+	ret.RendererFuncs[ast.NodeDefinitionList] = ret.renderDefinitionList
+	ret.RendererFuncs[ast.NodeDefinitionTerm] = ret.renderDefinitionTerm
+	ret.RendererFuncs[ast.NodeDefinitionDescription] = ret.renderDefinitionDescription
 	ret.RendererFuncs[ast.NodeHeading] = ret.renderHeading
 	ret.RendererFuncs[ast.NodeHeadingC8hMarker] = ret.renderHeadingC8hMarker
 	ret.RendererFuncs[ast.NodeHeadingID] = ret.renderHeadingID
@@ -114,6 +118,8 @@ func NewHtmlRenderer(tree *parse.Tree, options *Options) *HtmlRenderer {
 	ret.RendererFuncs[ast.NodeFileAnnotationRefID] = ret.renderFileAnnotationRefID
 	ret.RendererFuncs[ast.NodeFileAnnotationRefSpace] = ret.renderFileAnnotationRefSpace
 	ret.RendererFuncs[ast.NodeFileAnnotationRefText] = ret.renderFileAnnotationRefText
+	ret.RendererFuncs[ast.NodeWikiLink] = ret.renderWikiLink
+	ret.RendererFuncs[ast.NodeWikiLinkEmbed] = ret.renderWikiLinkEmbed
 	ret.RendererFuncs[ast.NodeMark] = ret.renderMark
 	ret.RendererFuncs[ast.NodeMark1OpenMarker] = ret.renderMark1OpenMarker
 	ret.RendererFuncs[ast.NodeMark1CloseMarker] = ret.renderMark1CloseMarker
@@ -161,7 +167,12 @@ func NewHtmlRenderer(tree *parse.Tree, options *Options) *HtmlRenderer {
 
 func (r *HtmlRenderer) Render() (output []byte) {
 	output = r.BaseRenderer.Render()
-	output = append(output, r.RenderFootnotes()...)
+	if !r.DeferFootnotes {
+		output = append(output, r.RenderFootnotes()...)
+	}
+	if nil != r.Options.SanitizePolicy {
+		output = r.Options.SanitizePolicy.sanitize(output)
+	}
 	return
 }
 
@@ -286,9 +297,7 @@ func (r *HtmlRenderer) renderVideo(node *ast.Node, entering bool) ast.WalkStatus
 	if entering {
 		r.Tag("div", [][]string{{"class", "iframe"}}, false)
 		tokens := node.Tokens
-		if r.Options.Sanitize {
-			tokens = sanitize(tokens)
-		}
+		tokens = r.sanitize(tokens)
 		tokens = r.tagSrcPath(tokens)
 		r.Write(tokens)
 		r.Tag("/div", nil, false)
@@ -300,9 +309,7 @@ func (r *HtmlRenderer) renderAudio(node *ast.Node, entering bool) ast.WalkStatus
 	if entering {
 		r.Tag("div", [][]string{{"class", "iframe"}}, false)
 		tokens := node.Tokens
-		if r.Options.Sanitize {
-			tokens = sanitize(tokens)
-		}
+		tokens = r.sanitize(tokens)
 		tokens = r.tagSrcPath(tokens)
 		r.Write(tokens)
 		r.Tag("/div", nil, false)
@@ -314,9 +321,7 @@ func (r *HtmlRenderer) renderIFrame(node *ast.Node, entering bool) ast.WalkStatu
 	if entering {
 		r.Tag("div", [][]string{{"class", "iframe"}}, false)
 		tokens := node.Tokens
-		if r.Options.Sanitize {
-			tokens = sanitize(tokens)
-		}
+		tokens = r.sanitize(tokens)
 		tokens = r.tagSrcPath(tokens)
 		r.Write(tokens)
 		r.Tag("/div", nil, false)
@@ -328,9 +333,7 @@ func (r *HtmlRenderer) renderWidget(node *ast.Node, entering bool) ast.WalkStatu
 	if entering {
 		r.Tag("div", [][]string{{"class", "iframe"}}, false)
 		tokens := node.Tokens
-		if r.Options.Sanitize {
-			tokens = sanitize(tokens)
-		}
+		tokens = r.sanitize(tokens)
 		tokens = r.tagSrcPath(tokens)
 		r.Write(tokens)
 		r.Tag("/div", nil, false)
@@ -526,6 +529,7 @@ func (r *HtmlRenderer) renderBlockQueryEmbedScript(node *ast.Node, entering bool
 }
 
 func (r *HtmlRenderer) renderBlockRef(node *ast.Node, entering bool) ast.WalkStatus {
+	r.blockRefLink(node, entering)
 	return ast.WalkContinue
 }
 
@@ -579,6 +583,30 @@ func (r *HtmlRenderer) renderFileAnnotationRefText(node *ast.Node, entering bool
 	return ast.WalkContinue
 }
 
+func (r *HtmlRenderer) renderWikiLink(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		attrs := [][]string{{"href", html.EscapeString(r.WikiLinkDest(node))}, {"class", "wikilink"}}
+		r.Tag("a", attrs, false)
+		r.WriteString(html.EscapeString(node.WikiLinkText()))
+		r.Tag("/a", nil, false)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *HtmlRenderer) renderWikiLinkEmbed(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		dest, text := html.EscapeString(r.WikiLinkDest(node)), html.EscapeString(node.WikiLinkText())
+		if isWikiLinkImage(node) {
+			r.Tag("img", [][]string{{"src", dest}, {"alt", text}, {"class", "wikilink-embed"}}, true)
+		} else {
+			r.Tag("a", [][]string{{"href", dest}, {"class", "wikilink wikilink-embed"}}, false)
+			r.WriteString(text)
+			r.Tag("/a", nil, false)
+		}
+	}
+	return ast.WalkSkipChildren
+}
+
 func (r *HtmlRenderer) renderYamlFrontMatterCloseMarker(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
 		r.WriteString("</code></pre>")
@@ -635,7 +663,9 @@ func (r *HtmlRenderer) renderFootnotesRef(node *ast.Node, entering bool) ast.Wal
 		idx, _ := r.Tree.FindFootnotesDef(node.Tokens)
 		idxStr := strconv.Itoa(idx)
 		r.Tag("sup", [][]string{{"class", "footnotes-ref"}, {"id", "footnotes-ref-" + node.FootnotesRefId}}, false)
-		r.Tag("a", [][]string{{"href", r.Options.LinkBase + "#footnotes-def-" + idxStr}}, false)
+		href, linkAttrs := r.ResolveLink(node, []byte("#footnotes-def-"+idxStr))
+		href = r.sanitizeURL(href)
+		r.Tag("a", append([][]string{{"href", r.Options.LinkBase + util.BytesToStr(html.EscapeHTML(href))}}, linkAttrs...), false)
 		r.WriteString(idxStr)
 		r.Tag("/a", nil, false)
 		r.Tag("/sup", nil, false)
@@ -963,14 +993,14 @@ func (r *HtmlRenderer) renderImage(node *ast.Node, entering bool) ast.WalkStatus
 			}
 
 			r.WriteString("<img src=\"")
-			destTokens := node.ChildByType(ast.NodeLinkDest).Tokens
+			destTokens, linkAttrs := r.ResolveLink(node, node.ChildByType(ast.NodeLinkDest).Tokens)
 			destTokens = r.LinkPath(destTokens)
 			if "" != r.Options.ImageLazyLoading {
 				r.Write(html.EscapeHTML(util.StrToBytes(r.Options.ImageLazyLoading)))
 				r.WriteString("\" data-src=\"")
 			}
 			r.Write(html.EscapeHTML(destTokens))
-			r.WriteString("\" alt=\"")
+			r.WriteString("\"" + r.linkAttrsStr(linkAttrs) + " alt=\"")
 		}
 		r.DisableTags++
 		return ast.WalkContinue
@@ -993,13 +1023,11 @@ func (r *HtmlRenderer) renderImage(node *ast.Node, entering bool) ast.WalkStatus
 			r.Tag("/span", nil, false)
 		}
 
-		if r.Options.Sanitize {
+		if r.sanitizing() {
 			buf := r.Writer.Bytes()
 			idx := bytes.LastIndex(buf, []byte("<img src="))
 			imgBuf := buf[idx:]
-			if r.Options.Sanitize {
-				imgBuf = sanitize(imgBuf)
-			}
+			imgBuf = r.sanitize(imgBuf)
 			r.Writer.Truncate(idx)
 			r.Writer.Write(imgBuf)
 		}
@@ -1012,19 +1040,14 @@ func (r *HtmlRenderer) renderLink(node *ast.Node, entering bool) ast.WalkStatus
 		r.LinkTextAutoSpacePrevious(node)
 
 		dest := node.ChildByType(ast.NodeLinkDest)
-		destTokens := dest.Tokens
-		if r.Options.Sanitize {
-			tokens := bytes.TrimSpace(destTokens)
-			tokens = bytes.ToLower(tokens)
-			if bytes.HasPrefix(tokens, []byte("javascript:")) {
-				destTokens = nil
-			}
-		}
+		destTokens, linkAttrs := r.ResolveLink(node, dest.Tokens)
+		destTokens = r.sanitizeURL(destTokens)
 		destTokens = r.LinkPath(destTokens)
 		attrs := [][]string{{"href", util.BytesToStr(html.EscapeHTML(destTokens))}}
 		if title := node.ChildByType(ast.NodeLinkTitle); nil != title && nil != title.Tokens {
 			attrs = append(attrs, []string{"title", util.BytesToStr(html.EscapeHTML(title.Tokens))})
 		}
+		attrs = append(attrs, linkAttrs...)
 		r.Tag("a", attrs, false)
 	} else {
 		r.Tag("/a", nil, false)
@@ -1038,9 +1061,7 @@ func (r *HtmlRenderer) renderHTML(node *ast.Node, entering bool) ast.WalkStatus
 	if entering {
 		r.Newline()
 		tokens := node.Tokens
-		if r.Options.Sanitize {
-			tokens = sanitize(tokens)
-		}
+		tokens = r.sanitize(tokens)
 		tokens = r.tagSrcPath(tokens)
 		r.Write(tokens)
 		r.Newline()
@@ -1051,9 +1072,7 @@ func (r *HtmlRenderer) renderHTML(node *ast.Node, entering bool) ast.WalkStatus
 func (r *HtmlRenderer) renderInlineHTML(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
 		tokens := node.Tokens
-		if r.Options.Sanitize {
-			tokens = sanitize(tokens)
-		}
+		tokens = r.sanitize(tokens)
 		r.Write(tokens)
 	}
 	return ast.WalkContinue
@@ -1237,6 +1256,63 @@ func (r *HtmlRenderer) renderBlockquoteMarker(node *ast.Node, entering bool) ast
 	return ast.WalkContinue
 }
 
+func (r *HtmlRenderer) renderAdmonition(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.handleKramdownBlockIAL(node)
+		attrs := [][]string{{"class", "admonition admonition-" + html.EscapeString(node.AdmonitionKind)}}
+		attrs = append(attrs, node.KramdownIAL...)
+		r.Tag("div", attrs, false)
+		r.Newline()
+		r.Tag("p", [][]string{{"class", "admonition-title"}}, false)
+		r.WriteString(html.EscapeString(AdmonitionTitle(node)))
+		r.Tag("/p", nil, false)
+		r.Newline()
+	} else {
+		r.Newline()
+		r.Tag("/div", nil, false)
+		r.Newline()
+	}
+	return ast.WalkContinue
+}
+
+func (r *HtmlRenderer) renderDefinitionList(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.handleKramdownBlockIAL(node)
+		r.Tag("dl", node.KramdownIAL, false)
+		r.Newline()
+	} else {
+		r.Newline()
+		r.Tag("/dl", nil, false)
+		r.Newline()
+	}
+	return ast.WalkContinue
+}
+
+func (r *HtmlRenderer) renderDefinitionTerm(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Tag("dt", nil, false)
+	} else {
+		r.Tag("/dt", nil, false)
+		r.Newline()
+	}
+	return ast.WalkContinue
+}
+
+func (r *HtmlRenderer) renderDefinitionDescription(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Tag("dd", nil, false)
+		if nil != node.FirstChild && node.FirstChild.IsBlock() {
+			r.Newline()
+		}
+	} else {
+		r.Tag("/dd", nil, false)
+		r.Newline()
+	}
+	return ast.WalkContinue
+}
+
 const headingLevel = " 123456"
 
 func (r *HtmlRenderer) renderHeading(node *ast.Node, entering bool) ast.WalkStatus {
@@ -1244,7 +1320,7 @@ func (r *HtmlRenderer) renderHeading(node *ast.Node, entering bool) ast.WalkStat
 		r.Newline()
 		level := headingLevel[node.HeadingLevel : node.HeadingLevel+1]
 		r.WriteString("<h" + level)
-		id := HeadingID(node)
+		id := r.HeadingID(node)
 		if r.Options.ToC || r.Options.HeadingID || r.Options.KramdownBlockIAL {
 			r.WriteString(" id=\"" + id + "\"")
 			if r.Options.KramdownBlockIAL {
@@ -1254,7 +1330,7 @@ func (r *HtmlRenderer) renderHeading(node *ast.Node, entering bool) ast.WalkStat
 				if 1 < len(node.KramdownIAL) {
 					exceptID := node.KramdownIAL[1:]
 					for _, attr := range exceptID {
-						r.WriteString(" " + attr[0] + "=\"" + attr[1] + "\"")
+						r.WriteString(r.attrStr(attr[0], attr[1]))
 					}
 				}
 			}
@@ -1262,7 +1338,7 @@ func (r *HtmlRenderer) renderHeading(node *ast.Node, entering bool) ast.WalkStat
 		r.WriteString(">")
 	} else {
 		if r.Options.HeadingAnchor {
-			id := HeadingID(node)
+			id := r.HeadingID(node)
 			r.Tag("a", [][]string{{"id", "editorAnchor-" + id}, {"class", "editor-anchor"}, {"href", "#" + id}}, false)
 			r.WriteString(`<svg viewBox="0 0 16 16" version="1.1" width="16" height="16"><path fill-rule="evenodd" d="M4 9h1v1H4c-1.5 0-3-1.69-3-3.5S2.55 3 4 3h4c1.45 0 3 1.69 3 3.5 0 1.41-.91 2.72-2 3.25V8.59c.58-.45 1-1.27 1-2.09C10 5.22 8.98 4 8 4H4c-.98 0-2 1.22-2 2.5S3 9 4 9zm9-3h-1v1h1c1 0 2 1.22 2 2.5S13.98 12 13 12H9c-.98 0-2-1.22-2-2.5 0-.83.42-1.64 1-2.09V6.25c-1.09.53-2 1.84-2 3.25C6 11.31 7.55 13 9 13h4c1.45 0 3-1.69 3-3.5S14.5 6 13 6z"></path></svg>`)
 			r.Tag("/a", nil, false)
@@ -1387,8 +1463,11 @@ func (r *HtmlRenderer) renderTextMarkAttrs(node *ast.Node) (attrs [][]string) {
 		if "block-ref" == typ {
 			attrs = append(attrs, []string{"data-subtype", node.TextMarkBlockRefSubtype})
 			attrs = append(attrs, []string{"data-id", node.TextMarkBlockRefID})
+			if href := r.ResolveLinkStr(node, node.TextMarkBlockRefID); href != node.TextMarkBlockRefID {
+				attrs = append(attrs, []string{"data-href", util.BytesToStr(r.LinkPath([]byte(href)))})
+			}
 		} else if "a" == typ {
-			href := node.TextMarkAHref
+			href := r.ResolveLinkStr(node, node.TextMarkAHref)
 			href = string(r.LinkPath([]byte(href)))
 
 			attrs = append(attrs, []string{"data-href", href})
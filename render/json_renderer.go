@@ -2,6 +2,7 @@ package render
 
 import (
 	"encoding/json"
+	"errors"
 
 	"github.com/pafthang/md/ast"
 	"github.com/pafthang/md/parse"
@@ -31,19 +32,28 @@ func NewJSONRenderer(tree *parse.Tree, options *Options) Renderer {
 	return ret
 }
 
+// Render 渲染输出 JSON，节点序列化失败时返回 nil，错误可以通过 Err 获取。
+func (r *JSONRenderer) Render() (output []byte) {
+	output = r.BaseRenderer.Render()
+	if nil != r.Err() {
+		return nil
+	}
+	return
+}
+
 func (r *JSONRenderer) renderNode(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
 		if nil != node.Previous {
 			r.WriteString(",")
 		}
-		node.Data, node.TypeStr = util.BytesToStr(node.Tokens), node.Type.String()
+		node.Data, node.TypeStr = util.BytesToStr(node.Tokens), r.nodeTypeName(node)
 		node.Properties = ial2Map(node.KramdownIAL)
 		delete(node.Properties, "refcount")
 		data, err := json.Marshal(node)
 		node.Data, node.TypeStr = "", ""
 		node.Properties = nil
 		if nil != err {
-			panic("marshal node to json failed: " + err.Error())
+			r.setErr(errors.New("marshal node [type=" + r.nodeTypeName(node) + "] to json failed: " + err.Error()))
 			return ast.WalkStop
 		}
 		n := util.BytesToStr(data)
@@ -317,7 +317,7 @@ func (r *KityMinderJSONRenderer) data(node *ast.Node) {
 	}
 	r.WriteString("\"text\":\"" + text + "\",")
 	r.WriteString("\"id\":\"" + node.IALAttr("id") + "\",")
-	r.WriteString("\"type\":\"" + node.Type.String() + "\",")
+	r.WriteString("\"type\":\"" + r.nodeTypeName(node) + "\",")
 	r.WriteString("\"isContainer\":" + strconv.FormatBool(node.IsContainerBlock()))
 	r.closeObj()
 }
@@ -0,0 +1,732 @@
+package render
+
+import (
+	"bytes"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pafthang/md/ast"
+	"github.com/pafthang/md/lex"
+	"github.com/pafthang/md/parse"
+	"github.com/pafthang/md/util"
+)
+
+// LaTeXRenderer 描述了 LaTeX 渲染器。
+//
+// 标题映射为 \section 等分节命令，表格在顶层使用 longtable、在容器块中使用 tabular，脚注渲染为 \footnote，
+// 数学公式原样透传，HTML 块和行级 HTML 会被忽略。
+type LaTeXRenderer struct {
+	*BaseRenderer
+
+	// Standalone 设置是否输出包含导言区的完整文档，设置为 false 时只输出正文，便于嵌入到已有的 LaTeX 模板中。
+	Standalone bool
+	// CodeBlockListings 设置代码块是否使用 listings 宏包的 lstlisting 环境渲染，默认使用 verbatim 环境。
+	CodeBlockListings bool
+
+	footnoteLabels map[*ast.Node]string // 已经渲染过的脚注定义对应的标签
+	enumDepth      int                  // 有序列表嵌套深度
+	tableCell      bool                 // 是否正在渲染表格单元格
+	footnote       bool                 // 是否正在渲染脚注内容，\footnote 参数中不能使用 verbatim 等逐字环境
+}
+
+// NewLaTeXRenderer 创建一个 LaTeX 渲染器。
+func NewLaTeXRenderer(tree *parse.Tree, options *Options) *LaTeXRenderer {
+	ret := &LaTeXRenderer{BaseRenderer: NewBaseRenderer(tree, options), Standalone: true}
+	ret.RendererFuncs[ast.NodeDocument] = ret.renderDocument
+	ret.RendererFuncs[ast.NodeParagraph] = ret.renderParagraph
+	ret.RendererFuncs[ast.NodeText] = ret.renderText
+	ret.RendererFuncs[ast.NodeCodeSpan] = ret.renderCodeSpan
+	ret.RendererFuncs[ast.NodeCodeSpanContent] = ret.renderText
+	ret.RendererFuncs[ast.NodeCodeBlock] = ret.renderCodeBlock
+	ret.RendererFuncs[ast.NodeMathBlock] = ret.renderMathBlock
+	ret.RendererFuncs[ast.NodeInlineMath] = ret.renderInlineMath
+	ret.RendererFuncs[ast.NodeEmphasis] = ret.renderEmphasis
+	ret.RendererFuncs[ast.NodeStrong] = ret.renderStrong
+	ret.RendererFuncs[ast.NodeBlockquote] = ret.renderBlockquote
+	ret.RendererFuncs[ast.NodeAdmonition] = ret.renderAdmonition
+	ret.RendererFuncs[ast.NodeHeading] = ret.renderHeading
+	ret.RendererFuncs[ast.NodeList] = ret.renderList
+	ret.RendererFuncs[ast.NodeListItem] = ret.renderListItem
+	ret.RendererFuncs[ast.NodeTaskListItemMarker] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeThematicBreak] = ret.renderThematicBreak
+	ret.RendererFuncs[ast.NodeHardBreak] = ret.renderHardBreak
+	ret.RendererFuncs[ast.NodeSoftBreak] = ret.renderSoftBreak
+	ret.RendererFuncs[ast.NodeBr] = ret.renderHardBreak
+	ret.RendererFuncs[ast.NodeHTMLBlock] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeInlineHTML] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeLink] = ret.renderLink
+	ret.RendererFuncs[ast.NodeImage] = ret.renderImage
+	ret.RendererFuncs[ast.NodeLinkText] = ret.renderLinkText
+	ret.RendererFuncs[ast.NodeHTMLEntity] = ret.renderText
+	ret.RendererFuncs[ast.NodeBackslashContent] = ret.renderText
+	ret.RendererFuncs[ast.NodeStrikethrough] = ret.renderStrikethrough
+	ret.RendererFuncs[ast.NodeTable] = ret.renderTable
+	ret.RendererFuncs[ast.NodeTableHead] = ret.renderTableHead
+	ret.RendererFuncs[ast.NodeTableRow] = ret.renderTableRow
+	ret.RendererFuncs[ast.NodeTableCell] = ret.renderTableCell
+	ret.RendererFuncs[ast.NodeEmojiUnicode] = ret.renderText
+	ret.RendererFuncs[ast.NodeEmojiImg] = ret.renderEmojiImg
+	ret.RendererFuncs[ast.NodeFootnotesDefBlock] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeFootnotesRef] = ret.renderFootnotesRef
+	ret.RendererFuncs[ast.NodeToC] = ret.renderToC
+	ret.RendererFuncs[ast.NodeYamlFrontMatter] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeLinkRefDefBlock] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeBlockRefText] = ret.renderText
+	ret.RendererFuncs[ast.NodeBlockRefDynamicText] = ret.renderText
+	ret.RendererFuncs[ast.NodeFileAnnotationRefText] = ret.renderText
+	ret.RendererFuncs[ast.NodeWikiLink] = ret.renderWikiLink
+	ret.RendererFuncs[ast.NodeWikiLinkEmbed] = ret.renderWikiLink
+	ret.RendererFuncs[ast.NodeMark] = ret.renderMark
+	ret.RendererFuncs[ast.NodeSup] = ret.renderSup
+	ret.RendererFuncs[ast.NodeSub] = ret.renderSub
+	ret.RendererFuncs[ast.NodeKbd] = ret.renderKbd
+	ret.RendererFuncs[ast.NodeUnderline] = ret.renderUnderline
+	ret.RendererFuncs[ast.NodeTextMark] = ret.renderTextMark
+	ret.RendererFuncs[ast.NodeKramdownBlockIAL] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeKramdownSpanIAL] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeBlockQueryEmbed] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeIFrame] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeAudio] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeVideo] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeWidget] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeAttributeView] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeGitConflict] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeCustomBlock] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeDefinitionList] = ret.renderDefinitionList
+	ret.RendererFuncs[ast.NodeDefinitionTerm] = ret.renderDefinitionTerm
+	ret.RendererFuncs[ast.NodeDefinitionDescription] = ret.renderDefinitionDescription
+	ret.DefaultRendererFunc = ret.renderDefault
+	return ret
+}
+
+// Render 渲染输出 LaTeX 文本。
+func (r *LaTeXRenderer) Render() (output []byte) {
+	r.footnoteLabels = map[*ast.Node]string{}
+	r.enumDepth, r.tableCell, r.footnote = 0, false, false
+	return r.BaseRenderer.Render()
+}
+
+// latexPreamble 是完整文档的导言区，同时兼容 pdfLaTeX 和 XeLaTeX/LuaLaTeX。
+const latexPreamble = `\documentclass{article}
+\usepackage{iftex}
+\ifPDFTeX
+  \usepackage[T1]{fontenc}
+  \usepackage[utf8]{inputenc}
+\else
+  \usepackage{fontspec}
+\fi
+\usepackage{amsmath,amssymb}
+\usepackage{graphicx}
+\usepackage{longtable,booktabs}
+\usepackage{listings}
+\usepackage[normalem]{ulem}
+\usepackage{xcolor}
+\usepackage{hyperref}
+\makeatletter
+\def\maxwidth{\ifdim\Gin@nat@width>\linewidth\linewidth\else\Gin@nat@width\fi}
+\makeatother
+\setkeys{Gin}{width=\maxwidth,keepaspectratio}
+\lstset{basicstyle=\ttfamily\small,breaklines=true,columns=fullflexible}
+`
+
+// walk 使用和 BaseRenderer.Render 相同的分派规则渲染以 node 为根的子树。
+func (r *LaTeXRenderer) walk(node *ast.Node) {
+	ast.Walk(node, func(n *ast.Node, entering bool) ast.WalkStatus {
+		if extRender := r.ExtRendererFuncs[n.Type]; nil != extRender {
+			output, status := extRender(n, entering)
+			r.WriteString(output)
+			return status
+		}
+		if render := r.RendererFuncs[n.Type]; nil != render {
+			return render(n, entering)
+		}
+		return r.DefaultRendererFunc(n, entering)
+	})
+}
+
+// blankLine 结束当前块，输出一个空行。
+func (r *LaTeXRenderer) blankLine() {
+	r.Newline()
+	if buf := r.Writer.Bytes(); 1 < len(buf) && lex.ItemNewline != buf[len(buf)-2] {
+		r.WriteByte(lex.ItemNewline)
+	}
+}
+
+func (r *LaTeXRenderer) renderDefault(node *ast.Node, entering bool) ast.WalkStatus {
+	return ast.WalkContinue
+}
+
+func (r *LaTeXRenderer) renderSkip(node *ast.Node, entering bool) ast.WalkStatus {
+	return ast.WalkSkipChildren
+}
+
+func (r *LaTeXRenderer) renderDocument(node *ast.Node, entering bool) ast.WalkStatus {
+	if !r.Standalone {
+		return ast.WalkContinue
+	}
+
+	if entering {
+		r.WriteString(latexPreamble)
+		r.WriteString("\\begin{document}\n\n")
+	} else {
+		r.Newline()
+		r.WriteString("\\end{document}\n")
+	}
+	return ast.WalkContinue
+}
+
+// endBlock 结束块 node，紧凑列表项中的块之间不输出空行。
+func (r *LaTeXRenderer) endBlock(node *ast.Node) {
+	if item := node.Parent; nil != item && ast.NodeListItem == item.Type && nil != item.Parent && item.Parent.ListData.Tight {
+		r.Newline()
+		return
+	}
+	r.blankLine()
+}
+
+func (r *LaTeXRenderer) renderParagraph(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering {
+		r.endBlock(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *LaTeXRenderer) renderText(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString(LaTeXEscape(util.BytesToStr(node.Tokens)))
+	}
+	return ast.WalkContinue
+}
+
+// command 在进入节点时输出 \name{，在离开节点时输出 }。
+func (r *LaTeXRenderer) command(name string, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString("\\" + name + "{")
+	} else {
+		r.WriteByte(lex.ItemCloseBrace)
+	}
+	return ast.WalkContinue
+}
+
+func (r *LaTeXRenderer) renderCodeSpan(node *ast.Node, entering bool) ast.WalkStatus {
+	return r.command("texttt", entering)
+}
+
+func (r *LaTeXRenderer) renderKbd(node *ast.Node, entering bool) ast.WalkStatus {
+	return r.command("texttt", entering)
+}
+
+func (r *LaTeXRenderer) renderEmphasis(node *ast.Node, entering bool) ast.WalkStatus {
+	return r.command("emph", entering)
+}
+
+func (r *LaTeXRenderer) renderStrong(node *ast.Node, entering bool) ast.WalkStatus {
+	return r.command("textbf", entering)
+}
+
+func (r *LaTeXRenderer) renderStrikethrough(node *ast.Node, entering bool) ast.WalkStatus {
+	return r.command("sout", entering)
+}
+
+func (r *LaTeXRenderer) renderUnderline(node *ast.Node, entering bool) ast.WalkStatus {
+	return r.command("uline", entering)
+}
+
+func (r *LaTeXRenderer) renderSup(node *ast.Node, entering bool) ast.WalkStatus {
+	return r.command("textsuperscript", entering)
+}
+
+func (r *LaTeXRenderer) renderSub(node *ast.Node, entering bool) ast.WalkStatus {
+	return r.command("textsubscript", entering)
+}
+
+func (r *LaTeXRenderer) renderMark(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString("\\colorbox{yellow}{")
+	} else {
+		r.WriteByte(lex.ItemCloseBrace)
+	}
+	return ast.WalkContinue
+}
+
+func (r *LaTeXRenderer) renderInlineMath(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if content := node.ChildByType(ast.NodeInlineMathContent); nil != content {
+			r.WriteString("$" + strings.TrimSpace(util.BytesToStr(content.Tokens)) + "$")
+		}
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *LaTeXRenderer) renderMathBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.WriteString("\\[\n")
+		if content := node.ChildByType(ast.NodeMathBlockContent); nil != content {
+			r.WriteString(strings.TrimSpace(util.BytesToStr(content.Tokens)))
+		}
+		r.WriteString("\n\\]")
+		r.blankLine()
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *LaTeXRenderer) renderCodeBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering {
+		return ast.WalkContinue
+	}
+
+	var code string
+	if codeNode := node.ChildByType(ast.NodeCodeBlockCode); nil != codeNode {
+		code = util.BytesToStr(codeNode.Tokens)
+	}
+	code = strings.TrimSuffix(code, "\n")
+
+	r.Newline()
+	if r.footnote || strings.Contains(code, "\\end{verbatim}") || strings.Contains(code, "\\end{lstlisting}") {
+		// 逐字环境不能嵌套在命令参数中，代码中出现结束环境的行时也无法安全地逐字输出，所以转义后按行输出
+		r.writeEscapedCode(code)
+	} else if r.CodeBlockListings {
+		r.WriteString("\\begin{lstlisting}")
+		var language string
+		if 0 < len(node.CodeBlockInfo) {
+			language = strings.Fields(util.BytesToStr(node.CodeBlockInfo))[0]
+		}
+		if isListingsLanguage(language) {
+			r.WriteString("[language=" + language + "]")
+		}
+		r.WriteString("\n" + code + "\n\\end{lstlisting}")
+	} else {
+		r.WriteString("\\begin{verbatim}\n" + code + "\n\\end{verbatim}")
+	}
+	r.endBlock(node)
+	return ast.WalkSkipChildren
+}
+
+// writeEscapedCode 使用等宽字体逐行输出转义后的代码 code，保留空格和空行。
+func (r *LaTeXRenderer) writeEscapedCode(code string) {
+	r.WriteString("\\begin{flushleft}\\ttfamily\n")
+	for i, line := range strings.Split(code, "\n") {
+		if 0 < i {
+			r.WriteString("\\\\\n")
+		}
+		line = strings.NewReplacer(" ", "~", "\t", "~~~~").Replace(LaTeXEscape(line))
+		if "" == line {
+			line = "\\mbox{}"
+		}
+		r.WriteString(line)
+	}
+	r.WriteString("\n\\end{flushleft}")
+}
+
+// listingsLanguages 是 listings 宏包内置支持的部分语言，键为小写的代码块语言名称。
+var listingsLanguages = map[string]bool{
+	"bash": true, "c": true, "c++": true, "go": true, "html": true, "java": true, "python": true, "ruby": true,
+	"sql": true, "xml": true, "php": true, "perl": true, "lua": true, "haskell": true, "r": true, "tex": true,
+}
+
+func isListingsLanguage(language string) bool {
+	return listingsLanguages[strings.ToLower(language)]
+}
+
+var latexSectionCommands = []string{"", "section", "subsection", "subsubsection", "paragraph", "subparagraph", "subparagraph"}
+
+func (r *LaTeXRenderer) renderHeading(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.WriteString("\\" + latexSectionCommands[node.HeadingLevel] + "{")
+	} else {
+		r.WriteString("}\\label{" + latexLabel(r.HeadingID(node)) + "}")
+		r.blankLine()
+	}
+	return ast.WalkContinue
+}
+
+func (r *LaTeXRenderer) renderToC(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.WriteString("\\tableofcontents")
+		r.blankLine()
+	}
+	return ast.WalkContinue
+}
+
+func (r *LaTeXRenderer) renderThematicBreak(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.WriteString("\\begin{center}\\rule{0.5\\linewidth}{0.5pt}\\end{center}")
+		r.blankLine()
+	}
+	return ast.WalkContinue
+}
+
+func (r *LaTeXRenderer) renderHardBreak(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString("\\newline\n")
+	}
+	return ast.WalkContinue
+}
+
+func (r *LaTeXRenderer) renderSoftBreak(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if r.tableCell {
+			r.WriteByte(lex.ItemSpace)
+		} else {
+			r.WriteByte(lex.ItemNewline)
+		}
+	}
+	return ast.WalkContinue
+}
+
+func (r *LaTeXRenderer) renderBlockquote(node *ast.Node, entering bool) ast.WalkStatus {
+	r.Newline()
+	if entering {
+		r.WriteString("\\begin{quote}\n")
+	} else {
+		r.WriteString("\\end{quote}")
+		r.blankLine()
+	}
+	return ast.WalkContinue
+}
+
+func (r *LaTeXRenderer) renderAdmonition(node *ast.Node, entering bool) ast.WalkStatus {
+	r.renderBlockquote(node, entering)
+	if entering {
+		r.WriteString("\\textbf{" + LaTeXEscape(AdmonitionTitle(node)) + "}\n\n")
+	}
+	return ast.WalkContinue
+}
+
+func (r *LaTeXRenderer) renderDefinitionList(node *ast.Node, entering bool) ast.WalkStatus {
+	r.Newline()
+	if entering {
+		r.WriteString("\\begin{description}\n")
+	} else {
+		r.WriteString("\\end{description}")
+		r.blankLine()
+	}
+	return ast.WalkContinue
+}
+
+func (r *LaTeXRenderer) renderDefinitionTerm(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.WriteString("\\item[{")
+	} else {
+		r.WriteString("}]\n")
+	}
+	return ast.WalkContinue
+}
+
+func (r *LaTeXRenderer) renderDefinitionDescription(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering {
+		r.blankLine()
+	}
+	return ast.WalkContinue
+}
+
+func (r *LaTeXRenderer) renderList(node *ast.Node, entering bool) ast.WalkStatus {
+	ordered := 1 == node.ListData.Typ || (3 == node.ListData.Typ && 0 == node.ListData.BulletChar)
+	env := "itemize"
+	if ordered {
+		env = "enumerate"
+	}
+
+	r.Newline()
+	if entering {
+		r.WriteString("\\begin{" + env + "}\n")
+		if ordered {
+			r.enumDepth++
+			if start := node.ListData.Start; 1 < start && 4 >= r.enumDepth {
+				r.WriteString("\\setcounter{enum" + strings.Repeat("i", r.enumDepth) + "}{" + strconv.Itoa(start-1) + "}\n")
+			}
+		}
+	} else {
+		if ordered {
+			r.enumDepth--
+		}
+		r.WriteString("\\end{" + env + "}")
+		r.endBlock(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *LaTeXRenderer) renderListItem(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		r.WriteString("\\item")
+		if 3 == node.ListData.Typ {
+			if node.ListData.Checked {
+				r.WriteString("[$\\boxtimes$]")
+			} else {
+				r.WriteString("[$\\square$]")
+			}
+		}
+		r.WriteByte(lex.ItemSpace)
+	} else {
+		r.Newline()
+	}
+	return ast.WalkContinue
+}
+
+func (r *LaTeXRenderer) renderTable(node *ast.Node, entering bool) ast.WalkStatus {
+	env := "longtable"
+	if ast.NodeDocument != node.Parent.Type {
+		env = "tabular" // longtable 不能在列表、引述等环境中使用
+	}
+
+	r.Newline()
+	if entering {
+		spec := "@{}"
+		for _, align := range node.TableAligns {
+			switch align {
+			case 2:
+				spec += "c"
+			case 3:
+				spec += "r"
+			default:
+				spec += "l"
+			}
+		}
+		spec += "@{}"
+		r.WriteString("\\begin{" + env + "}{" + spec + "}\n\\toprule\n")
+	} else {
+		r.WriteString("\\bottomrule\n\\end{" + env + "}")
+		r.blankLine()
+	}
+	return ast.WalkContinue
+}
+
+func (r *LaTeXRenderer) renderTableHead(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering {
+		r.WriteString("\\midrule\n")
+		if ast.NodeDocument == node.Parent.Parent.Type {
+			r.WriteString("\\endhead\n")
+		}
+	}
+	return ast.WalkContinue
+}
+
+func (r *LaTeXRenderer) renderTableRow(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering {
+		r.WriteString(" \\\\\n")
+	}
+	return ast.WalkContinue
+}
+
+func (r *LaTeXRenderer) renderTableCell(node *ast.Node, entering bool) ast.WalkStatus {
+	r.tableCell = entering
+	if entering && nil != node.Previous {
+		r.WriteString(" & ")
+	}
+	return ast.WalkContinue
+}
+
+func (r *LaTeXRenderer) renderEmojiImg(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if alias := node.ChildByType(ast.NodeEmojiAlias); nil != alias {
+			r.WriteString(LaTeXEscape(util.BytesToStr(alias.Tokens)))
+		}
+	}
+	return ast.WalkSkipChildren
+}
+
+// linkDest 返回链接节点 node 的链接地址，启用 Sanitize 时将过滤 javascript: 地址。
+func (r *LaTeXRenderer) linkDest(node *ast.Node) string {
+	dest := node.ChildByType(ast.NodeLinkDest)
+	if nil == dest {
+		return ""
+	}
+	destTokens, _ := r.ResolveLink(node, dest.Tokens)
+	if 0 < len(destTokens) && nil == r.sanitizeURL(destTokens) {
+		return ""
+	}
+	return util.BytesToStr(r.LinkPath(destTokens))
+}
+
+// beginLink 输出链接开始部分，以 # 开头的地址作为文档内标签引用。
+func (r *LaTeXRenderer) beginLink(dest string) {
+	if strings.HasPrefix(dest, "#") {
+		label := dest[1:]
+		if unescaped, err := url.PathUnescape(label); nil == err {
+			label = unescaped
+		}
+		r.WriteString("\\hyperref[" + latexLabel(label) + "]{")
+	} else {
+		r.WriteString("\\href{" + latexEscapeURL(dest) + "}{")
+	}
+}
+
+func (r *LaTeXRenderer) renderLink(node *ast.Node, entering bool) ast.WalkStatus {
+	dest := r.linkDest(node)
+	if "" == dest {
+		return ast.WalkContinue
+	}
+
+	if nil == node.ChildByType(ast.NodeLinkText) {
+		if entering {
+			r.WriteString("\\url{" + latexEscapeURL(dest) + "}")
+		}
+		return ast.WalkContinue
+	}
+
+	if entering {
+		r.beginLink(dest)
+	} else {
+		r.WriteByte(lex.ItemCloseBrace)
+	}
+	return ast.WalkContinue
+}
+
+func (r *LaTeXRenderer) renderLinkText(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering && ast.NodeImage != node.Parent.Type {
+		r.WriteString(LaTeXEscape(util.BytesToStr(node.Tokens)))
+	}
+	return ast.WalkContinue
+}
+
+func (r *LaTeXRenderer) renderImage(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString("\\includegraphics{" + latexEscapeURL(r.linkDest(node)) + "}")
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *LaTeXRenderer) renderWikiLink(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering {
+		return ast.WalkSkipChildren
+	}
+
+	dest := r.WikiLinkDest(node)
+	text := LaTeXEscape(node.WikiLinkText())
+	if isWikiLinkImage(node) && "" != dest {
+		r.WriteString("\\includegraphics{" + latexEscapeURL(dest) + "}")
+	} else if "" != dest {
+		r.beginLink(dest)
+		r.WriteString(text + "}")
+	} else {
+		r.WriteString(text)
+	}
+	return ast.WalkSkipChildren
+}
+
+// latexTextMarkCommands 是 TextMark 类型到 LaTeX 命令的映射。
+var latexTextMarkCommands = map[string]string{
+	"strong": "textbf", "em": "emph", "s": "sout", "u": "uline", "sup": "textsuperscript", "sub": "textsubscript",
+	"code": "texttt", "kbd": "texttt",
+}
+
+func (r *LaTeXRenderer) renderTextMark(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering {
+		return ast.WalkContinue
+	}
+
+	if node.IsTextMarkType("inline-math") {
+		r.WriteString("$" + strings.TrimSpace(node.TextMarkInlineMathContent) + "$")
+		return ast.WalkContinue
+	}
+
+	var closes int
+	for _, typ := range strings.Split(node.TextMarkType, " ") {
+		if command := latexTextMarkCommands[typ]; "" != command {
+			r.WriteString("\\" + command + "{")
+			closes++
+		}
+	}
+	dest := r.ResolveLinkStr(node, node.TextMarkAHref)
+	if "" != dest && nil == r.sanitizeURL(util.StrToBytes(dest)) {
+		dest = ""
+	}
+	if node.IsTextMarkType("a") && "" != dest {
+		r.beginLink(util.BytesToStr(r.LinkPath(util.StrToBytes(dest))))
+		closes++
+	}
+	r.WriteString(LaTeXEscape(node.TextMarkTextContent))
+	r.WriteString(strings.Repeat("}", closes))
+	return ast.WalkContinue
+}
+
+func (r *LaTeXRenderer) renderFootnotesRef(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering {
+		return ast.WalkContinue
+	}
+
+	_, def := r.Tree.FindFootnotesDef(node.Tokens)
+	if nil == def {
+		r.WriteString(LaTeXEscape("[" + util.BytesToStr(node.Tokens) + "]"))
+		return ast.WalkContinue
+	}
+
+	if label, ok := r.footnoteLabels[def]; ok {
+		// 重复引用同一个脚注定义时引用第一次出现时的编号
+		r.WriteString("\\textsuperscript{\\ref{" + label + "}}")
+		return ast.WalkContinue
+	}
+
+	label := "fn:" + strconv.Itoa(len(r.footnoteLabels)+1)
+	r.footnoteLabels[def] = label
+
+	writer, lastOut, footnote := r.Writer, r.LastOut, r.footnote
+	r.Writer, r.LastOut, r.footnote = &bytes.Buffer{}, lex.ItemNewline, true
+	for child := def.FirstChild; nil != child; child = child.Next {
+		r.walk(child)
+	}
+	content := bytes.TrimSpace(r.Writer.Bytes())
+	r.Writer, r.LastOut, r.footnote = writer, lastOut, footnote
+
+	r.WriteString("\\footnote{")
+	r.Write(content)
+	r.WriteString("\\label{" + label + "}}")
+	return ast.WalkContinue
+}
+
+// latexSpecialChars 是 LaTeX 正文中需要转义的特殊字符。
+var latexSpecialChars = strings.NewReplacer(
+	"\\", "\\textbackslash{}",
+	"{", "\\{",
+	"}", "\\}",
+	"$", "\\$",
+	"&", "\\&",
+	"#", "\\#",
+	"%", "\\%",
+	"_", "\\_",
+	"~", "\\textasciitilde{}",
+	"^", "\\textasciicircum{}",
+	"<", "\\textless{}",
+	">", "\\textgreater{}",
+)
+
+// LaTeXEscape 转义 text 中的 LaTeX 特殊字符。
+func LaTeXEscape(text string) string {
+	return latexSpecialChars.Replace(text)
+}
+
+// latexURLChars 是 \href、\url 和 \includegraphics 参数中需要转义的字符。
+var latexURLChars = strings.NewReplacer(
+	"\\", "/",
+	"%", "\\%",
+	"#", "\\#",
+	"{", "\\%7B",
+	"}", "\\%7D",
+)
+
+func latexEscapeURL(url string) string {
+	return latexURLChars.Replace(url)
+}
+
+// latexLabel 将 id 转换为可以安全用于 \label 和 \hyperref 的标签，字母、数字和 -:./ 以外的字节编码为 +XX。
+func latexLabel(id string) string {
+	buf := &strings.Builder{}
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		if ('a' <= c && 'z' >= c) || ('A' <= c && 'Z' >= c) || ('0' <= c && '9' >= c) || 0 <= strings.IndexByte("-:./", c) {
+			buf.WriteByte(c)
+			continue
+		}
+		buf.WriteByte('+')
+		buf.WriteByte("0123456789ABCDEF"[c>>4])
+		buf.WriteByte("0123456789ABCDEF"[c&0xF])
+	}
+	return buf.String()
+}
@@ -4,9 +4,114 @@ import (
 	"bytes"
 	"strings"
 
+	"github.com/pafthang/md/ast"
+	"github.com/pafthang/md/html"
+	"github.com/pafthang/md/parse"
 	"github.com/pafthang/md/util"
 )
 
+// LinkKind 描述了传入 LinkResolver 的链接类型。
+type LinkKind int
+
+const (
+	LinkKindLink     LinkKind = iota // 链接 [foo](bar)、链接引用 [foo] 和维基链接 [[foo]]
+	LinkKindImage                    // 图片 ![foo](bar)
+	LinkKindAutolink                 // 自动链接 <https://foo> 和 GFM 扩展自动链接
+	LinkKindFootnote                 // 脚注引用 [^foo]，dest 为脚注定义的锚点，比如 #footnotes-def-1
+	LinkKindBlockRef                 // 块引用 ((id "text"))，dest 为块 ID
+)
+
+// LinkResolver 用于改写链接地址，kind 为链接类型，dest 为原始地址，tree 为链接所在的语法树。
+//
+// 返回改写后的地址 url，为空时保持原始地址不变；attrs 为需要额外渲染的属性，比如 {{"target", "_blank"}}，仅 HTML 类渲染器支持。
+// 改写后的地址仍然会按照 LinkBase 和 LinkPrefix 进行处理。
+type LinkResolver func(kind LinkKind, dest string, tree *parse.Tree) (url string, attrs [][]string)
+
+// ResolveLink 使用 Options.LinkResolver 改写节点 node 的链接地址 dest，返回改写后的地址和转义后的额外属性。
+//
+// node 可以是链接、图片、链接地址、脚注引用、块引用和超链接/块引用行级元素节点。
+func (r *BaseRenderer) ResolveLink(node *ast.Node, dest []byte) (ret []byte, attrs [][]string) {
+	ret = dest
+	if nil == r.Options.LinkResolver {
+		return
+	}
+
+	url, resolved := r.Options.LinkResolver(linkKind(node), util.BytesToStr(dest), r.Tree)
+	if "" != url {
+		ret = util.StrToBytes(url)
+	}
+	// LinkResolver 可能每次都返回同一个属性切片，转义时不能修改它，否则再次渲染时会重复转义
+	for _, attr := range resolved {
+		if 2 > len(attr) {
+			continue
+		}
+		attrs = append(attrs, []string{attr[0], util.BytesToStr(html.EscapeHTML(util.StrToBytes(attr[1])))})
+	}
+	return
+}
+
+// ResolveLinkStr 使用 Options.LinkResolver 改写节点 node 的链接地址 dest，忽略额外属性。
+func (r *BaseRenderer) ResolveLinkStr(node *ast.Node, dest string) string {
+	ret, _ := r.ResolveLink(node, util.StrToBytes(dest))
+	return util.BytesToStr(ret)
+}
+
+func linkKind(node *ast.Node) LinkKind {
+	if ast.NodeLinkDest == node.Type && nil != node.Parent {
+		node = node.Parent
+	}
+
+	switch node.Type {
+	case ast.NodeImage:
+		return LinkKindImage
+	case ast.NodeFootnotesRef:
+		return LinkKindFootnote
+	case ast.NodeBlockRef:
+		return LinkKindBlockRef
+	case ast.NodeLink:
+		if 2 == node.LinkType {
+			return LinkKindAutolink
+		}
+	case ast.NodeWikiLinkEmbed:
+		if isWikiLinkImage(node) {
+			return LinkKindImage
+		}
+	case ast.NodeTextMark:
+		if node.IsTextMarkType("block-ref") {
+			return LinkKindBlockRef
+		}
+	}
+	return LinkKindLink
+}
+
+// blockRefLink 在 LinkResolver 将块引用 node 改写为地址时使用 <a> 包裹块引用。
+func (r *BaseRenderer) blockRefLink(node *ast.Node, entering bool) {
+	refID := node.ChildByType(ast.NodeBlockRefID)
+	if nil == refID {
+		return
+	}
+
+	href, linkAttrs := r.ResolveLink(node, refID.Tokens)
+	if bytes.Equal(href, refID.Tokens) {
+		return
+	}
+
+	if entering {
+		href = r.LinkPath(r.sanitizeURL(href))
+		r.Tag("a", append([][]string{{"href", util.BytesToStr(html.EscapeHTML(href))}}, linkAttrs...), false)
+	} else {
+		r.Tag("/a", nil, false)
+	}
+}
+
+// linkAttrsStr 返回额外属性 attrs 的 HTML 字符串，用于直接拼接 HTML 标签的渲染器。
+func (r *BaseRenderer) linkAttrsStr(attrs [][]string) (ret string) {
+	for _, attr := range attrs {
+		ret += r.attrStr(attr[0], attr[1])
+	}
+	return
+}
+
 func (r *BaseRenderer) EncodeLinkSpace(dest string) string {
 	// Improve export of Markdown hyperlink spaces and markers https://github.com/siyuan-note/siyuan/issues/9792
 	return strings.ReplaceAll(dest, " ", "%20")
@@ -53,6 +53,7 @@ func NewProtyleExportDocxRenderer(tree *parse.Tree, options *Options) *ProtyleEx
 	ret.RendererFuncs[ast.NodeStrongU8eCloseMarker] = ret.renderStrongU8eCloseMarker
 	ret.RendererFuncs[ast.NodeBlockquote] = ret.renderBlockquote
 	ret.RendererFuncs[ast.NodeBlockquoteMarker] = ret.renderBlockquoteMarker
+	ret.RendererFuncs[ast.NodeAdmonition] = ret.renderAdmonition
 	ret.RendererFuncs[ast.NodeHeading] = ret.renderHeading
 	ret.RendererFuncs[ast.NodeHeadingC8hMarker] = ret.renderHeadingC8hMarker
 	ret.RendererFuncs[ast.NodeHeadingID] = ret.renderHeadingID
@@ -112,6 +113,8 @@ func NewProtyleExportDocxRenderer(tree *parse.Tree, options *Options) *ProtyleEx
 	ret.RendererFuncs[ast.NodeFileAnnotationRefID] = ret.renderFileAnnotationRefID
 	ret.RendererFuncs[ast.NodeFileAnnotationRefSpace] = ret.renderFileAnnotationRefSpace
 	ret.RendererFuncs[ast.NodeFileAnnotationRefText] = ret.renderFileAnnotationRefText
+	ret.RendererFuncs[ast.NodeWikiLink] = ret.renderWikiLink
+	ret.RendererFuncs[ast.NodeWikiLinkEmbed] = ret.renderWikiLinkEmbed
 	ret.RendererFuncs[ast.NodeMark] = ret.renderMark
 	ret.RendererFuncs[ast.NodeMark1OpenMarker] = ret.renderMark1OpenMarker
 	ret.RendererFuncs[ast.NodeMark1CloseMarker] = ret.renderMark1CloseMarker
@@ -213,11 +216,12 @@ func (r *ProtyleExportDocxRenderer) renderHTMLTag0(node *ast.Node, currentTextMa
 	switch currentTextMarkType {
 	case "a":
 		if entering {
-			attrs := [][]string{{"href", node.TextMarkAHref}}
+			href, linkAttrs := r.ResolveLink(node, []byte(node.TextMarkAHref))
+			attrs := [][]string{{"href", string(href)}}
 			if "" != node.TextMarkATitle {
 				attrs = append(attrs, []string{"title", node.TextMarkATitle})
 			}
-			r.Tag("a", attrs, false)
+			r.Tag("a", append(attrs, linkAttrs...), false)
 
 		} else {
 			r.WriteString("</a>")
@@ -398,9 +402,7 @@ func (r *ProtyleExportDocxRenderer) renderVideo(node *ast.Node, entering bool) a
 	if entering {
 		r.Tag("div", [][]string{{"class", "iframe"}}, false)
 		tokens := node.Tokens
-		if r.Options.Sanitize {
-			tokens = sanitize(tokens)
-		}
+		tokens = r.sanitize(tokens)
 		tokens = r.tagSrcPath(tokens)
 		r.Write(tokens)
 		r.Tag("/div", nil, false)
@@ -412,9 +414,7 @@ func (r *ProtyleExportDocxRenderer) renderAudio(node *ast.Node, entering bool) a
 	if entering {
 		r.Tag("div", [][]string{{"class", "iframe"}}, false)
 		tokens := node.Tokens
-		if r.Options.Sanitize {
-			tokens = sanitize(tokens)
-		}
+		tokens = r.sanitize(tokens)
 		tokens = r.tagSrcPath(tokens)
 		r.Write(tokens)
 		r.Tag("/div", nil, false)
@@ -426,9 +426,7 @@ func (r *ProtyleExportDocxRenderer) renderIFrame(node *ast.Node, entering bool)
 	if entering {
 		r.Tag("div", [][]string{{"class", "iframe"}}, false)
 		tokens := node.Tokens
-		if r.Options.Sanitize {
-			tokens = sanitize(tokens)
-		}
+		tokens = r.sanitize(tokens)
 		tokens = r.tagSrcPath(tokens)
 		r.Write(tokens)
 		r.Tag("/div", nil, false)
@@ -440,9 +438,7 @@ func (r *ProtyleExportDocxRenderer) renderWidget(node *ast.Node, entering bool)
 	if entering {
 		r.Tag("div", [][]string{{"class", "iframe"}}, false)
 		tokens := node.Tokens
-		if r.Options.Sanitize {
-			tokens = sanitize(tokens)
-		}
+		tokens = r.sanitize(tokens)
 		tokens = r.tagSrcPath(tokens)
 		r.Write(tokens)
 		r.Tag("/div", nil, false)
@@ -637,6 +633,7 @@ func (r *ProtyleExportDocxRenderer) renderBlockQueryEmbedScript(node *ast.Node,
 }
 
 func (r *ProtyleExportDocxRenderer) renderBlockRef(node *ast.Node, entering bool) ast.WalkStatus {
+	r.blockRefLink(node, entering)
 	return ast.WalkContinue
 }
 
@@ -690,6 +687,30 @@ func (r *ProtyleExportDocxRenderer) renderFileAnnotationRefText(node *ast.Node,
 	return ast.WalkContinue
 }
 
+func (r *ProtyleExportDocxRenderer) renderWikiLink(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		attrs := [][]string{{"href", html.EscapeString(r.WikiLinkDest(node))}, {"class", "wikilink"}}
+		r.Tag("a", attrs, false)
+		r.WriteString(html.EscapeString(node.WikiLinkText()))
+		r.Tag("/a", nil, false)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *ProtyleExportDocxRenderer) renderWikiLinkEmbed(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		dest, text := html.EscapeString(r.WikiLinkDest(node)), html.EscapeString(node.WikiLinkText())
+		if isWikiLinkImage(node) {
+			r.Tag("img", [][]string{{"src", dest}, {"alt", text}, {"class", "wikilink-embed"}}, true)
+		} else {
+			r.Tag("a", [][]string{{"href", dest}, {"class", "wikilink wikilink-embed"}}, false)
+			r.WriteString(text)
+			r.Tag("/a", nil, false)
+		}
+	}
+	return ast.WalkSkipChildren
+}
+
 func (r *ProtyleExportDocxRenderer) renderYamlFrontMatterCloseMarker(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
 		r.WriteString("</code></pre>")
@@ -746,7 +767,9 @@ func (r *ProtyleExportDocxRenderer) renderFootnotesRef(node *ast.Node, entering
 		idx, _ := r.Tree.FindFootnotesDef(node.Tokens)
 		idxStr := strconv.Itoa(idx)
 		r.Tag("sup", [][]string{{"class", "footnotes-ref"}, {"id", "footnotes-ref-" + node.FootnotesRefId}}, false)
-		r.Tag("a", [][]string{{"href", r.Options.LinkBase + "#footnotes-def-" + idxStr}}, false)
+		href, linkAttrs := r.ResolveLink(node, []byte("#footnotes-def-"+idxStr))
+		href = r.sanitizeURL(href)
+		r.Tag("a", append([][]string{{"href", r.Options.LinkBase + util.BytesToStr(html.EscapeHTML(href))}}, linkAttrs...), false)
 		r.WriteString(idxStr)
 		r.Tag("/a", nil, false)
 		r.Tag("/sup", nil, false)
@@ -1088,14 +1111,14 @@ func (r *ProtyleExportDocxRenderer) renderImage(node *ast.Node, entering bool) a
 			}
 			r.Tag("span", attrs, false)
 			r.WriteString("<img src=\"")
-			destTokens := node.ChildByType(ast.NodeLinkDest).Tokens
+			destTokens, linkAttrs := r.ResolveLink(node, node.ChildByType(ast.NodeLinkDest).Tokens)
 			destTokens = r.LinkPath(destTokens)
 			if "" != r.Options.ImageLazyLoading {
 				r.Write(html.EscapeHTML(util.StrToBytes(r.Options.ImageLazyLoading)))
 				r.WriteString("\" data-src=\"")
 			}
 			r.Write(html.EscapeHTML(destTokens))
-			r.WriteString("\" alt=\"")
+			r.WriteString("\"" + r.linkAttrsStr(linkAttrs) + " alt=\"")
 		}
 		r.DisableTags++
 		return ast.WalkContinue
@@ -1127,9 +1150,7 @@ func (r *ProtyleExportDocxRenderer) renderImage(node *ast.Node, entering bool) a
 		buf := r.Writer.Bytes()
 		idx := bytes.LastIndex(buf, []byte("<img src="))
 		imgBuf := buf[idx:]
-		if r.Options.Sanitize {
-			imgBuf = sanitize(imgBuf)
-		}
+		imgBuf = r.sanitize(imgBuf)
 		r.Writer.Truncate(idx)
 		r.Writer.Write(imgBuf)
 	}
@@ -1141,19 +1162,14 @@ func (r *ProtyleExportDocxRenderer) renderLink(node *ast.Node, entering bool) as
 		r.LinkTextAutoSpacePrevious(node)
 
 		dest := node.ChildByType(ast.NodeLinkDest)
-		destTokens := dest.Tokens
-		if r.Options.Sanitize {
-			tokens := bytes.TrimSpace(destTokens)
-			tokens = bytes.ToLower(tokens)
-			if bytes.HasPrefix(tokens, []byte("javascript:")) {
-				destTokens = nil
-			}
-		}
+		destTokens, linkAttrs := r.ResolveLink(node, dest.Tokens)
+		destTokens = r.sanitizeURL(destTokens)
 		destTokens = r.LinkPath(destTokens)
 		attrs := [][]string{{"href", util.BytesToStr(html.EscapeHTML(destTokens))}}
 		if title := node.ChildByType(ast.NodeLinkTitle); nil != title && nil != title.Tokens {
 			attrs = append(attrs, []string{"title", util.BytesToStr(html.EscapeHTML(title.Tokens))})
 		}
+		attrs = append(attrs, linkAttrs...)
 		r.Tag("a", attrs, false)
 	} else {
 		r.Tag("/a", nil, false)
@@ -1167,9 +1183,7 @@ func (r *ProtyleExportDocxRenderer) renderHTML(node *ast.Node, entering bool) as
 	if entering {
 		r.Newline()
 		tokens := node.Tokens
-		if r.Options.Sanitize {
-			tokens = sanitize(tokens)
-		}
+		tokens = r.sanitize(tokens)
 		tokens = r.tagSrcPath(tokens)
 		r.Write(tokens)
 		r.Newline()
@@ -1180,9 +1194,7 @@ func (r *ProtyleExportDocxRenderer) renderHTML(node *ast.Node, entering bool) as
 func (r *ProtyleExportDocxRenderer) renderInlineHTML(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
 		tokens := node.Tokens
-		if r.Options.Sanitize {
-			tokens = sanitize(tokens)
-		}
+		tokens = r.sanitize(tokens)
 		r.Write(tokens)
 	}
 	return ast.WalkContinue
@@ -1356,12 +1368,31 @@ func (r *ProtyleExportDocxRenderer) renderBlockquoteMarker(node *ast.Node, enter
 	return ast.WalkContinue
 }
 
+func (r *ProtyleExportDocxRenderer) renderAdmonition(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		attrs := [][]string{{"class", "admonition admonition-" + html.EscapeString(node.AdmonitionKind)}}
+		attrs = append(attrs, node.KramdownIAL...)
+		r.Tag("div", attrs, false)
+		r.Newline()
+		r.Tag("p", [][]string{{"class", "admonition-title"}}, false)
+		r.WriteString(html.EscapeString(AdmonitionTitle(node)))
+		r.Tag("/p", nil, false)
+		r.Newline()
+	} else {
+		r.Newline()
+		r.Tag("/div", nil, false)
+		r.Newline()
+	}
+	return ast.WalkContinue
+}
+
 func (r *ProtyleExportDocxRenderer) renderHeading(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
 		r.Newline()
 		level := headingLevel[node.HeadingLevel : node.HeadingLevel+1]
 		r.WriteString("<h" + level)
-		id := HeadingID(node)
+		id := r.HeadingID(node)
 		if r.Options.ToC || r.Options.HeadingID || r.Options.KramdownBlockIAL {
 			r.WriteString(" id=\"" + id + "\"")
 			if r.Options.KramdownBlockIAL {
@@ -1371,7 +1402,7 @@ func (r *ProtyleExportDocxRenderer) renderHeading(node *ast.Node, entering bool)
 				if 1 < len(node.KramdownIAL) {
 					exceptID := node.KramdownIAL[1:]
 					for _, attr := range exceptID {
-						r.WriteString(" " + attr[0] + "=\"" + attr[1] + "\"")
+						r.WriteString(r.attrStr(attr[0], attr[1]))
 					}
 				}
 			}
@@ -1379,7 +1410,7 @@ func (r *ProtyleExportDocxRenderer) renderHeading(node *ast.Node, entering bool)
 		r.WriteString(">")
 	} else {
 		if r.Options.HeadingAnchor {
-			id := HeadingID(node)
+			id := r.HeadingID(node)
 			r.Tag("a", [][]string{{"id", "editorAnchor-" + id}, {"class", "editor-anchor"}, {"href", "#" + id}}, false)
 			r.WriteString(`<svg viewBox="0 0 16 16" version="1.1" width="16" height="16"><path fill-rule="evenodd" d="M4 9h1v1H4c-1.5 0-3-1.69-3-3.5S2.55 3 4 3h4c1.45 0 3 1.69 3 3.5 0 1.41-.91 2.72-2 3.25V8.59c.58-.45 1-1.27 1-2.09C10 5.22 8.98 4 8 4H4c-.98 0-2 1.22-2 2.5S3 9 4 9zm9-3h-1v1h1c1 0 2 1.22 2 2.5S13.98 12 13 12H9c-.98 0-2-1.22-2-2.5 0-.83.42-1.64 1-2.09V6.25c-1.09.53-2 1.84-2 3.25C6 11.31 7.55 13 9 13h4c1.45 0 3-1.69 3-3.5S14.5 6 13 6z"></path></svg>`)
 			r.Tag("/a", nil, false)
@@ -54,6 +54,7 @@ func NewProtyleExportMdRenderer(tree *parse.Tree, options *Options) *ProtyleExpo
 	ret.RendererFuncs[ast.NodeStrongU8eCloseMarker] = ret.renderStrongU8eCloseMarker
 	ret.RendererFuncs[ast.NodeBlockquote] = ret.renderBlockquote
 	ret.RendererFuncs[ast.NodeBlockquoteMarker] = ret.renderBlockquoteMarker
+	ret.RendererFuncs[ast.NodeAdmonition] = ret.renderAdmonition
 	ret.RendererFuncs[ast.NodeHeading] = ret.renderHeading
 	ret.RendererFuncs[ast.NodeHeadingC8hMarker] = ret.renderHeadingC8hMarker
 	ret.RendererFuncs[ast.NodeHeadingID] = ret.renderHeadingID
@@ -113,6 +114,8 @@ func NewProtyleExportMdRenderer(tree *parse.Tree, options *Options) *ProtyleExpo
 	ret.RendererFuncs[ast.NodeFileAnnotationRefID] = ret.renderFileAnnotationRefID
 	ret.RendererFuncs[ast.NodeFileAnnotationRefSpace] = ret.renderFileAnnotationRefSpace
 	ret.RendererFuncs[ast.NodeFileAnnotationRefText] = ret.renderFileAnnotationRefText
+	ret.RendererFuncs[ast.NodeWikiLink] = ret.renderWikiLink
+	ret.RendererFuncs[ast.NodeWikiLinkEmbed] = ret.renderWikiLinkEmbed
 	ret.RendererFuncs[ast.NodeMark] = ret.renderMark
 	ret.RendererFuncs[ast.NodeMark1OpenMarker] = ret.renderMark1OpenMarker
 	ret.RendererFuncs[ast.NodeMark1CloseMarker] = ret.renderMark1CloseMarker
@@ -280,7 +283,7 @@ func (r *ProtyleExportMdRenderer) renderMdMarker(node *ast.Node, entering bool)
 
 			switch typ {
 			case "a":
-				href := node.TextMarkAHref
+				href := r.ResolveLinkStr(node, node.TextMarkAHref)
 				href = string(r.LinkPath([]byte(href)))
 				href = html.UnescapeHTMLStr(href)
 				href = r.EncodeLinkSpace(href)
@@ -328,7 +331,7 @@ func (r *ProtyleExportMdRenderer) renderMdMarker(node *ast.Node, entering bool)
 		} else {
 			switch typ {
 			case "a":
-				href := node.TextMarkAHref
+				href := r.ResolveLinkStr(node, node.TextMarkAHref)
 				href = string(r.LinkPath([]byte(href)))
 				href = html.UnescapeHTMLStr(href)
 				href = r.EncodeLinkSpace(href)
@@ -372,7 +375,7 @@ func reverse(ss []string) {
 func (r *ProtyleExportMdRenderer) renderMdMarker0(node *ast.Node, currentTextmarkType string, entering bool) (ret string) {
 	switch currentTextmarkType {
 	case "a":
-		href := node.TextMarkAHref
+		href := r.ResolveLinkStr(node, node.TextMarkAHref)
 		href = string(r.LinkPath([]byte(href)))
 		href = html.UnescapeHTMLStr(href)
 		href = r.EncodeLinkSpace(href)
@@ -878,6 +881,24 @@ func (r *ProtyleExportMdRenderer) renderFileAnnotationRefText(node *ast.Node, en
 	return ast.WalkContinue
 }
 
+func (r *ProtyleExportMdRenderer) renderWikiLink(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		// 导出时将维基链接转换为标准 Markdown 链接
+		r.WriteString(r.wikiLinkStdMarkdown(node))
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *ProtyleExportMdRenderer) renderWikiLinkEmbed(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if isWikiLinkImage(node) {
+			r.WriteByte(lex.ItemBang)
+		}
+		r.WriteString(r.wikiLinkStdMarkdown(node))
+	}
+	return ast.WalkSkipChildren
+}
+
 func (r *ProtyleExportMdRenderer) renderYamlFrontMatterCloseMarker(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
 		r.Write(parse.YamlFrontMatterMarker)
@@ -1189,7 +1210,7 @@ func (r *ProtyleExportMdRenderer) renderLinkTitle(node *ast.Node, entering bool)
 
 func (r *ProtyleExportMdRenderer) renderLinkDest(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
-		tokens := node.Tokens
+		tokens, _ := r.ResolveLink(node, node.Tokens)
 		tokens = r.LinkPath(tokens)
 		tokens = []byte(r.EncodeLinkSpace(string(tokens)))
 		r.Write(tokens)
@@ -1772,6 +1793,38 @@ func (r *ProtyleExportMdRenderer) renderBlockquoteMarker(node *ast.Node, enterin
 	return ast.WalkContinue
 }
 
+func (r *ProtyleExportMdRenderer) renderAdmonition(node *ast.Node, entering bool) ast.WalkStatus {
+	if 0 == node.AdmonitionFenceLen {
+		// GFM 提示按块引用渲染，首行为 [!NOTE]
+		ret := r.renderBlockquote(node, entering)
+		if entering {
+			r.WriteString(admonitionOpenMarker(node))
+			r.WriteByte(lex.ItemNewline)
+		}
+		return ret
+	}
+
+	if entering {
+		r.Newline()
+		r.WriteString(admonitionOpenMarker(node))
+		r.WriteByte(lex.ItemNewline)
+	} else {
+		buf := bytes.TrimRight(r.Writer.Bytes(), "\n")
+		r.Writer.Reset()
+		r.Write(buf)
+		r.WriteByte(lex.ItemNewline)
+		r.WriteString(strings.Repeat(":", node.AdmonitionFenceLen))
+		if !node.ParentIs(ast.NodeTableCell) {
+			if r.withoutKramdownBlockIAL(node) {
+				r.WriteString("\n\n")
+			} else {
+				r.WriteByte(lex.ItemNewline)
+			}
+		}
+	}
+	return ast.WalkContinue
+}
+
 func (r *ProtyleExportMdRenderer) renderHeading(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
 		if !node.HeadingSetext {
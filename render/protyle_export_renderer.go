@@ -53,6 +53,7 @@ func NewProtyleExportRenderer(tree *parse.Tree, options *Options) *ProtyleExport
 	ret.RendererFuncs[ast.NodeStrongU8eCloseMarker] = ret.renderStrongU8eCloseMarker
 	ret.RendererFuncs[ast.NodeBlockquote] = ret.renderBlockquote
 	ret.RendererFuncs[ast.NodeBlockquoteMarker] = ret.renderBlockquoteMarker
+	ret.RendererFuncs[ast.NodeAdmonition] = ret.renderAdmonition
 	ret.RendererFuncs[ast.NodeHeading] = ret.renderHeading
 	ret.RendererFuncs[ast.NodeHeadingC8hMarker] = ret.renderHeadingC8hMarker
 	ret.RendererFuncs[ast.NodeHeadingID] = ret.renderHeadingID
@@ -112,6 +113,8 @@ func NewProtyleExportRenderer(tree *parse.Tree, options *Options) *ProtyleExport
 	ret.RendererFuncs[ast.NodeFileAnnotationRefID] = ret.renderFileAnnotationRefID
 	ret.RendererFuncs[ast.NodeFileAnnotationRefSpace] = ret.renderFileAnnotationRefSpace
 	ret.RendererFuncs[ast.NodeFileAnnotationRefText] = ret.renderFileAnnotationRefText
+	ret.RendererFuncs[ast.NodeWikiLink] = ret.renderWikiLink
+	ret.RendererFuncs[ast.NodeWikiLinkEmbed] = ret.renderWikiLinkEmbed
 	ret.RendererFuncs[ast.NodeMark] = ret.renderMark
 	ret.RendererFuncs[ast.NodeMark1OpenMarker] = ret.renderMark1OpenMarker
 	ret.RendererFuncs[ast.NodeMark1CloseMarker] = ret.renderMark1CloseMarker
@@ -305,9 +308,7 @@ func (r *ProtyleExportRenderer) renderVideo(node *ast.Node, entering bool) ast.W
 		r.Tag("div", attrs, false)
 		r.Tag("div", [][]string{{"class", "iframe-content"}}, false)
 		tokens := bytes.ReplaceAll(node.Tokens, editor.CaretTokens, nil)
-		if r.Options.Sanitize {
-			tokens = sanitize(tokens)
-		}
+		tokens = r.sanitize(tokens)
 		dataSrc := r.tagSrc(tokens)
 		src := r.LinkPath(dataSrc)
 		tokens = r.replaceSrc(tokens, src, dataSrc)
@@ -330,9 +331,7 @@ func (r *ProtyleExportRenderer) renderAudio(node *ast.Node, entering bool) ast.W
 		r.Tag("div", attrs, false)
 		r.Tag("div", [][]string{{"class", "iframe-content"}}, false)
 		tokens := bytes.ReplaceAll(node.Tokens, editor.CaretTokens, nil)
-		if r.Options.Sanitize {
-			tokens = sanitize(tokens)
-		}
+		tokens = r.sanitize(tokens)
 		dataSrc := r.tagSrc(tokens)
 		src := r.LinkPath(dataSrc)
 		tokens = r.replaceSrc(tokens, src, dataSrc)
@@ -354,9 +353,7 @@ func (r *ProtyleExportRenderer) renderWidget(node *ast.Node, entering bool) ast.
 		r.Tag("div", attrs, false)
 		r.Tag("div", [][]string{{"class", "iframe-content"}}, false)
 		tokens := bytes.ReplaceAll(node.Tokens, editor.CaretTokens, nil)
-		if r.Options.Sanitize {
-			tokens = sanitize(tokens)
-		}
+		tokens = r.sanitize(tokens)
 		dataSrc := r.tagSrc(tokens)
 		src := r.LinkPath(dataSrc)
 		tokens = r.replaceSrc(tokens, src, dataSrc)
@@ -378,9 +375,7 @@ func (r *ProtyleExportRenderer) renderIFrame(node *ast.Node, entering bool) ast.
 		r.Tag("div", attrs, false)
 		r.Tag("div", [][]string{{"class", "iframe-content"}}, false)
 		tokens := bytes.ReplaceAll(node.Tokens, editor.CaretTokens, nil)
-		if r.Options.Sanitize {
-			tokens = sanitize(tokens)
-		}
+		tokens = r.sanitize(tokens)
 		dataSrc := r.tagSrc(tokens)
 		src := r.LinkPath(dataSrc)
 		tokens = r.replaceSrc(tokens, src, dataSrc)
@@ -484,6 +479,30 @@ func (r *ProtyleExportRenderer) renderFileAnnotationRefText(node *ast.Node, ente
 	return ast.WalkContinue
 }
 
+func (r *ProtyleExportRenderer) renderWikiLink(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		attrs := [][]string{{"target", "_blank"}, {"href", html.EscapeString(r.WikiLinkDest(node))}, {"class", "wikilink"}}
+		r.Tag("a", attrs, false)
+		r.WriteString(html.EscapeString(node.WikiLinkText()))
+		r.Tag("/a", nil, false)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *ProtyleExportRenderer) renderWikiLinkEmbed(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		dest, text := html.EscapeString(r.WikiLinkDest(node)), html.EscapeString(node.WikiLinkText())
+		if isWikiLinkImage(node) {
+			r.Tag("img", [][]string{{"src", dest}, {"alt", text}, {"class", "wikilink-embed"}}, true)
+		} else {
+			r.Tag("a", [][]string{{"target", "_blank"}, {"href", dest}, {"class", "wikilink wikilink-embed"}}, false)
+			r.WriteString(text)
+			r.Tag("/a", nil, false)
+		}
+	}
+	return ast.WalkSkipChildren
+}
+
 func (r *ProtyleExportRenderer) renderGitConflictCloseMarker(node *ast.Node, entering bool) ast.WalkStatus {
 	return ast.WalkContinue
 }
@@ -1224,10 +1243,8 @@ func (r *ProtyleExportRenderer) renderImage(node *ast.Node, entering bool) ast.W
 		r.WriteString("<span class=\"protyle-icon protyle-icon--only\"><svg class=\"svg\"><use xlink:href=\"#iconMore\"></use></svg></span>")
 		r.Tag("/span", nil, false)
 	} else {
-		destTokens := node.ChildByType(ast.NodeLinkDest).Tokens
-		if r.Options.Sanitize {
-			destTokens = sanitize(destTokens)
-		}
+		destTokens, _ := r.ResolveLink(node, node.ChildByType(ast.NodeLinkDest).Tokens)
+		destTokens = r.sanitize(destTokens)
 		destTokens = bytes.ReplaceAll(destTokens, editor.CaretTokens, nil)
 		dataSrcTokens := destTokens
 		dataSrc := util.BytesToStr(dataSrcTokens)
@@ -1253,9 +1270,7 @@ func (r *ProtyleExportRenderer) renderImage(node *ast.Node, entering bool) ast.W
 		buf := r.Writer.Bytes()
 		idx := bytes.LastIndex(buf, []byte("<img src="))
 		imgBuf := buf[idx:]
-		if r.Options.Sanitize {
-			imgBuf = sanitize(imgBuf)
-		}
+		imgBuf = r.sanitize(imgBuf)
 		imgBuf = r.tagSrcPath(imgBuf)
 		r.Writer.Truncate(idx)
 		r.Writer.Write(imgBuf)
@@ -1283,10 +1298,8 @@ func (r *ProtyleExportRenderer) renderImage(node *ast.Node, entering bool) ast.W
 func (r *ProtyleExportRenderer) renderLink(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
 		dest := node.ChildByType(ast.NodeLinkDest)
-		destTokens := dest.Tokens
-		if r.Options.Sanitize {
-			destTokens = sanitize(destTokens)
-		}
+		destTokens, linkAttrs := r.ResolveLink(node, dest.Tokens)
+		destTokens = r.sanitize(destTokens)
 
 		destTokens = r.LinkPath(destTokens)
 
@@ -1300,6 +1313,7 @@ func (r *ProtyleExportRenderer) renderLink(node *ast.Node, entering bool) ast.Wa
 		if title := node.ChildByType(ast.NodeLinkTitle); nil != title && nil != title.Tokens {
 			attrs = append(attrs, []string{"data-title", r.escapeRefText(string(title.Tokens))})
 		}
+		attrs = append(attrs, linkAttrs...)
 		r.Tag("a", attrs, false)
 	} else {
 		r.Tag("/a", nil, false)
@@ -1311,9 +1325,7 @@ func (r *ProtyleExportRenderer) renderHTML(node *ast.Node, entering bool) ast.Wa
 	if entering {
 		r.Newline()
 		tokens := node.Tokens
-		if r.Options.Sanitize {
-			tokens = sanitize(tokens)
-		}
+		tokens = r.sanitize(tokens)
 		tokens = r.tagSrcPath(tokens)
 		r.Write(tokens)
 		r.Newline()
@@ -1324,9 +1336,7 @@ func (r *ProtyleExportRenderer) renderHTML(node *ast.Node, entering bool) ast.Wa
 func (r *ProtyleExportRenderer) renderInlineHTML(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
 		tokens := node.Tokens
-		if r.Options.Sanitize {
-			tokens = sanitize(tokens)
-		}
+		tokens = r.sanitize(tokens)
 		r.Write(tokens)
 	}
 	return ast.WalkContinue
@@ -1506,6 +1516,25 @@ func (r *ProtyleExportRenderer) renderBlockquoteMarker(node *ast.Node, entering
 	return ast.WalkContinue
 }
 
+func (r *ProtyleExportRenderer) renderAdmonition(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		var attrs [][]string
+		r.blockNodeAttrs(node, &attrs, "admonition admonition-"+html.EscapeString(node.AdmonitionKind))
+		attrs = append(attrs, []string{"data-kind", html.EscapeString(node.AdmonitionKind)})
+		if "" != node.AdmonitionTitle {
+			attrs = append(attrs, []string{"data-title", html.EscapeString(node.AdmonitionTitle)})
+		}
+		if 0 < node.AdmonitionFenceLen {
+			attrs = append(attrs, []string{"data-fence-len", strconv.Itoa(node.AdmonitionFenceLen)})
+		}
+		r.Tag("div", attrs, false)
+	} else {
+		r.renderIAL(node)
+		r.Tag("/div", nil, false)
+	}
+	return ast.WalkContinue
+}
+
 func (r *ProtyleExportRenderer) renderHeading(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
 		var attrs [][]string
@@ -1661,7 +1690,7 @@ func (r *ProtyleExportRenderer) nodeClass(node *ast.Node, attrs *[][]string, cla
 }
 
 func (r *ProtyleExportRenderer) nodeDataType(node *ast.Node, attrs *[][]string) {
-	*attrs = append(*attrs, []string{"data-type", node.Type.String()})
+	*attrs = append(*attrs, []string{"data-type", r.nodeTypeName(node)})
 }
 
 func (r *ProtyleExportRenderer) nodeID(node *ast.Node, attrs *[][]string) {
@@ -1740,7 +1769,7 @@ func (r *ProtyleExportRenderer) renderTextMarkAttrs(node *ast.Node) (attrs [][]s
 			attrs = append(attrs, []string{"data-subtype", node.TextMarkBlockRefSubtype})
 			attrs = append(attrs, []string{"data-id", node.TextMarkBlockRefID})
 		} else if "a" == typ {
-			href := node.TextMarkAHref
+			href := r.ResolveLinkStr(node, node.TextMarkAHref)
 			href = string(r.LinkPath([]byte(href)))
 
 			attrs = append(attrs, []string{"data-href", href})
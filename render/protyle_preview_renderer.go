@@ -53,6 +53,7 @@ func NewProtylePreviewRenderer(tree *parse.Tree, options *Options) *ProtylePrevi
 	ret.RendererFuncs[ast.NodeStrongU8eCloseMarker] = ret.renderStrongU8eCloseMarker
 	ret.RendererFuncs[ast.NodeBlockquote] = ret.renderBlockquote
 	ret.RendererFuncs[ast.NodeBlockquoteMarker] = ret.renderBlockquoteMarker
+	ret.RendererFuncs[ast.NodeAdmonition] = ret.renderAdmonition
 	ret.RendererFuncs[ast.NodeHeading] = ret.renderHeading
 	ret.RendererFuncs[ast.NodeHeadingC8hMarker] = ret.renderHeadingC8hMarker
 	ret.RendererFuncs[ast.NodeHeadingID] = ret.renderHeadingID
@@ -112,6 +113,8 @@ func NewProtylePreviewRenderer(tree *parse.Tree, options *Options) *ProtylePrevi
 	ret.RendererFuncs[ast.NodeFileAnnotationRefID] = ret.renderFileAnnotationRefID
 	ret.RendererFuncs[ast.NodeFileAnnotationRefSpace] = ret.renderFileAnnotationRefSpace
 	ret.RendererFuncs[ast.NodeFileAnnotationRefText] = ret.renderFileAnnotationRefText
+	ret.RendererFuncs[ast.NodeWikiLink] = ret.renderWikiLink
+	ret.RendererFuncs[ast.NodeWikiLinkEmbed] = ret.renderWikiLinkEmbed
 	ret.RendererFuncs[ast.NodeMark] = ret.renderMark
 	ret.RendererFuncs[ast.NodeMark1OpenMarker] = ret.renderMark1OpenMarker
 	ret.RendererFuncs[ast.NodeMark1CloseMarker] = ret.renderMark1CloseMarker
@@ -279,9 +282,7 @@ func (r *ProtylePreviewRenderer) renderVideo(node *ast.Node, entering bool) ast.
 	if entering {
 		r.Tag("div", [][]string{{"class", "iframe"}}, false)
 		tokens := node.Tokens
-		if r.Options.Sanitize {
-			tokens = sanitize(tokens)
-		}
+		tokens = r.sanitize(tokens)
 		tokens = r.tagSrcPath(tokens)
 		r.Write(tokens)
 		r.Tag("/div", nil, false)
@@ -293,9 +294,7 @@ func (r *ProtylePreviewRenderer) renderAudio(node *ast.Node, entering bool) ast.
 	if entering {
 		r.Tag("div", [][]string{{"class", "iframe"}}, false)
 		tokens := node.Tokens
-		if r.Options.Sanitize {
-			tokens = sanitize(tokens)
-		}
+		tokens = r.sanitize(tokens)
 		tokens = r.tagSrcPath(tokens)
 		r.Write(tokens)
 		r.Tag("/div", nil, false)
@@ -307,9 +306,7 @@ func (r *ProtylePreviewRenderer) renderIFrame(node *ast.Node, entering bool) ast
 	if entering {
 		r.Tag("div", [][]string{{"class", "iframe"}}, false)
 		tokens := node.Tokens
-		if r.Options.Sanitize {
-			tokens = sanitize(tokens)
-		}
+		tokens = r.sanitize(tokens)
 		tokens = r.tagSrcPath(tokens)
 		r.Write(tokens)
 		r.Tag("/div", nil, false)
@@ -321,9 +318,7 @@ func (r *ProtylePreviewRenderer) renderWidget(node *ast.Node, entering bool) ast
 	if entering {
 		r.Tag("div", [][]string{{"class", "iframe"}}, false)
 		tokens := node.Tokens
-		if r.Options.Sanitize {
-			tokens = sanitize(tokens)
-		}
+		tokens = r.sanitize(tokens)
 		tokens = r.tagSrcPath(tokens)
 		r.Write(tokens)
 		r.Tag("/div", nil, false)
@@ -518,6 +513,7 @@ func (r *ProtylePreviewRenderer) renderBlockQueryEmbedScript(node *ast.Node, ent
 }
 
 func (r *ProtylePreviewRenderer) renderBlockRef(node *ast.Node, entering bool) ast.WalkStatus {
+	r.blockRefLink(node, entering)
 	return ast.WalkContinue
 }
 
@@ -571,6 +567,30 @@ func (r *ProtylePreviewRenderer) renderFileAnnotationRefText(node *ast.Node, ent
 	return ast.WalkContinue
 }
 
+func (r *ProtylePreviewRenderer) renderWikiLink(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		attrs := [][]string{{"href", html.EscapeString(r.WikiLinkDest(node))}, {"class", "wikilink"}}
+		r.Tag("a", attrs, false)
+		r.WriteString(html.EscapeString(node.WikiLinkText()))
+		r.Tag("/a", nil, false)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *ProtylePreviewRenderer) renderWikiLinkEmbed(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		dest, text := html.EscapeString(r.WikiLinkDest(node)), html.EscapeString(node.WikiLinkText())
+		if isWikiLinkImage(node) {
+			r.Tag("img", [][]string{{"src", dest}, {"alt", text}, {"class", "wikilink-embed"}}, true)
+		} else {
+			r.Tag("a", [][]string{{"href", dest}, {"class", "wikilink wikilink-embed"}}, false)
+			r.WriteString(text)
+			r.Tag("/a", nil, false)
+		}
+	}
+	return ast.WalkSkipChildren
+}
+
 func (r *ProtylePreviewRenderer) renderYamlFrontMatterCloseMarker(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
 		r.WriteString("</code></pre>")
@@ -627,7 +647,9 @@ func (r *ProtylePreviewRenderer) renderFootnotesRef(node *ast.Node, entering boo
 		idx, _ := r.Tree.FindFootnotesDef(node.Tokens)
 		idxStr := strconv.Itoa(idx)
 		r.Tag("sup", [][]string{{"class", "footnotes-ref"}, {"id", "footnotes-ref-" + node.FootnotesRefId}}, false)
-		r.Tag("a", [][]string{{"href", r.Options.LinkBase + "#footnotes-def-" + idxStr}}, false)
+		href, linkAttrs := r.ResolveLink(node, []byte("#footnotes-def-"+idxStr))
+		href = r.sanitizeURL(href)
+		r.Tag("a", append([][]string{{"href", r.Options.LinkBase + util.BytesToStr(html.EscapeHTML(href))}}, linkAttrs...), false)
 		r.WriteString(idxStr)
 		r.Tag("/a", nil, false)
 		r.Tag("/sup", nil, false)
@@ -970,14 +992,14 @@ func (r *ProtylePreviewRenderer) renderImage(node *ast.Node, entering bool) ast.
 			}
 			r.Tag("span", attrs, false)
 			r.WriteString("<img src=\"")
-			destTokens := node.ChildByType(ast.NodeLinkDest).Tokens
+			destTokens, linkAttrs := r.ResolveLink(node, node.ChildByType(ast.NodeLinkDest).Tokens)
 			destTokens = r.LinkPath(destTokens)
 			if "" != r.Options.ImageLazyLoading {
 				r.Write(html.EscapeHTML(util.StrToBytes(r.Options.ImageLazyLoading)))
 				r.WriteString("\" data-src=\"")
 			}
 			r.Write(html.EscapeHTML(destTokens))
-			r.WriteString("\" alt=\"")
+			r.WriteString("\"" + r.linkAttrsStr(linkAttrs) + " alt=\"")
 		}
 		r.DisableTags++
 		return ast.WalkContinue
@@ -1009,9 +1031,7 @@ func (r *ProtylePreviewRenderer) renderImage(node *ast.Node, entering bool) ast.
 		buf := r.Writer.Bytes()
 		idx := bytes.LastIndex(buf, []byte("<img src="))
 		imgBuf := buf[idx:]
-		if r.Options.Sanitize {
-			imgBuf = sanitize(imgBuf)
-		}
+		imgBuf = r.sanitize(imgBuf)
 		r.Writer.Truncate(idx)
 		r.Writer.Write(imgBuf)
 	}
@@ -1023,19 +1043,14 @@ func (r *ProtylePreviewRenderer) renderLink(node *ast.Node, entering bool) ast.W
 		r.LinkTextAutoSpacePrevious(node)
 
 		dest := node.ChildByType(ast.NodeLinkDest)
-		destTokens := dest.Tokens
-		if r.Options.Sanitize {
-			tokens := bytes.TrimSpace(destTokens)
-			tokens = bytes.ToLower(tokens)
-			if bytes.HasPrefix(tokens, []byte("javascript:")) {
-				destTokens = nil
-			}
-		}
+		destTokens, linkAttrs := r.ResolveLink(node, dest.Tokens)
+		destTokens = r.sanitizeURL(destTokens)
 		destTokens = r.LinkPath(destTokens)
 		attrs := [][]string{{"href", util.BytesToStr(html.EscapeHTML(destTokens))}}
 		if title := node.ChildByType(ast.NodeLinkTitle); nil != title && nil != title.Tokens {
 			attrs = append(attrs, []string{"title", util.BytesToStr(html.EscapeHTML(title.Tokens))})
 		}
+		attrs = append(attrs, linkAttrs...)
 		r.Tag("a", attrs, false)
 	} else {
 		r.Tag("/a", nil, false)
@@ -1049,9 +1064,7 @@ func (r *ProtylePreviewRenderer) renderHTML(node *ast.Node, entering bool) ast.W
 	if entering {
 		r.Newline()
 		tokens := node.Tokens
-		if r.Options.Sanitize {
-			tokens = sanitize(tokens)
-		}
+		tokens = r.sanitize(tokens)
 		tokens = r.tagSrcPath(tokens)
 		r.Write(tokens)
 		r.Newline()
@@ -1062,9 +1075,7 @@ func (r *ProtylePreviewRenderer) renderHTML(node *ast.Node, entering bool) ast.W
 func (r *ProtylePreviewRenderer) renderInlineHTML(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
 		tokens := node.Tokens
-		if r.Options.Sanitize {
-			tokens = sanitize(tokens)
-		}
+		tokens = r.sanitize(tokens)
 		r.Write(tokens)
 	}
 	return ast.WalkContinue
@@ -1238,6 +1249,25 @@ func (r *ProtylePreviewRenderer) renderBlockquoteMarker(node *ast.Node, entering
 	return ast.WalkContinue
 }
 
+func (r *ProtylePreviewRenderer) renderAdmonition(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.Newline()
+		attrs := [][]string{{"class", "admonition admonition-" + html.EscapeString(node.AdmonitionKind)}}
+		attrs = append(attrs, node.KramdownIAL...)
+		r.Tag("div", attrs, false)
+		r.Newline()
+		r.Tag("p", [][]string{{"class", "admonition-title"}}, false)
+		r.WriteString(html.EscapeString(AdmonitionTitle(node)))
+		r.Tag("/p", nil, false)
+		r.Newline()
+	} else {
+		r.Newline()
+		r.Tag("/div", nil, false)
+		r.Newline()
+	}
+	return ast.WalkContinue
+}
+
 func (r *ProtylePreviewRenderer) renderHeading(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
 		r.Newline()
@@ -1245,18 +1275,18 @@ func (r *ProtylePreviewRenderer) renderHeading(node *ast.Node, entering bool) as
 		r.WriteString("<h" + level)
 		id := node.ID
 		if "" == id {
-			id = HeadingID(node)
+			id = r.HeadingID(node)
 		}
 		if r.Options.ToC || r.Options.HeadingID || r.Options.KramdownBlockIAL {
 			r.WriteString(" id=\"" + id + "\"")
 			if r.Options.KramdownBlockIAL {
 				if "id" != r.Options.KramdownIALIDRenderName && 0 < len(node.KramdownIAL) {
-					r.WriteString(" " + r.Options.KramdownIALIDRenderName + "=\"" + node.HeadingNormalizedID + "\"")
+					r.WriteString(" " + r.Options.KramdownIALIDRenderName + "=\"" + r.HeadingID(node) + "\"")
 				}
 				if 1 < len(node.KramdownIAL) {
 					exceptID := node.KramdownIAL[1:]
 					for _, attr := range exceptID {
-						r.WriteString(" " + attr[0] + "=\"" + attr[1] + "\"")
+						r.WriteString(r.attrStr(attr[0], attr[1]))
 					}
 				}
 			}
@@ -1264,7 +1294,7 @@ func (r *ProtylePreviewRenderer) renderHeading(node *ast.Node, entering bool) as
 		r.WriteString(">")
 	} else {
 		if r.Options.HeadingAnchor {
-			id := HeadingID(node)
+			id := r.HeadingID(node)
 			r.Tag("a", [][]string{{"id", "editorAnchor-" + id}, {"class", "editor-anchor"}, {"href", "#" + id}}, false)
 			r.WriteString(`<svg viewBox="0 0 16 16" version="1.1" width="16" height="16"><path fill-rule="evenodd" d="M4 9h1v1H4c-1.5 0-3-1.69-3-3.5S2.55 3 4 3h4c1.45 0 3 1.69 3 3.5 0 1.41-.91 2.72-2 3.25V8.59c.58-.45 1-1.27 1-2.09C10 5.22 8.98 4 8 4H4c-.98 0-2 1.22-2 2.5S3 9 4 9zm9-3h-1v1h1c1 0 2 1.22 2 2.5S13.98 12 13 12H9c-.98 0-2-1.22-2-2.5 0-.83.42-1.64 1-2.09V6.25c-1.09.53-2 1.84-2 3.25C6 11.31 7.55 13 9 13h4c1.45 0 3-1.69 3-3.5S14.5 6 13 6z"></path></svg>`)
 			r.Tag("/a", nil, false)
@@ -1379,8 +1409,11 @@ func (r *ProtylePreviewRenderer) renderTextMarkAttrs(node *ast.Node) (attrs [][]
 		if "block-ref" == typ {
 			attrs = append(attrs, []string{"data-subtype", node.TextMarkBlockRefSubtype})
 			attrs = append(attrs, []string{"data-id", node.TextMarkBlockRefID})
+			if href := r.ResolveLinkStr(node, node.TextMarkBlockRefID); href != node.TextMarkBlockRefID {
+				attrs = append(attrs, []string{"data-href", util.BytesToStr(r.LinkPath([]byte(href)))})
+			}
 		} else if "a" == typ {
-			href := node.TextMarkAHref
+			href := r.ResolveLinkStr(node, node.TextMarkAHref)
 			href = string(r.LinkPath([]byte(href)))
 
 			attrs = append(attrs, []string{"data-href", href})
@@ -1416,5 +1449,8 @@ func (r *ProtylePreviewRenderer) spanNodeAttrs(node *ast.Node, attrs *[][]string
 
 func (r *ProtylePreviewRenderer) Render() (output []byte) {
 	output = r.BaseRenderer.Render()
+	if nil != r.Options.SanitizePolicy {
+		output = r.Options.SanitizePolicy.sanitize(output)
+	}
 	return
 }
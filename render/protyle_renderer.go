@@ -56,6 +56,7 @@ func NewProtyleRenderer(tree *parse.Tree, options *Options) *ProtyleRenderer {
 	ret.RendererFuncs[ast.NodeStrongU8eCloseMarker] = ret.renderStrongU8eCloseMarker
 	ret.RendererFuncs[ast.NodeBlockquote] = ret.renderBlockquote
 	ret.RendererFuncs[ast.NodeBlockquoteMarker] = ret.renderBlockquoteMarker
+	ret.RendererFuncs[ast.NodeAdmonition] = ret.renderAdmonition
 	ret.RendererFuncs[ast.NodeHeading] = ret.renderHeading
 	ret.RendererFuncs[ast.NodeHeadingC8hMarker] = ret.renderHeadingC8hMarker
 	ret.RendererFuncs[ast.NodeHeadingID] = ret.renderHeadingID
@@ -115,6 +116,8 @@ func NewProtyleRenderer(tree *parse.Tree, options *Options) *ProtyleRenderer {
 	ret.RendererFuncs[ast.NodeFileAnnotationRefID] = ret.renderFileAnnotationRefID
 	ret.RendererFuncs[ast.NodeFileAnnotationRefSpace] = ret.renderFileAnnotationRefSpace
 	ret.RendererFuncs[ast.NodeFileAnnotationRefText] = ret.renderFileAnnotationRefText
+	ret.RendererFuncs[ast.NodeWikiLink] = ret.renderWikiLink
+	ret.RendererFuncs[ast.NodeWikiLinkEmbed] = ret.renderWikiLinkEmbed
 	ret.RendererFuncs[ast.NodeMark] = ret.renderMark
 	ret.RendererFuncs[ast.NodeMark1OpenMarker] = ret.renderMark1OpenMarker
 	ret.RendererFuncs[ast.NodeMark1CloseMarker] = ret.renderMark1CloseMarker
@@ -337,9 +340,7 @@ func (r *ProtyleRenderer) renderVideo(node *ast.Node, entering bool) ast.WalkSta
 		r.Tag("div", [][]string{{"class", "iframe-content"}}, false)
 		r.WriteString(editor.Zwsp)
 		tokens := bytes.ReplaceAll(node.Tokens, editor.CaretTokens, nil)
-		if r.Options.Sanitize {
-			tokens = sanitize(tokens)
-		}
+		tokens = r.sanitize(tokens)
 		dataSrc := r.tagSrc(tokens)
 		src := r.LinkPath(dataSrc)
 		tokens = r.replaceSrc(tokens, src, dataSrc)
@@ -361,9 +362,7 @@ func (r *ProtyleRenderer) renderAudio(node *ast.Node, entering bool) ast.WalkSta
 		r.Tag("div", attrs, false)
 		r.Tag("div", [][]string{{"class", "iframe-content"}}, false)
 		tokens := bytes.ReplaceAll(node.Tokens, editor.CaretTokens, nil)
-		if r.Options.Sanitize {
-			tokens = sanitize(tokens)
-		}
+		tokens = r.sanitize(tokens)
 		dataSrc := r.tagSrc(tokens)
 		src := r.LinkPath(dataSrc)
 		tokens = r.replaceSrc(tokens, src, dataSrc)
@@ -385,9 +384,7 @@ func (r *ProtyleRenderer) renderWidget(node *ast.Node, entering bool) ast.WalkSt
 		r.Tag("div", attrs, false)
 		r.Tag("div", [][]string{{"class", "iframe-content"}}, false)
 		tokens := bytes.ReplaceAll(node.Tokens, editor.CaretTokens, nil)
-		if r.Options.Sanitize {
-			tokens = sanitize(tokens)
-		}
+		tokens = r.sanitize(tokens)
 		dataSrc := r.tagSrc(tokens)
 		src := r.LinkPath(dataSrc)
 		tokens = r.replaceSrc(tokens, src, dataSrc)
@@ -409,9 +406,7 @@ func (r *ProtyleRenderer) renderIFrame(node *ast.Node, entering bool) ast.WalkSt
 		r.Tag("div", attrs, false)
 		r.Tag("div", [][]string{{"class", "iframe-content"}}, false)
 		tokens := bytes.ReplaceAll(node.Tokens, editor.CaretTokens, nil)
-		if r.Options.Sanitize {
-			tokens = sanitize(tokens)
-		}
+		tokens = r.sanitize(tokens)
 		dataSrc := r.tagSrc(tokens)
 		src := r.LinkPath(dataSrc)
 		tokens = r.replaceSrc(tokens, src, dataSrc)
@@ -516,6 +511,24 @@ func (r *ProtyleRenderer) renderFileAnnotationRefText(node *ast.Node, entering b
 	return ast.WalkContinue
 }
 
+func (r *ProtyleRenderer) renderWikiLink(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		// data-wiki-link 记录维基链接目标，用于从 DOM 还原 [[Page#Heading|Alias]]
+		attrs := [][]string{{"data-type", "a"}, {"data-href", html.EscapeString(r.WikiLinkDest(node))}, {"data-wiki-link", html.EscapeString(wikiLinkTarget(node))}}
+		if ast.NodeWikiLinkEmbed == node.Type {
+			attrs = append(attrs, []string{"data-subtype", "wiki-embed"})
+		}
+		r.Tag("span", attrs, false)
+		r.WriteString(html.EscapeString(node.WikiLinkText()))
+		r.Tag("/span", nil, false)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *ProtyleRenderer) renderWikiLinkEmbed(node *ast.Node, entering bool) ast.WalkStatus {
+	return r.renderWikiLink(node, entering)
+}
+
 func (r *ProtyleRenderer) renderGitConflictCloseMarker(node *ast.Node, entering bool) ast.WalkStatus {
 	return ast.WalkContinue
 }
@@ -1250,10 +1263,8 @@ func (r *ProtyleRenderer) renderImage(node *ast.Node, entering bool) ast.WalkSta
 		r.WriteString("<span class=\"protyle-icon protyle-icon--only\"><svg class=\"svg\"><use xlink:href=\"#iconMore\"></use></svg></span>")
 		r.Tag("/span", nil, false)
 	} else {
-		destTokens := node.ChildByType(ast.NodeLinkDest).Tokens
-		if r.Options.Sanitize {
-			destTokens = sanitize(destTokens)
-		}
+		destTokens, _ := r.ResolveLink(node, node.ChildByType(ast.NodeLinkDest).Tokens)
+		destTokens = r.sanitize(destTokens)
 		destTokens = bytes.ReplaceAll(destTokens, editor.CaretTokens, nil)
 		dataSrcTokens := destTokens
 		dataSrc := util.BytesToStr(dataSrcTokens)
@@ -1279,9 +1290,7 @@ func (r *ProtyleRenderer) renderImage(node *ast.Node, entering bool) ast.WalkSta
 		buf := r.Writer.Bytes()
 		idx := bytes.LastIndex(buf, []byte("<img src="))
 		imgBuf := buf[idx:]
-		if r.Options.Sanitize {
-			imgBuf = sanitize(imgBuf)
-		}
+		imgBuf = r.sanitize(imgBuf)
 		imgBuf = r.tagSrcPath(imgBuf)
 		r.Writer.Truncate(idx)
 		r.Writer.Write(imgBuf)
@@ -1320,14 +1329,11 @@ func (r *ProtyleRenderer) renderImage(node *ast.Node, entering bool) ast.WalkSta
 func (r *ProtyleRenderer) renderLink(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
 		dest := node.ChildByType(ast.NodeLinkDest)
-		destTokens := dest.Tokens
-		if r.Options.Sanitize {
+		destTokens, _ := r.ResolveLink(node, dest.Tokens)
+		if r.sanitizing() {
 			destTokens = bytes.TrimSpace(destTokens)
-			destTokens = sanitize(destTokens)
-			tokens := bytes.ToLower(destTokens)
-			if bytes.HasPrefix(tokens, []byte("javascript:")) {
-				destTokens = nil
-			}
+			destTokens = r.sanitize(destTokens)
+			destTokens = r.sanitizeURL(destTokens)
 		}
 		destTokens = r.LinkPath(destTokens)
 
@@ -1581,6 +1587,25 @@ func (r *ProtyleRenderer) renderBlockquoteMarker(node *ast.Node, entering bool)
 	return ast.WalkContinue
 }
 
+func (r *ProtyleRenderer) renderAdmonition(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		var attrs [][]string
+		r.blockNodeAttrs(node, &attrs, "admonition admonition-"+html.EscapeString(node.AdmonitionKind))
+		attrs = append(attrs, []string{"data-kind", html.EscapeString(node.AdmonitionKind)})
+		if "" != node.AdmonitionTitle {
+			attrs = append(attrs, []string{"data-title", html.EscapeString(node.AdmonitionTitle)})
+		}
+		if 0 < node.AdmonitionFenceLen {
+			attrs = append(attrs, []string{"data-fence-len", strconv.Itoa(node.AdmonitionFenceLen)})
+		}
+		r.Tag("div", attrs, false)
+	} else {
+		r.renderIAL(node)
+		r.Tag("/div", nil, false)
+	}
+	return ast.WalkContinue
+}
+
 func (r *ProtyleRenderer) renderHeading(node *ast.Node, entering bool) ast.WalkStatus {
 	if entering {
 		var attrs [][]string
@@ -1740,7 +1765,7 @@ func (r *ProtyleRenderer) nodeClass(node *ast.Node, attrs *[][]string, class str
 }
 
 func (r *ProtyleRenderer) nodeDataType(node *ast.Node, attrs *[][]string) {
-	*attrs = append(*attrs, []string{"data-type", node.Type.String()})
+	*attrs = append(*attrs, []string{"data-type", r.nodeTypeName(node)})
 }
 
 func (r *ProtyleRenderer) nodeID(node *ast.Node, attrs *[][]string) {
@@ -1838,7 +1863,10 @@ func (r *ProtyleRenderer) renderTextMarkAttrs(node *ast.Node) (attrs [][]string)
 			attrs = append(attrs, []string{"data-subtype", node.TextMarkBlockRefSubtype})
 			attrs = append(attrs, []string{"data-id", node.TextMarkBlockRefID})
 		} else if "a" == typ {
-			href := node.TextMarkAHref
+			href := r.ResolveLinkStr(node, node.TextMarkAHref)
+			if r.sanitizing() {
+				href = string(r.sanitizeURL([]byte(strings.TrimSpace(href))))
+			}
 			href = string(r.LinkPath([]byte(href)))
 			if node.ParentIs(ast.NodeTableCell) {
 				href = strings.ReplaceAll(href, "\\|", "|")
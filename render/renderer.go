@@ -2,6 +2,7 @@ package render
 
 import (
 	"bytes"
+	"errors"
 	"strconv"
 	"strings"
 	"unicode"
@@ -16,6 +17,22 @@ import (
 	"github.com/pafthang/md/util"
 )
 
+// ErrUnknownNode 表示语法树中存在没有渲染函数的节点，这些节点不会输出，但是会继续渲染它们的子节点。
+var ErrUnknownNode = errors.New("unknown node")
+
+// UnknownNodeError 描述了没有渲染函数的节点类型，可以使用 errors.Is(err, ErrUnknownNode) 判断。
+type UnknownNodeError struct {
+	Type ast.NodeType // 节点类型
+}
+
+func (e *UnknownNodeError) Error() string {
+	return ErrUnknownNode.Error() + " [type=" + e.Type.String() + "]"
+}
+
+func (e *UnknownNodeError) Unwrap() error {
+	return ErrUnknownNode
+}
+
 // RendererFunc 描述了渲染器函数签名。
 type RendererFunc func(n *ast.Node, entering bool) ast.WalkStatus
 
@@ -67,18 +84,24 @@ type Options struct {
 	// ChineseParagraphBeginningSpace 设置是否使用传统中文排版“段落开头空两格”。
 	ChineseParagraphBeginningSpace bool
 	// Sanitize 设置是否启用 XSS 安全过滤 https://github.com/pafthang/md/issues/51
-	// 注意：MD 目前的实现存在一些漏洞，请不要依赖它来防御 XSS 攻击。
+	// 注意：该开关仅基于黑名单过滤事件属性和 javascript: 地址，请不要依赖它来防御 XSS 攻击，需要防御时请使用 SanitizePolicy。
 	Sanitize bool
+	// SanitizePolicy 设置基于白名单的 HTML 过滤策略，设置后 Sanitize 开关不再生效。
+	// HtmlRenderer 和 ProtylePreviewRenderer 会使用该策略过滤完整的输出，其他 HTML 渲染器使用该策略过滤原始 HTML 和链接地址。
+	SanitizePolicy *SanitizePolicy `json:"-"`
 	// FixTermTypo 设置是否对普通文本中出现的术语进行修正。
 	// https://github.com/sparanoid/chinese-copywriting-guidelines
 	// 注意：开启术语修正的话会默认在中西文之间插入空格。
 	FixTermTypo bool
 	// Terms 将传入的 terms 合并覆盖到已有的 Terms 字典。
-	Terms map[string]string
+	Terms map[string]string `json:"-"`
 	// ToC 设置是否打开“目录”支持。
 	ToC bool
 	// HeadingID 设置是否打开“自定义标题 ID”支持。
 	HeadingID bool
+	// HeadingIDSlugger 设置标题 ID 的生成策略，比如 SluggerGitHub，重复的 ID 依次追加 -1、-2 后缀。
+	// 为 nil 时使用默认规则：保留字母和数字，其他字符替换为 -，重复的 ID 追加 -。
+	HeadingIDSlugger *Slugger
 	// KramdownIALIDRenderName 设置 kramdown 内联属性列表中出现 id 属性时渲染 id 属性用的 name(key) 名称，默认为 "id"。
 	// 仅在 HTML 渲染器 HtmlRenderer 中支持。
 	KramdownIALIDRenderName string
@@ -95,6 +118,8 @@ type Options struct {
 	// LinkBase 设置链接、图片、脚注的基础路径。如果用户在链接或者图片地址中使用相对路径（没有协议前缀且不以 / 开头）并且 LinkBase 不为空则会用该值作为前缀。
 	// 比如 LinkBase 设置为 http://domain.com/，对于 ![foo](bar.png) 则渲染为 <img src="http://domain.com/bar.png" alt="foo" />
 	LinkBase string
+	// LinkResolver 设置链接地址改写回调，用于改写链接、图片、自动链接、脚注引用和块引用的地址，比如将相对路径映射为 CDN 地址。
+	LinkResolver LinkResolver `json:"-"`
 	// LinkPrefix 设置连接、图片的路径前缀。一旦设置该值，链接渲染将强制添加该值作为链接前缀，这有别于 LinkBase。
 	// 比如 LinkPrefix 设置为 http://domain.com，对于使用绝对路径的 ![foo](/local/path/bar.png) 则渲染为 <img src="http://domain.com/local/path/bar.png" alt="foo" />；
 	// 在 LinkBase 和 LinkPrefix 同时设置的情况下，会先处理 LinkBase 逻辑，最后再在 LinkBase 处理结果上加上 LinkPrefix。
@@ -109,6 +134,10 @@ type Options struct {
 	ProtyleMarkNetImg bool
 	// Spellcheck 设置是否启用拼写检查
 	Spellcheck bool
+	// WikiLinkResolver 设置维基链接页面名称到链接地址的解析函数，为空时直接使用页面名称作为相对路径。
+	WikiLinkResolver func(page string) (dest string) `json:"-"`
+	// FormatStyle 设置格式化渲染器的输出风格，比如列表标记符、强调标记符和标题风格，为 nil 时保持原文风格。
+	FormatStyle *FormatStyle
 }
 
 func NewOptions() *Options {
@@ -156,6 +185,10 @@ type BaseRenderer struct {
 	DisableTags         int                              // 标签嵌套计数器，用于判断不可能出现标签嵌套的情况，比如语法树允许图片节点包含链接节点，但是 HTML <img> 不能包含 <a>
 	FootnotesDefs       []*ast.Node                      // 脚注定义集
 	RenderingFootnotes  bool                             // 是否正在渲染脚注定义
+	DeferFootnotes      bool                             // 是否不在渲染结果末尾输出脚注定义，分多次渲染同一文档时由调用方在最后统一输出
+	HeadingIDOccurs     map[string]int                   // 已经占用的标题 ID，不为 nil 时在使用同一个 map 的多次渲染之间去重
+
+	err error // 渲染时发生的第一个错误
 }
 
 // NewBaseRenderer 构造一个 BaseRenderer。
@@ -171,6 +204,7 @@ func (r *BaseRenderer) Render() (output []byte) {
 	r.LastOut = lex.ItemNewline
 	r.Writer = &bytes.Buffer{}
 	r.Writer.Grow(4096)
+	r.err = nil
 
 	ast.Walk(r.Tree.Root, func(n *ast.Node, entering bool) ast.WalkStatus {
 		extRender := r.ExtRendererFuncs[n.Type]
@@ -194,8 +228,81 @@ func (r *BaseRenderer) Render() (output []byte) {
 	return
 }
 
+// sanitize 根据 SanitizePolicy 或者 Sanitize 选项过滤 HTML 片段 tokens。
+func (r *BaseRenderer) sanitize(tokens []byte) []byte {
+	if nil != r.Options.SanitizePolicy {
+		return r.Options.SanitizePolicy.sanitize(tokens)
+	}
+	if r.Options.Sanitize {
+		return sanitize(tokens)
+	}
+	return tokens
+}
+
+// sanitizeURL 根据 SanitizePolicy 或者 Sanitize 选项过滤链接地址 dest，不安全时返回 nil。
+func (r *BaseRenderer) sanitizeURL(dest []byte) []byte {
+	if nil != r.Options.SanitizePolicy {
+		if !r.Options.SanitizePolicy.AllowURL(util.BytesToStr(dest)) {
+			return nil
+		}
+		return dest
+	}
+	if r.Options.Sanitize && bytes.HasPrefix(bytes.ToLower(bytes.TrimSpace(dest)), []byte("javascript:")) {
+		return nil
+	}
+	return dest
+}
+
+// sanitizeStyle 根据 SanitizePolicy 或者 Sanitize 选项使用 CSS 过滤策略清理经过 HTML 转义的 style 属性值。
+func (r *BaseRenderer) sanitizeStyle(style string) string {
+	if nil != r.Options.SanitizePolicy {
+		return sanitizeStyleAttr(r.Options.SanitizePolicy.css(), style)
+	}
+	if r.Options.Sanitize {
+		return sanitizeStyleAttr(defaultCSSPolicy, style)
+	}
+	return style
+}
+
+// attrStr 返回属性 key="value" 的 HTML 字符串（带前导空格），其中 style 属性值会被清理，清理后为空时返回 ""。
+func (r *BaseRenderer) attrStr(key, value string) string {
+	if "style" == key {
+		if value = r.sanitizeStyle(value); "" == value {
+			return ""
+		}
+	}
+	return " " + key + "=\"" + value + "\""
+}
+
+// nodeTypeName 返回节点 node 的类型名称，自定义节点类型使用解析选项中注册的名称。
+func (r *BaseRenderer) nodeTypeName(node *ast.Node) string {
+	if nil != r.Tree && nil != r.Tree.Context && nil != r.Tree.Context.ParseOption {
+		return r.Tree.Context.ParseOption.NodeTypeName(node.Type)
+	}
+	return node.Type.String()
+}
+
+// sanitizing 判断是否需要进行 HTML 过滤。
+func (r *BaseRenderer) sanitizing() bool {
+	return r.Options.Sanitize || nil != r.Options.SanitizePolicy
+}
+
+// Err 返回最近一次 Render 时发生的第一个错误，比如 *UnknownNodeError，没有错误时返回 nil。
+func (r *BaseRenderer) Err() error {
+	return r.err
+}
+
+// setErr 记录渲染时发生的错误 err，只保留第一个错误。
+func (r *BaseRenderer) setErr(err error) {
+	if nil == r.err {
+		r.err = err
+	}
+}
+
 func (r *BaseRenderer) renderDefault(n *ast.Node, entering bool) ast.WalkStatus {
-	r.WriteString("not found render function for node [type=" + n.Type.String() + ", Tokens=" + util.BytesToStr(n.Tokens) + "]")
+	if entering {
+		r.setErr(&UnknownNodeError{Type: n.Type})
+	}
 	return ast.WalkContinue
 }
 
@@ -339,19 +446,22 @@ func SubStr(str string, length int) (ret string) {
 	return
 }
 
+// HeadingID 使用默认规则返回标题 heading 的 ID，需要使用 Options.HeadingIDSlugger 的话请调用 BaseRenderer.HeadingID。
 func HeadingID(heading *ast.Node) (ret string) {
 	if 0 == len(util.StrToBytes(heading.HeadingNormalizedID)) {
-		headingID0(heading)
+		headingID0(heading, nil)
 	}
 	return heading.HeadingNormalizedID
 }
 
-func headingID0(heading *ast.Node) {
+func headingID0(heading *ast.Node, idOccurs map[string]int) {
 	var root *ast.Node
 	for root = heading.Parent; ast.NodeDocument != root.Type; root = root.Parent {
 	}
 
-	idOccurs := map[string]int{}
+	if nil == idOccurs {
+		idOccurs = map[string]int{}
+	}
 	ast.Walk(root, func(n *ast.Node, entering bool) ast.WalkStatus {
 		if entering {
 			if ast.NodeHeading == n.Type {
@@ -442,7 +552,7 @@ func (r *BaseRenderer) Tag(name string, attrs [][]string, selfclosing bool) {
 	r.WriteString(name)
 	if 0 < len(attrs) {
 		for _, attr := range attrs {
-			r.WriteString(" " + attr[0] + "=\"" + attr[1] + "\"")
+			r.WriteString(r.attrStr(attr[0], attr[1]))
 		}
 	}
 	if selfclosing {
@@ -459,22 +569,7 @@ func (r *BaseRenderer) headings() (ret []*Heading) {
 			continue
 		}
 
-		id := HeadingID(heading)
-		if r.Options.EditorWYSIWYG {
-			id = "wysiwyg-" + id
-		} else if r.Options.EditorIR {
-			id = "ir-" + id
-		}
-
-		if r.Options.KramdownBlockIAL {
-			for _, kv := range heading.KramdownIAL {
-				if "id" == kv[0] {
-					id = kv[1]
-					break
-				}
-			}
-		}
-
+		id := r.headingAnchorID(heading)
 		h := &Heading{
 			ID:      id,
 			Box:     r.Tree.Box,
@@ -535,6 +630,9 @@ func headingText(n *ast.Node) (ret string) {
 			buf.WriteString("<code>")
 			buf.Write(html.EscapeHTML(n.Tokens))
 			buf.WriteString("</code>")
+		case ast.NodeWikiLink, ast.NodeWikiLinkEmbed:
+			buf.WriteString(html.EscapeString(n.WikiLinkText()))
+			return ast.WalkSkipChildren
 		case ast.NodeText:
 			if n.ParentIs(ast.NodeStrong) {
 				buf.WriteString("<strong>")
@@ -678,11 +776,9 @@ func (r *BaseRenderer) NodeAttrsStr(node *ast.Node) (ret string) {
 		if "id" == kv[0] {
 			continue
 		}
-		ret += kv[0] + "=\"" + kv[1] + "\" "
-	}
-	if "" != ret {
-		ret = ret[:len(ret)-1]
+		ret += r.attrStr(kv[0], kv[1])
 	}
+	ret = strings.TrimPrefix(ret, " ")
 	return
 }
 
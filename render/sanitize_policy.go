@@ -0,0 +1,394 @@
+package render
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/pafthang/md/editor"
+	"github.com/pafthang/md/html"
+	"github.com/pafthang/md/util"
+)
+
+// SanitizePolicy 描述了基于白名单的 HTML 过滤策略。
+//
+// 过滤规则：
+//   - 不在 Elements 中的元素会被移除，但保留其内容；SkipContentElements 中的元素连同内容一起移除；
+//   - 元素上只保留 Elements 中为该元素列出的属性以及 GlobalAttributes 中的属性，on 开头的事件属性总是被移除；
+//   - 属性值需要通过 AttributeValidators 中对应的校验器，style 属性值和 <style> 元素内容使用 CSS 策略清理；
+//   - URLAttributes 中的属性值必须是相对地址或者使用 URLSchemes 中的协议，data: 协议仅允许非 SVG 的图片；
+//   - 文本内容总是会被转义，注释仅在 AllowComments 时保留。
+type SanitizePolicy struct {
+	// Elements 为允许的元素名（小写）到该元素允许的属性名列表的映射。
+	Elements map[string][]string
+	// GlobalAttributes 为所有允许的元素都可以使用的属性名，以 * 结尾表示前缀匹配，比如 data-*。
+	GlobalAttributes []string
+	// AttributeValidators 为属性值校验器，键为“元素名.属性名”或者属性名，前者优先，校验失败时移除该属性。
+	AttributeValidators map[string]func(value string) bool
+	// URLAttributes 为值是 URL 的属性名。
+	URLAttributes []string
+	// URLSchemes 为允许的 URL 协议（小写，不含冒号）。
+	URLSchemes []string
+	// AllowRelativeURLs 设置是否允许不带协议的相对地址和锚点。
+	AllowRelativeURLs bool
+	// SkipContentElements 为连同内容一起移除的元素名。
+	SkipContentElements []string
+	// AllowComments 设置是否保留 HTML 注释。
+	AllowComments bool
+	// CSS 为清理 style 属性值和 <style> 元素内容使用的 CSS 过滤策略，为 nil 时使用 NewCSSPolicy() 创建的默认策略。
+	CSS *CSSPolicy
+}
+
+// NewStrictUGCPolicy 创建适用于用户生成内容（评论、帖子等）的严格过滤策略。
+//
+// 仅允许 Markdown 渲染会产生的排版元素，不允许 style、data-* 属性以及 iframe、表单等嵌入元素。
+func NewStrictUGCPolicy() *SanitizePolicy {
+	ret := &SanitizePolicy{
+		Elements: map[string][]string{
+			"a":          {"href", "title"},
+			"abbr":       {"title"},
+			"b":          nil,
+			"blockquote": {"cite"},
+			"br":         nil,
+			"code":       nil,
+			"dd":         nil,
+			"del":        nil,
+			"details":    {"open"},
+			"div":        nil,
+			"dl":         nil,
+			"dt":         nil,
+			"em":         nil,
+			"figcaption": nil,
+			"figure":     nil,
+			"h1":         nil,
+			"h2":         nil,
+			"h3":         nil,
+			"h4":         nil,
+			"h5":         nil,
+			"h6":         nil,
+			"hr":         nil,
+			"i":          nil,
+			"img":        {"src", "alt", "title", "width", "height", "data-src"},
+			"input":      {"type", "checked", "disabled"},
+			"ins":        nil,
+			"kbd":        nil,
+			"li":         nil,
+			"mark":       nil,
+			"ol":         {"start"},
+			"p":          nil,
+			"pre":        nil,
+			"q":          {"cite"},
+			"s":          nil,
+			"samp":       nil,
+			"small":      nil,
+			"span":       nil,
+			"strong":     nil,
+			"sub":        nil,
+			"summary":    nil,
+			"sup":        nil,
+			"table":      nil,
+			"tbody":      nil,
+			"td":         {"align", "colspan", "rowspan"},
+			"tfoot":      nil,
+			"th":         {"align", "colspan", "rowspan"},
+			"thead":      nil,
+			"tr":         nil,
+			"u":          nil,
+			"ul":         nil,
+		},
+		GlobalAttributes: []string{"class", "id"},
+		AttributeValidators: map[string]func(string) bool{
+			"class":      ClassValidator(ugcClassPattern),
+			"id":         PatternValidator(safeIDPattern),
+			"input.type": PatternValidator(regexp.MustCompile(`^checkbox$`)),
+			"align":      PatternValidator(regexp.MustCompile(`^(left|center|right)$`)),
+			"colspan":    PatternValidator(numberPattern),
+			"rowspan":    PatternValidator(numberPattern),
+			"start":      PatternValidator(numberPattern),
+			"width":      PatternValidator(lengthPattern),
+			"height":     PatternValidator(lengthPattern),
+		},
+		URLAttributes:       []string{"href", "src", "cite", "data-src"},
+		URLSchemes:          []string{"http", "https", "mailto"},
+		AllowRelativeURLs:   true,
+		SkipContentElements: defaultSkipContentElements(),
+	}
+	return ret
+}
+
+// NewTrustedDocsPolicy 创建适用于受信任文档（项目文档、知识库等）的宽松过滤策略。
+//
+// 在 NewStrictUGCPolicy 的基础上允许 style、data-* 属性，布局元素以及音视频和 iframe 嵌入，但仍然移除脚本、事件属性和危险的 URL 协议。
+func NewTrustedDocsPolicy() *SanitizePolicy {
+	ret := NewStrictUGCPolicy()
+	for element, attrs := range map[string][]string{
+		"article":  nil,
+		"aside":    nil,
+		"audio":    {"src", "controls", "loop", "muted", "preload"},
+		"caption":  nil,
+		"center":   nil,
+		"cite":     nil,
+		"col":      {"span", "width"},
+		"colgroup": {"span", "width"},
+		"dfn":      nil,
+		"font":     {"color", "face", "size"},
+		"footer":   nil,
+		"header":   nil,
+		"iframe":   {"src", "width", "height", "allow", "allowfullscreen", "frameborder", "loading", "referrerpolicy", "sandbox", "title"},
+		"main":     nil,
+		"nav":      nil,
+		"picture":  nil,
+		"rp":       nil,
+		"rt":       nil,
+		"ruby":     nil,
+		"section":  nil,
+		"source":   {"src", "srcset", "type", "media", "sizes"},
+		"strike":   nil,
+		"tt":       nil,
+		"var":      nil,
+		"video":    {"src", "poster", "controls", "loop", "muted", "preload", "width", "height", "playsinline"},
+		"wbr":      nil,
+	} {
+		ret.Elements[element] = attrs
+	}
+	ret.Elements["img"] = append(ret.Elements["img"], "srcset", "sizes", "loading", "align")
+	ret.Elements["a"] = append(ret.Elements["a"], "name", "target", "rel")
+	ret.Elements["ol"] = append(ret.Elements["ol"], "type", "reversed")
+	ret.Elements["td"] = append(ret.Elements["td"], "valign", "width")
+	ret.Elements["th"] = append(ret.Elements["th"], "valign", "width", "scope")
+	ret.GlobalAttributes = []string{"class", "id", "style", "title", "lang", "dir", "data-*"}
+	ret.AttributeValidators["class"] = ClassValidator(safeClassPattern)
+	ret.AttributeValidators["a.target"] = PatternValidator(regexp.MustCompile(`^(_blank|_self|_parent|_top)$`))
+	ret.AttributeValidators["td.width"] = PatternValidator(lengthPattern)
+	ret.AttributeValidators["th.width"] = PatternValidator(lengthPattern)
+	ret.URLAttributes = append(ret.URLAttributes, "srcset", "poster")
+	ret.URLSchemes = append(ret.URLSchemes, "ftp", "tel", "data")
+	ret.CSS = NewCSSPolicy()
+	return ret
+}
+
+// PatternValidator 返回使用正则表达式 pattern 校验属性值的校验器。
+func PatternValidator(pattern *regexp.Regexp) func(string) bool {
+	return func(value string) bool {
+		return pattern.MatchString(value)
+	}
+}
+
+// ClassValidator 返回校验 class 属性的校验器，其中以空白分隔的每个类名都需要匹配 pattern。
+func ClassValidator(pattern *regexp.Regexp) func(string) bool {
+	return func(value string) bool {
+		for _, class := range strings.Fields(value) {
+			if !pattern.MatchString(class) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+var (
+	ugcClassPattern  = regexp.MustCompile(`^(language-[\w+#.-]+|highlight-[\w-]+|footnotes-[\w-]+|admonition(-[\w-]+)?|editor-[\w-]+|wikilink(-embed)?|iframe|indent--2|render-node)$`)
+	safeClassPattern = regexp.MustCompile(`^[\w-]+$`)
+	safeIDPattern    = regexp.MustCompile(`^[\p{L}\p{N}_:.-]+$`)
+	numberPattern    = regexp.MustCompile(`^\d{1,4}$`)
+	lengthPattern    = regexp.MustCompile(`^\d{1,5}(\.\d+)?(%|px)?$`)
+)
+
+func defaultSkipContentElements() []string {
+	var ret []string
+	for element := range setOfElementsToSkipContent {
+		ret = append(ret, element)
+	}
+	return append(ret, "iframe", "textarea", "select", "template", "svg", "math")
+}
+
+// Sanitize 按照策略过滤 HTML 字符串 str。
+func (p *SanitizePolicy) Sanitize(str string) string {
+	return string(p.sanitize([]byte(str)))
+}
+
+func (p *SanitizePolicy) sanitize(tokens []byte) []byte {
+	var (
+		buff                     bytes.Buffer
+		skippingElementsCount    int
+		mostRecentlyStartedToken string
+	)
+
+	caretLeftSpace := bytes.Contains(tokens, []byte(" "+editor.Caret))
+	tokens = bytes.ReplaceAll(tokens, editor.CaretTokens, []byte(editor.CaretReplacement))
+
+	tokenizer := html.NewTokenizer(bytes.NewReader(tokens))
+	for {
+		if tokenizer.Next() == html.ErrorToken {
+			err := tokenizer.Err()
+			if err == io.EOF {
+				ret := buff.Bytes()
+				if caretLeftSpace {
+					ret = bytes.ReplaceAll(ret, []byte("\""+editor.CaretReplacement), []byte("\" "+editor.CaretReplacement))
+				} else {
+					ret = bytes.ReplaceAll(ret, []byte("\" "+editor.CaretReplacement), []byte("\""+editor.CaretReplacement))
+				}
+				ret = bytes.ReplaceAll(ret, []byte(editor.CaretReplacement), editor.CaretTokens)
+				return ret
+			}
+
+			return util.StrToBytes(html.EscapeString(err.Error()))
+		}
+
+		token := tokenizer.Token()
+		switch token.Type {
+		case html.CommentToken:
+			if p.AllowComments && 0 == skippingElementsCount {
+				buff.WriteString(token.String())
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if p.skipContent(token.Data) {
+				if html.StartTagToken == token.Type {
+					skippingElementsCount++
+				}
+				break
+			}
+			if 0 < skippingElementsCount || !p.allowElement(token.Data) {
+				break
+			}
+
+			mostRecentlyStartedToken = token.Data
+			token.Attr = p.sanitizeAttrs(token.Data, token.Attr)
+			writeLinkableBuf(&buff, &token)
+		case html.EndTagToken:
+			if mostRecentlyStartedToken == token.Data {
+				mostRecentlyStartedToken = ""
+			}
+			if p.skipContent(token.Data) {
+				if 0 < skippingElementsCount {
+					skippingElementsCount--
+				}
+				break
+			}
+			if 0 < skippingElementsCount || !p.allowElement(token.Data) {
+				break
+			}
+			buff.WriteString(token.String())
+		case html.TextToken:
+			if 0 < skippingElementsCount {
+				break
+			}
+			if "style" == mostRecentlyStartedToken {
+				// 策略允许 style 元素时不能转义 CSS 内容，只能按照 CSS 策略清理
+				buff.WriteString(p.css().SanitizeStylesheet(token.Data))
+				break
+			}
+			buff.WriteString(token.String())
+		}
+	}
+}
+
+func (p *SanitizePolicy) allowElement(element string) bool {
+	_, ok := p.Elements[element]
+	return ok
+}
+
+func (p *SanitizePolicy) skipContent(element string) bool {
+	for _, e := range p.SkipContentElements {
+		if e == element {
+			return !p.allowElement(element)
+		}
+	}
+	return false
+}
+
+func (p *SanitizePolicy) sanitizeAttrs(element string, attrs []*html.Attribute) (ret []*html.Attribute) {
+	for _, attr := range attrs {
+		if "style" == strings.ToLower(attr.Key) && p.hasAttr(element, "style") {
+			if attr.Val = p.css().SanitizeStyle(attr.Val); "" == attr.Val {
+				continue
+			}
+		}
+		if editor.CaretReplacement == attr.Key || p.AllowAttr(element, attr.Key, attr.Val) {
+			ret = append(ret, attr)
+		}
+	}
+	return
+}
+
+func (p *SanitizePolicy) css() *CSSPolicy {
+	if nil != p.CSS {
+		return p.CSS
+	}
+	return defaultCSSPolicy
+}
+
+// AllowAttr 判断元素 element 上的属性 key=value 是否满足策略。
+func (p *SanitizePolicy) AllowAttr(element, key, value string) bool {
+	key = strings.ToLower(key)
+	if strings.HasPrefix(key, "on") || !p.hasAttr(element, key) {
+		return false
+	}
+
+	validator := p.AttributeValidators[element+"."+key]
+	if nil == validator {
+		validator = p.AttributeValidators[key]
+	}
+	if nil != validator && !validator(value) {
+		return false
+	}
+
+	if !p.isURLAttr(key) {
+		return true
+	}
+	if "srcset" == key {
+		for _, candidate := range strings.Split(value, ",") {
+			if fields := strings.Fields(candidate); 0 < len(fields) && !p.AllowURL(fields[0]) {
+				return false
+			}
+		}
+		return true
+	}
+	return p.AllowURL(value)
+}
+
+func (p *SanitizePolicy) hasAttr(element, key string) bool {
+	for _, attrs := range [][]string{p.Elements[element], p.GlobalAttributes} {
+		for _, attr := range attrs {
+			if attr == key || (strings.HasSuffix(attr, "*") && strings.HasPrefix(key, attr[:len(attr)-1])) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (p *SanitizePolicy) isURLAttr(key string) bool {
+	for _, attr := range p.URLAttributes {
+		if attr == key {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowURL 判断 URL 地址 dest 是否满足策略，dest 中的 HTML 实体会先被解码。
+func (p *SanitizePolicy) AllowURL(dest string) bool {
+	dest = strings.ToLower(removeSpace(strings.TrimSpace(html.UnescapeString(dest))))
+	dest = strings.ReplaceAll(dest, " ", "")
+	scheme := ""
+	if idx := strings.IndexAny(dest, ":/?#"); 0 <= idx && ':' == dest[idx] {
+		scheme = dest[:idx]
+	}
+	if "" == scheme {
+		return p.AllowRelativeURLs
+	}
+
+	for _, s := range p.URLSchemes {
+		if s != scheme {
+			continue
+		}
+		if "data" == scheme {
+			// data: 仅允许图片，SVG 中可以包含脚本
+			return strings.HasPrefix(dest, "data:image/") && !strings.HasPrefix(dest, "data:image/svg")
+		}
+		return true
+	}
+	return false
+}
@@ -127,7 +127,7 @@ func sanitize(tokens []byte) []byte {
 				case "style":
 					// not encouraged, but if a policy allows CSS styles we
 					// should not HTML escape it as that would break the output
-					buff.WriteString(token.Data)
+					buff.WriteString(defaultCSSPolicy.SanitizeStylesheet(token.Data))
 				default:
 					// HTML escape the text
 					buff.WriteString(token.String())
@@ -180,6 +180,11 @@ func sanitizeAttrs(attrs []*html.Attribute) (ret []*html.Attribute) {
 		if !allowAttr(attr.Key) {
 			continue
 		}
+		if "style" == attr.Key {
+			if attr.Val = defaultCSSPolicy.SanitizeStyle(attr.Val); "" == attr.Val {
+				continue
+			}
+		}
 		if "src" == attr.Key || "srcdoc" == attr.Key || "srcset" == attr.Key || "href" == attr.Key {
 			val := strings.ToLower(strings.TrimSpace(attr.Val))
 			val = removeSpace(val)
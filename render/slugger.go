@@ -0,0 +1,206 @@
+package render
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pafthang/md/ast"
+	"github.com/pafthang/md/editor"
+	"github.com/pafthang/md/util"
+)
+
+// Slugger 描述了标题 ID 的生成策略。
+type Slugger struct {
+	Name string                   // 策略名称
+	Slug func(text string) string // 将标题纯文本转换为 ID，不需要处理重复
+}
+
+// NewSlugger 使用自定义函数 slug 构造一个标题 ID 生成策略。
+func NewSlugger(name string, slug func(text string) string) *Slugger {
+	return &Slugger{Name: name, Slug: slug}
+}
+
+var (
+	// SluggerGitHub 和 GitHub 一致：转为小写，去掉除 - 和 _ 以外的标点符号，空格替换为 -。
+	SluggerGitHub = NewSlugger("github", githubSlug)
+	// SluggerGitLab 和 GitLab 一致：在 GitHub 规则的基础上合并连续的 -。
+	SluggerGitLab = NewSlugger("gitlab", gitlabSlug)
+	// SluggerPandoc 和 pandoc auto_identifiers 一致：仅保留字母数字和 _-.，去掉第一个字母前的内容，结果为空时使用 section。
+	SluggerPandoc = NewSlugger("pandoc", pandocSlug)
+	// SluggerUnicode 保留原始大小写和所有非 ASCII 字符，仅去掉 ASCII 标点符号，空白替换为 -。
+	SluggerUnicode = NewSlugger("unicode", unicodeSlug)
+)
+
+// MarshalText 将标题 ID 生成策略序列化为策略名称。
+func (s *Slugger) MarshalText() ([]byte, error) {
+	return []byte(s.Name), nil
+}
+
+// UnmarshalText 按照策略名称还原内置的标题 ID 生成策略。
+//
+// 自定义策略无法还原，仅保留名称且 Slug 为 nil，使用时视为没有设置策略，这样序列化后的配置总是可以反序列化。
+func (s *Slugger) UnmarshalText(text []byte) error {
+	for _, slugger := range []*Slugger{SluggerGitHub, SluggerGitLab, SluggerPandoc, SluggerUnicode} {
+		if slugger.Name == string(text) {
+			*s = *slugger
+			return nil
+		}
+	}
+	*s = Slugger{Name: string(text)}
+	return nil
+}
+
+// HeadingIDs 按照 slugger 计算文档 root 中所有标题的 ID。
+//
+// 重复的 ID 依次追加 -1、-2 后缀，脚注引用和脚注定义使用的锚点 ID 预先占用，避免和标题 ID 冲突。
+// 使用“自定义标题 ID”语法 {#id} 指定的 ID 不经过 slugger 转换。
+func HeadingIDs(root *ast.Node, slugger *Slugger) (ret map[*ast.Node]string) {
+	return headingIDs(root, slugger, map[string]int{})
+}
+
+// headingIDs 按照 slugger 计算文档 root 中所有标题的 ID，occurrences 记录了已经占用的 ID 及其重复次数，计算后会加入 root 中的 ID。
+func headingIDs(root *ast.Node, slugger *Slugger, occurrences map[string]int) (ret map[*ast.Node]string) {
+	ret = map[*ast.Node]string{}
+	var defs int
+	ast.Walk(root, func(n *ast.Node, entering bool) ast.WalkStatus {
+		if !entering {
+			return ast.WalkContinue
+		}
+
+		switch n.Type {
+		case ast.NodeFootnotesRef:
+			occurrences["footnotes-ref-"+n.FootnotesRefId] = 0
+		case ast.NodeFootnotesDef:
+			defs++
+			occurrences["footnotes-def-"+strconv.Itoa(defs)] = 0
+		}
+		return ast.WalkContinue
+	})
+
+	ast.Walk(root, func(n *ast.Node, entering bool) ast.WalkStatus {
+		if !entering || ast.NodeHeading != n.Type {
+			return ast.WalkContinue
+		}
+
+		var id string
+		if headingID := n.ChildByType(ast.NodeHeadingID); nil != headingID {
+			id = strings.TrimLeft(strings.ReplaceAll(util.BytesToStr(headingID.Tokens), editor.Caret, ""), "#")
+		}
+		if "" == id {
+			id = slugger.Slug(strings.ReplaceAll(tocText(n), editor.Caret, ""))
+		}
+
+		slug := id
+		for _, ok := occurrences[id]; ok; _, ok = occurrences[id] {
+			occurrences[slug]++
+			id = slug + "-" + strconv.Itoa(occurrences[slug])
+		}
+		occurrences[id] = 0
+		ret[n] = id
+		return ast.WalkContinue
+	})
+	return
+}
+
+// HeadingID 返回标题 heading 的 ID。
+//
+// 设置了 Options.HeadingIDSlugger 时按照该策略生成并在文档内去重，否则使用默认规则 HeadingID(heading)。
+// 和默认规则一样，生成的 ID 按照策略名称缓存在标题节点上，之后修改标题内容（比如 TOCOptions.InjectNumbering 插入编号）不会改变 ID。
+// 设置了 HeadingIDOccurs 时还会和其中已经占用的 ID 去重。
+func (r *BaseRenderer) HeadingID(heading *ast.Node) string {
+	slugger := r.Options.HeadingIDSlugger
+	if nil == slugger || nil == slugger.Slug {
+		if nil != r.HeadingIDOccurs && "" == heading.HeadingNormalizedID {
+			headingID0(heading, r.HeadingIDOccurs)
+		}
+		return HeadingID(heading)
+	}
+
+	if id, ok := heading.HeadingSlugIDs[slugger.Name]; ok {
+		return id
+	}
+	root := heading
+	for ; nil != root.Parent; root = root.Parent {
+	}
+	occurrences := r.HeadingIDOccurs
+	if nil == occurrences {
+		occurrences = map[string]int{}
+	}
+	for n, id := range headingIDs(root, slugger, occurrences) {
+		if nil == n.HeadingSlugIDs {
+			n.HeadingSlugIDs = map[string]string{}
+		}
+		n.HeadingSlugIDs[slugger.Name] = id
+	}
+	return heading.HeadingSlugIDs[slugger.Name]
+}
+
+func githubSlug(text string) string {
+	buf := &strings.Builder{}
+	for _, r := range strings.ToLower(strings.TrimSpace(text)) {
+		switch {
+		case ' ' == r || '-' == r:
+			buf.WriteByte('-')
+		case '_' == r || unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsMark(r):
+			buf.WriteRune(r)
+		}
+	}
+	return buf.String()
+}
+
+func gitlabSlug(text string) string {
+	ret := githubSlug(text)
+	for strings.Contains(ret, "--") {
+		ret = strings.ReplaceAll(ret, "--", "-")
+	}
+	return ret
+}
+
+func pandocSlug(text string) string {
+	buf := &strings.Builder{}
+	var letter, space bool
+	for _, r := range strings.ToLower(text) {
+		if !letter {
+			if !unicode.IsLetter(r) {
+				continue
+			}
+			letter = true
+		}
+
+		switch {
+		case unicode.IsSpace(r):
+			space = true
+		case '_' == r || '-' == r || '.' == r || unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsMark(r):
+			if space {
+				buf.WriteByte('-')
+				space = false
+			}
+			buf.WriteRune(r)
+		}
+	}
+	if 0 == buf.Len() {
+		return "section"
+	}
+	return buf.String()
+}
+
+func unicodeSlug(text string) string {
+	buf := &strings.Builder{}
+	var space bool
+	for _, r := range strings.TrimSpace(text) {
+		switch {
+		case unicode.IsSpace(r):
+			space = true
+		case unicode.IsControl(r) || (r < utf8.RuneSelf && '-' != r && '_' != r && '.' != r && !unicode.IsLetter(r) && !unicode.IsDigit(r)):
+		default:
+			if space {
+				buf.WriteByte('-')
+				space = false
+			}
+			buf.WriteRune(r)
+		}
+	}
+	return buf.String()
+}
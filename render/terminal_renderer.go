@@ -0,0 +1,884 @@
+package render
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pafthang/md/ast"
+	"github.com/pafthang/md/lex"
+	"github.com/pafthang/md/parse"
+	"github.com/pafthang/md/util"
+)
+
+// TerminalRenderer 描述了纯文本/终端渲染器。
+//
+// 和 ast.Node.Text() 直接拼接文本不同，TerminalRenderer 会保留文档结构：段落按照 Width 折行，列表输出项目符号和序号，
+// 引述使用竖线缩进，表格使用制表符绘制边框。启用 ANSI 后会使用 ANSI 转义序列渲染加粗、倾斜、代码和链接等样式。
+type TerminalRenderer struct {
+	*BaseRenderer
+
+	// Width 设置折行宽度（按照终端显示列数计算），小于 1 时不折行。
+	Width int
+	// ANSI 设置是否使用 ANSI 转义序列输出样式。
+	ANSI bool
+
+	indents    []*terminalIndent // 缩进栈
+	styles     map[string]int    // 行级样式计数
+	written    bool              // 是否已经输出过块
+	tableRows  [][]string        // 正在渲染的表格单元格内容
+	outWriter  *bytes.Buffer     // 捕获行级内容时被替换的输出缓冲
+	orderedNum []int             // 有序列表当前序号栈
+}
+
+// terminalIndent 描述了一层缩进，first 用于该层第一行（比如列表项目符号），rest 用于后续行。
+type terminalIndent struct {
+	first, rest string
+	pending     bool // first 是否还未使用
+}
+
+// terminalStyleCodes 是行级样式对应的 SGR 参数。
+var terminalStyleCodes = []struct {
+	name, code string
+}{
+	{"bold", "1"}, {"dim", "2"}, {"italic", "3"}, {"underline", "4"}, {"mark", "7"}, {"strike", "9"}, {"code", "36"}, {"link", "34"},
+}
+
+const terminalReset = "\x1b[0m"
+
+// NewTerminalRenderer 创建一个终端渲染器，width 为折行宽度，ansi 设置是否输出 ANSI 样式。
+func NewTerminalRenderer(tree *parse.Tree, options *Options, width int, ansi bool) *TerminalRenderer {
+	ret := &TerminalRenderer{BaseRenderer: NewBaseRenderer(tree, options), Width: width, ANSI: ansi}
+	ret.RendererFuncs[ast.NodeDocument] = ret.renderDocument
+	ret.RendererFuncs[ast.NodeParagraph] = ret.renderParagraph
+	ret.RendererFuncs[ast.NodeText] = ret.renderText
+	ret.RendererFuncs[ast.NodeCodeSpan] = ret.renderCodeSpan
+	ret.RendererFuncs[ast.NodeCodeSpanContent] = ret.renderText
+	ret.RendererFuncs[ast.NodeCodeBlock] = ret.renderCodeBlock
+	ret.RendererFuncs[ast.NodeMathBlock] = ret.renderMathBlock
+	ret.RendererFuncs[ast.NodeInlineMath] = ret.renderInlineMath
+	ret.RendererFuncs[ast.NodeInlineMathContent] = ret.renderText
+	ret.RendererFuncs[ast.NodeEmphasis] = ret.renderEmphasis
+	ret.RendererFuncs[ast.NodeStrong] = ret.renderStrong
+	ret.RendererFuncs[ast.NodeBlockquote] = ret.renderBlockquote
+	ret.RendererFuncs[ast.NodeAdmonition] = ret.renderAdmonition
+	ret.RendererFuncs[ast.NodeHeading] = ret.renderHeading
+	ret.RendererFuncs[ast.NodeList] = ret.renderList
+	ret.RendererFuncs[ast.NodeListItem] = ret.renderListItem
+	ret.RendererFuncs[ast.NodeTaskListItemMarker] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeThematicBreak] = ret.renderThematicBreak
+	ret.RendererFuncs[ast.NodeHardBreak] = ret.renderHardBreak
+	ret.RendererFuncs[ast.NodeSoftBreak] = ret.renderSoftBreak
+	ret.RendererFuncs[ast.NodeBr] = ret.renderHardBreak
+	ret.RendererFuncs[ast.NodeHTMLBlock] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeInlineHTML] = ret.renderInlineHTML
+	ret.RendererFuncs[ast.NodeLink] = ret.renderLink
+	ret.RendererFuncs[ast.NodeImage] = ret.renderImage
+	ret.RendererFuncs[ast.NodeLinkText] = ret.renderLinkText
+	ret.RendererFuncs[ast.NodeHTMLEntity] = ret.renderText
+	ret.RendererFuncs[ast.NodeBackslashContent] = ret.renderText
+	ret.RendererFuncs[ast.NodeStrikethrough] = ret.renderStrikethrough
+	ret.RendererFuncs[ast.NodeTable] = ret.renderTable
+	ret.RendererFuncs[ast.NodeTableCell] = ret.renderTableCell
+	ret.RendererFuncs[ast.NodeEmojiUnicode] = ret.renderText
+	ret.RendererFuncs[ast.NodeEmojiImg] = ret.renderEmojiImg
+	ret.RendererFuncs[ast.NodeFootnotesDefBlock] = ret.renderFootnotesDefBlock
+	ret.RendererFuncs[ast.NodeFootnotesDef] = ret.renderFootnotesDef
+	ret.RendererFuncs[ast.NodeFootnotesRef] = ret.renderFootnotesRef
+	ret.RendererFuncs[ast.NodeToC] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeYamlFrontMatter] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeLinkRefDefBlock] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeBlockRefText] = ret.renderText
+	ret.RendererFuncs[ast.NodeBlockRefDynamicText] = ret.renderText
+	ret.RendererFuncs[ast.NodeFileAnnotationRefText] = ret.renderText
+	ret.RendererFuncs[ast.NodeWikiLink] = ret.renderWikiLink
+	ret.RendererFuncs[ast.NodeWikiLinkEmbed] = ret.renderWikiLink
+	ret.RendererFuncs[ast.NodeMark] = ret.renderMark
+	ret.RendererFuncs[ast.NodeKbd] = ret.renderCodeSpan
+	ret.RendererFuncs[ast.NodeUnderline] = ret.renderUnderline
+	ret.RendererFuncs[ast.NodeTextMark] = ret.renderTextMark
+	ret.RendererFuncs[ast.NodeKramdownBlockIAL] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeKramdownSpanIAL] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeBlockQueryEmbed] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeIFrame] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeAudio] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeVideo] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeWidget] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeAttributeView] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeGitConflict] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeCustomBlock] = ret.renderSkip
+	ret.RendererFuncs[ast.NodeDefinitionList] = ret.renderDefinitionList
+	ret.RendererFuncs[ast.NodeDefinitionTerm] = ret.renderDefinitionTerm
+	ret.RendererFuncs[ast.NodeDefinitionDescription] = ret.renderDefinitionDescription
+	ret.DefaultRendererFunc = ret.renderDefault
+	return ret
+}
+
+// Render 渲染输出文本。
+func (r *TerminalRenderer) Render() (output []byte) {
+	r.indents, r.styles, r.written, r.tableRows, r.orderedNum = nil, map[string]int{}, false, nil, nil
+	return r.BaseRenderer.Render()
+}
+
+func (r *TerminalRenderer) renderDefault(node *ast.Node, entering bool) ast.WalkStatus {
+	return ast.WalkContinue
+}
+
+func (r *TerminalRenderer) renderSkip(node *ast.Node, entering bool) ast.WalkStatus {
+	return ast.WalkSkipChildren
+}
+
+func (r *TerminalRenderer) renderDocument(node *ast.Node, entering bool) ast.WalkStatus {
+	return ast.WalkContinue
+}
+
+// beginInline 开始捕获块节点的行级内容。
+func (r *TerminalRenderer) beginInline() {
+	r.outWriter = r.Writer
+	r.Writer = &bytes.Buffer{}
+}
+
+// endInline 结束捕获并返回捕获到的行级内容。
+func (r *TerminalRenderer) endInline() (ret string) {
+	ret = r.Writer.String()
+	r.Writer = r.outWriter
+	r.outWriter = nil
+	return
+}
+
+// separate 在块节点 node 输出前按需输出空行：容器块的第一个子块（由容器块负责分隔）以及紧凑列表中的块不需要空行。
+func (r *TerminalRenderer) separate(node *ast.Node) {
+	if !r.written {
+		return
+	}
+
+	previous := node.Previous
+	for nil != previous && (ast.NodeKramdownBlockIAL == previous.Type || ast.NodeBlockquoteMarker == previous.Type || ast.NodeLinkRefDefBlock == previous.Type ||
+		ast.NodeYamlFrontMatter == previous.Type || ast.NodeHTMLBlock == previous.Type || ast.NodeToC == previous.Type) {
+		previous = previous.Previous
+	}
+	if nil == previous {
+		return
+	}
+	if ast.NodeListItem == node.Type && node.Parent.ListData.Tight {
+		return
+	}
+	if ast.NodeListItem == node.Parent.Type && node.Parent.Parent.ListData.Tight {
+		return
+	}
+	if ast.NodeDefinitionDescription == node.Type || (ast.NodeDefinitionTerm == node.Type && ast.NodeDefinitionTerm == previous.Type) {
+		return
+	}
+	r.writeLine("", true)
+}
+
+// linePrefix 返回当前行的缩进前缀，blank 为 true 时表示空行，此时不会消耗列表项目符号。
+func (r *TerminalRenderer) linePrefix(blank bool) string {
+	buf := &bytes.Buffer{}
+	for _, indent := range r.indents {
+		if indent.pending && !blank {
+			buf.WriteString(indent.first)
+			indent.pending = false
+		} else {
+			buf.WriteString(indent.rest)
+		}
+	}
+	return buf.String()
+}
+
+// writeLine 输出一行，行首加上当前缩进前缀。
+func (r *TerminalRenderer) writeLine(line string, blank bool) {
+	prefix := r.linePrefix(blank)
+	if blank {
+		prefix = strings.TrimRight(prefix, " ")
+	}
+	r.WriteString(strings.TrimRight(prefix+line, " "))
+	r.WriteByte(lex.ItemNewline)
+	if !blank {
+		r.written = true
+	}
+}
+
+// writeLines 将 text 按照可用宽度折行后输出，wrap 为 false 时只按照换行符分行。
+func (r *TerminalRenderer) writeLines(text string, wrap bool) {
+	width := 0
+	if wrap && 0 < r.Width {
+		width = r.Width - terminalWidth(r.linePrefix(true))
+		if 10 > width {
+			width = 10
+		}
+	}
+
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		lines = append(lines, wrapTerminalLine(line, width)...)
+	}
+	if r.ANSI {
+		lines = carryTerminalStyles(lines)
+	}
+	for _, line := range lines {
+		r.writeLine(line, false)
+	}
+}
+
+// sgr 在启用 ANSI 时使用 SGR 参数 code 对应的样式包裹 text。
+func (r *TerminalRenderer) sgr(name, text string) string {
+	if !r.ANSI || "" == text {
+		return text
+	}
+	for _, style := range terminalStyleCodes {
+		if name == style.name {
+			return "\x1b[" + style.code + "m" + text + terminalReset
+		}
+	}
+	return text
+}
+
+// style 在进入节点时启用行级样式 name，在离开节点时恢复。
+func (r *TerminalRenderer) style(name string, entering bool) {
+	if entering {
+		r.styles[name]++
+	} else {
+		r.styles[name]--
+	}
+	if !r.ANSI {
+		return
+	}
+
+	r.WriteString(terminalReset)
+	var codes []string
+	for _, style := range terminalStyleCodes {
+		if 0 < r.styles[style.name] {
+			codes = append(codes, style.code)
+		}
+	}
+	if 0 < len(codes) {
+		r.WriteString("\x1b[" + strings.Join(codes, ";") + "m")
+	}
+}
+
+func (r *TerminalRenderer) renderParagraph(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.separate(node)
+		r.beginInline()
+	} else {
+		r.writeLines(r.endInline(), true)
+	}
+	return ast.WalkContinue
+}
+
+func (r *TerminalRenderer) renderHeading(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.separate(node)
+		r.beginInline()
+		if !r.ANSI && 2 < node.HeadingLevel {
+			r.WriteString(strings.Repeat("#", node.HeadingLevel) + " ")
+		}
+		r.style("bold", true)
+		if 1 == node.HeadingLevel {
+			r.style("underline", true)
+		}
+		return ast.WalkContinue
+	}
+
+	if 1 == node.HeadingLevel {
+		r.style("underline", false)
+	}
+	r.style("bold", false)
+	text := r.endInline()
+	r.writeLines(text, true)
+	if !r.ANSI && 3 > node.HeadingLevel {
+		underline := "="
+		if 2 == node.HeadingLevel {
+			underline = "-"
+		}
+		r.writeLine(strings.Repeat(underline, terminalWidth(text)), false)
+	}
+	return ast.WalkContinue
+}
+
+func (r *TerminalRenderer) renderText(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString(terminalSanitize(util.BytesToStr(node.Tokens)))
+	}
+	return ast.WalkContinue
+}
+
+func (r *TerminalRenderer) renderCodeSpan(node *ast.Node, entering bool) ast.WalkStatus {
+	if !r.ANSI {
+		r.WriteByte(lex.ItemBacktick)
+	}
+	r.style("code", entering)
+	return ast.WalkContinue
+}
+
+func (r *TerminalRenderer) renderInlineMath(node *ast.Node, entering bool) ast.WalkStatus {
+	if !r.ANSI {
+		r.WriteByte(lex.ItemDollar)
+	}
+	r.style("italic", entering)
+	return ast.WalkContinue
+}
+
+func (r *TerminalRenderer) renderEmphasis(node *ast.Node, entering bool) ast.WalkStatus {
+	r.style("italic", entering)
+	return ast.WalkContinue
+}
+
+func (r *TerminalRenderer) renderStrong(node *ast.Node, entering bool) ast.WalkStatus {
+	r.style("bold", entering)
+	return ast.WalkContinue
+}
+
+func (r *TerminalRenderer) renderStrikethrough(node *ast.Node, entering bool) ast.WalkStatus {
+	r.style("strike", entering)
+	return ast.WalkContinue
+}
+
+func (r *TerminalRenderer) renderMark(node *ast.Node, entering bool) ast.WalkStatus {
+	r.style("mark", entering)
+	return ast.WalkContinue
+}
+
+func (r *TerminalRenderer) renderUnderline(node *ast.Node, entering bool) ast.WalkStatus {
+	r.style("underline", entering)
+	return ast.WalkContinue
+}
+
+func (r *TerminalRenderer) renderHardBreak(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteByte(lex.ItemNewline)
+	}
+	return ast.WalkContinue
+}
+
+func (r *TerminalRenderer) renderSoftBreak(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if 0 < r.Width {
+			r.WriteByte(lex.ItemSpace)
+		} else {
+			r.WriteByte(lex.ItemNewline)
+		}
+	}
+	return ast.WalkContinue
+}
+
+func (r *TerminalRenderer) renderInlineHTML(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering && bytes.HasPrefix(bytes.ToLower(node.Tokens), []byte("<br")) {
+		r.WriteByte(lex.ItemNewline)
+	}
+	return ast.WalkContinue
+}
+
+func (r *TerminalRenderer) renderCodeBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.separate(node)
+		var code string
+		if codeNode := node.ChildByType(ast.NodeCodeBlockCode); nil != codeNode {
+			code = terminalSanitize(strings.TrimSuffix(util.BytesToStr(codeNode.Tokens), "\n"))
+		}
+		r.writeVerbatim(code)
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *TerminalRenderer) renderMathBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.separate(node)
+		if content := node.ChildByType(ast.NodeMathBlockContent); nil != content {
+			r.writeVerbatim(terminalSanitize(strings.TrimSpace(util.BytesToStr(content.Tokens))))
+		}
+	}
+	return ast.WalkSkipChildren
+}
+
+// writeVerbatim 缩进四个空格原样输出 text，不进行折行。
+func (r *TerminalRenderer) writeVerbatim(text string) {
+	for _, line := range strings.Split(text, "\n") {
+		r.writeLine("    "+r.sgr("code", strings.ReplaceAll(line, "\t", "    ")), false)
+	}
+}
+
+func (r *TerminalRenderer) renderThematicBreak(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.separate(node)
+		width := r.Width - terminalWidth(r.linePrefix(true))
+		if 1 > width {
+			width = 3
+		}
+		r.writeLine(r.sgr("dim", strings.Repeat("─", width)), false)
+	}
+	return ast.WalkContinue
+}
+
+func (r *TerminalRenderer) renderBlockquote(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.separate(node)
+		r.indents = append(r.indents, &terminalIndent{first: "│ ", rest: "│ "})
+	} else {
+		r.indents = r.indents[:len(r.indents)-1]
+	}
+	return ast.WalkContinue
+}
+
+func (r *TerminalRenderer) renderAdmonition(node *ast.Node, entering bool) ast.WalkStatus {
+	r.renderBlockquote(node, entering)
+	if entering {
+		r.writeLine(r.sgr("bold", AdmonitionTitle(node)), false)
+	}
+	return ast.WalkContinue
+}
+
+func (r *TerminalRenderer) renderDefinitionList(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.separate(node)
+	}
+	return ast.WalkContinue
+}
+
+func (r *TerminalRenderer) renderDefinitionTerm(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.separate(node)
+		r.beginInline()
+		r.style("bold", true)
+	} else {
+		r.style("bold", false)
+		r.writeLines(r.endInline(), true)
+	}
+	return ast.WalkContinue
+}
+
+func (r *TerminalRenderer) renderDefinitionDescription(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.separate(node)
+		r.indents = append(r.indents, &terminalIndent{first: "    ", rest: "    "})
+		r.beginInline()
+	} else {
+		r.writeLines(r.endInline(), true)
+		r.indents = r.indents[:len(r.indents)-1]
+	}
+	return ast.WalkContinue
+}
+
+// terminalBullets 是无序列表各级的项目符号。
+var terminalBullets = []string{"•", "◦", "▪"}
+
+func (r *TerminalRenderer) renderList(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.separate(node)
+		r.orderedNum = append(r.orderedNum, node.ListData.Start)
+	} else {
+		r.orderedNum = r.orderedNum[:len(r.orderedNum)-1]
+	}
+	return ast.WalkContinue
+}
+
+func (r *TerminalRenderer) renderListItem(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering {
+		if indent := r.indents[len(r.indents)-1]; indent.pending { // 空列表项
+			r.writeLine("", false)
+		}
+		r.indents = r.indents[:len(r.indents)-1]
+		return ast.WalkContinue
+	}
+
+	r.separate(node)
+	list := node.Parent
+	var marker string
+	if 1 == list.ListData.Typ || (3 == list.ListData.Typ && 0 == list.ListData.BulletChar) {
+		num := r.orderedNum[len(r.orderedNum)-1]
+		r.orderedNum[len(r.orderedNum)-1]++
+		delimiter := "."
+		if lex.ItemCloseParen == list.ListData.Delimiter {
+			delimiter = ")"
+		}
+		marker = strconv.Itoa(num) + delimiter
+	} else {
+		depth := 0
+		for p := list.Parent; nil != p; p = p.Parent {
+			if ast.NodeList == p.Type && 0 != p.ListData.BulletChar {
+				depth++
+			}
+		}
+		marker = terminalBullets[depth%len(terminalBullets)]
+	}
+	if 3 == node.ListData.Typ {
+		if node.ListData.Checked {
+			marker += " [x]"
+		} else {
+			marker += " [ ]"
+		}
+	}
+	marker += " "
+	r.indents = append(r.indents, &terminalIndent{first: marker, rest: strings.Repeat(" ", terminalWidth(marker)), pending: true})
+	return ast.WalkContinue
+}
+
+func (r *TerminalRenderer) renderTable(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.separate(node)
+		r.tableRows = nil
+		return ast.WalkContinue
+	}
+
+	cols := len(node.TableAligns)
+	widths := make([]int, cols)
+	for _, row := range r.tableRows {
+		for i, cell := range row {
+			if i < cols {
+				if w := terminalWidth(cell); widths[i] < w {
+					widths[i] = w
+				}
+			}
+		}
+	}
+
+	border := func(left, middle, right string) string {
+		buf := &bytes.Buffer{}
+		buf.WriteString(left)
+		for i, w := range widths {
+			if 0 < i {
+				buf.WriteString(middle)
+			}
+			buf.WriteString(strings.Repeat("─", w+2))
+		}
+		buf.WriteString(right)
+		return r.sgr("dim", buf.String())
+	}
+
+	r.writeLine(border("┌", "┬", "┐"), false)
+	for i, row := range r.tableRows {
+		buf := &bytes.Buffer{}
+		buf.WriteString(r.sgr("dim", "│"))
+		for j := 0; j < cols; j++ {
+			var cell string
+			if j < len(row) {
+				cell = row[j]
+			}
+			padding := widths[j] - terminalWidth(cell)
+			left, right := 0, padding
+			switch node.TableAligns[j] {
+			case 2:
+				left = padding / 2
+				right = padding - left
+			case 3:
+				left, right = padding, 0
+			}
+			if 0 == i {
+				cell = r.sgr("bold", cell)
+			}
+			buf.WriteString(" " + strings.Repeat(" ", left) + cell + strings.Repeat(" ", right) + " ")
+			buf.WriteString(r.sgr("dim", "│"))
+		}
+		r.writeLine(buf.String(), false)
+		if 0 == i && 1 < len(r.tableRows) {
+			r.writeLine(border("├", "┼", "┤"), false)
+		}
+	}
+	r.writeLine(border("└", "┴", "┘"), false)
+	r.tableRows = nil
+	return ast.WalkContinue
+}
+
+func (r *TerminalRenderer) renderTableCell(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if nil == node.Previous {
+			r.tableRows = append(r.tableRows, nil)
+		}
+		r.beginInline()
+	} else {
+		cell := strings.ReplaceAll(r.endInline(), "\n", " ")
+		r.tableRows[len(r.tableRows)-1] = append(r.tableRows[len(r.tableRows)-1], cell)
+	}
+	return ast.WalkContinue
+}
+
+func (r *TerminalRenderer) renderEmojiImg(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		if alias := node.ChildByType(ast.NodeEmojiAlias); nil != alias {
+			r.WriteString(terminalSanitize(util.BytesToStr(alias.Tokens)))
+		}
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *TerminalRenderer) renderLink(node *ast.Node, entering bool) ast.WalkStatus {
+	r.style("link", entering)
+	r.style("underline", entering)
+	if !entering {
+		var dest string
+		if destNode := node.ChildByType(ast.NodeLinkDest); nil != destNode {
+			dest = util.BytesToStr(destNode.Tokens)
+		}
+		if text := node.Text(); "" != dest && text != dest && "mailto:"+text != dest {
+			r.WriteString(" " + r.sgr("dim", "("+terminalSanitize(r.ResolveLinkStr(node, dest))+")"))
+		}
+	}
+	return ast.WalkContinue
+}
+
+func (r *TerminalRenderer) renderLinkText(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString(terminalSanitize(util.BytesToStr(node.Tokens)))
+	}
+	return ast.WalkContinue
+}
+
+func (r *TerminalRenderer) renderImage(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.WriteString("[")
+		r.style("italic", true)
+		r.WriteString("image")
+		if alt := node.Text(); "" != alt {
+			r.WriteString(": " + terminalSanitize(alt))
+		}
+		r.style("italic", false)
+		r.WriteString("]")
+		if destNode := node.ChildByType(ast.NodeLinkDest); nil != destNode && 0 < len(destNode.Tokens) {
+			r.WriteString(" " + r.sgr("dim", "("+terminalSanitize(r.ResolveLinkStr(node, util.BytesToStr(destNode.Tokens)))+")"))
+		}
+	}
+	return ast.WalkSkipChildren
+}
+
+func (r *TerminalRenderer) renderWikiLink(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.style("link", true)
+		r.WriteString(terminalSanitize(node.WikiLinkText()))
+		r.style("link", false)
+	}
+	return ast.WalkSkipChildren
+}
+
+// terminalTextMarkStyles 是 TextMark 类型到行级样式的映射。
+var terminalTextMarkStyles = map[string]string{
+	"strong": "bold", "em": "italic", "s": "strike", "u": "underline", "mark": "mark", "code": "code", "kbd": "code", "a": "link",
+}
+
+func (r *TerminalRenderer) renderTextMark(node *ast.Node, entering bool) ast.WalkStatus {
+	if !entering {
+		return ast.WalkContinue
+	}
+
+	content := node.TextMarkTextContent
+	if node.IsTextMarkType("inline-math") {
+		content = node.TextMarkInlineMathContent
+	}
+	var applied []string
+	for _, typ := range strings.Split(node.TextMarkType, " ") {
+		if style := terminalTextMarkStyles[typ]; "" != style {
+			r.style(style, true)
+			applied = append(applied, style)
+		}
+	}
+	r.WriteString(terminalSanitize(content))
+	for _, style := range applied {
+		r.style(style, false)
+	}
+	if node.IsTextMarkType("a") && "" != node.TextMarkAHref && content != node.TextMarkAHref {
+		r.WriteString(" " + r.sgr("dim", "("+terminalSanitize(r.ResolveLinkStr(node, node.TextMarkAHref))+")"))
+	}
+	return ast.WalkContinue
+}
+
+func (r *TerminalRenderer) renderFootnotesRef(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		idx, _ := r.Tree.FindFootnotesDef(node.Tokens)
+		r.WriteString(r.sgr("dim", "["+strconv.Itoa(idx)+"]"))
+	}
+	return ast.WalkContinue
+}
+
+func (r *TerminalRenderer) renderFootnotesDefBlock(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.separate(node)
+		r.writeLine(r.sgr("dim", "───"), false)
+	}
+	return ast.WalkContinue
+}
+
+func (r *TerminalRenderer) renderFootnotesDef(node *ast.Node, entering bool) ast.WalkStatus {
+	if entering {
+		r.separate(node)
+		idx, _ := r.Tree.FindFootnotesDef(node.Tokens)
+		marker := "[" + strconv.Itoa(idx) + "] "
+		r.indents = append(r.indents, &terminalIndent{first: marker, rest: strings.Repeat(" ", len(marker)), pending: true})
+	} else {
+		r.indents = r.indents[:len(r.indents)-1]
+	}
+	return ast.WalkContinue
+}
+
+// wrapTerminalLine 将 line 按照显示宽度 width 折行，width 小于 1 时不折行。
+// 折行点为空格以及东亚宽字符前后，ANSI 转义序列不计入宽度；超过宽度的单词会被强制断开。
+func wrapTerminalLine(line string, width int) (ret []string) {
+	if 1 > width {
+		return []string{line}
+	}
+
+	cur, word := &strings.Builder{}, &strings.Builder{}
+	var curWidth, wordWidth, spaces int
+	flushWord := func() {
+		if 0 == word.Len() {
+			return
+		}
+		if 0 < curWidth && curWidth+spaces+wordWidth > width {
+			ret = append(ret, cur.String())
+			cur.Reset()
+			curWidth, spaces = 0, 0
+		}
+		if 0 < curWidth {
+			cur.WriteString(strings.Repeat(" ", spaces))
+			curWidth += spaces
+		}
+		spaces = 0
+		text := word.String()
+		for width < curWidth+wordWidth { // 强制断开超长单词
+			head, headWidth := terminalCut(text, width-curWidth)
+			if "" == head {
+				break
+			}
+			cur.WriteString(head)
+			ret = append(ret, cur.String())
+			cur.Reset()
+			text = text[len(head):]
+			wordWidth -= headWidth
+			curWidth = 0
+		}
+		cur.WriteString(text)
+		curWidth += wordWidth
+		word.Reset()
+		wordWidth = 0
+	}
+
+	for i := 0; i < len(line); {
+		if seq := terminalEscape(line[i:]); 0 < len(seq) {
+			word.WriteString(seq)
+			i += len(seq)
+			continue
+		}
+		c, size := utf8.DecodeRuneInString(line[i:])
+		i += size
+		if ' ' == c {
+			flushWord()
+			if 0 < curWidth {
+				spaces++
+			}
+			continue
+		}
+		w := terminalRuneWidth(c)
+		if 2 == w {
+			flushWord()
+			word.WriteRune(c)
+			wordWidth = w
+			flushWord()
+			continue
+		}
+		word.WriteRune(c)
+		wordWidth += w
+	}
+	flushWord()
+	ret = append(ret, cur.String())
+	return
+}
+
+// terminalCut 从 text 开头截取不超过 width 显示宽度的部分，ANSI 转义序列不计入宽度。
+func terminalCut(text string, width int) (head string, headWidth int) {
+	i := 0
+	for i < len(text) {
+		if seq := terminalEscape(text[i:]); 0 < len(seq) {
+			i += len(seq)
+			continue
+		}
+		c, size := utf8.DecodeRuneInString(text[i:])
+		w := terminalRuneWidth(c)
+		if width < headWidth+w {
+			break
+		}
+		headWidth += w
+		i += size
+	}
+	head = text[:i]
+	return
+}
+
+// carryTerminalStyles 在每行结尾重置样式并在下一行开头恢复，避免样式泄漏到缩进前缀。
+func carryTerminalStyles(lines []string) (ret []string) {
+	active := ""
+	for _, line := range lines {
+		current := active + line
+		for i := 0; i < len(line); {
+			if seq := terminalEscape(line[i:]); 0 < len(seq) {
+				if terminalReset == seq {
+					active = ""
+				} else {
+					active += seq
+				}
+				i += len(seq)
+				continue
+			}
+			i++
+		}
+		if "" != active {
+			current += terminalReset
+		}
+		ret = append(ret, current)
+	}
+	return
+}
+
+// terminalSanitize 移除 text 中除换行和制表符以外的 C0、C1 控制字符，非法的 UTF-8 字节替换为 U+FFFD，
+// 避免 Markdown 原文中的转义序列（比如修改窗口标题的 OSC、清屏的 CSI 以及 OSC 8 超链接）被终端执行。
+func terminalSanitize(text string) string {
+	return strings.Map(func(c rune) rune {
+		if '\n' == c || '\t' == c {
+			return c
+		}
+		if 0x20 > c || (0x7F <= c && 0x9F >= c) {
+			return -1
+		}
+		return c
+	}, text)
+}
+
+// terminalEscape 如果 text 以 ANSI CSI 转义序列开头则返回该序列。
+func terminalEscape(text string) string {
+	if !strings.HasPrefix(text, "\x1b[") {
+		return ""
+	}
+	for i := 2; i < len(text); i++ {
+		if c := text[i]; 0x40 <= c && 0x7e >= c {
+			return text[:i+1]
+		}
+	}
+	return ""
+}
+
+// terminalWidth 返回 text 在终端中的显示宽度。
+func terminalWidth(text string) (ret int) {
+	for i := 0; i < len(text); {
+		if seq := terminalEscape(text[i:]); 0 < len(seq) {
+			i += len(seq)
+			continue
+		}
+		c, size := utf8.DecodeRuneInString(text[i:])
+		ret += terminalRuneWidth(c)
+		i += size
+	}
+	return
+}
+
+// terminalRuneWidth 返回字符 c 在终端中的显示宽度：组合字符为 0，东亚宽字符和 Emoji 为 2，其余为 1。
+func terminalRuneWidth(c rune) int {
+	if unicode.Is(unicode.Mn, c) || 0x200B == c || 0xFE0F == c {
+		return 0
+	}
+	if (0x1100 <= c && 0x115F >= c) || (0x2E80 <= c && 0xA4CF >= c && 0x303F != c) || (0xAC00 <= c && 0xD7A3 >= c) ||
+		(0xF900 <= c && 0xFAFF >= c) || (0xFE30 <= c && 0xFE4F >= c) || (0xFF00 <= c && 0xFF60 >= c) || (0xFFE0 <= c && 0xFFE6 >= c) ||
+		(0x1F300 <= c && 0x1F64F >= c) || (0x1F900 <= c && 0x1F9FF >= c) || (0x20000 <= c && 0x3FFFD >= c) {
+		return 2
+	}
+	return 1
+}
@@ -3,14 +3,12 @@ package render
 import (
 	"strings"
 
-	"github.com/pafthang/md/ast"
 	"github.com/pafthang/md/parse"
-	"github.com/pafthang/md/util"
 )
 
 // TextBundleRenderer 描述了 TextBundle 渲染器。https://github.com/pafthang/md/issues/77
 //
-// 继承 FormatRenderer，覆写链接地址渲染函数 renderLinkDest，如果 URL 在指定的链接前缀列表中，则将其替换为 assets/xxx，比如对于 Markdown 原文：
+// 继承 FormatRenderer，通过 LinkResolver 改写链接地址，如果 URL 在指定的链接前缀列表中，则将其替换为 assets/xxx，比如对于 Markdown 原文：
 //
 //	[foo](https://img.hacpai.com/dir1/bar.zip)
 //
@@ -21,17 +19,23 @@ import (
 //	[foo](assets/dir1/bar.zip)
 //
 //	![foo](assets/dir2/baz.png)
+//
+// 渲染选项中设置的 LinkResolver 会先于前缀匹配执行，LinkBase 和 LinkPrefix 不生效。
 type TextBundleRenderer struct {
 	*FormatRenderer
 
-	linkPrefixes []string // 链接前缀列表
-	originalLink []string // 原始链接列表
+	linkPrefixes []string     // 链接前缀列表
+	originalLink []string     // 原始链接列表
+	linkResolver LinkResolver // 渲染选项中设置的链接地址改写回调
 }
 
 // NewTextBundleRenderer 创建一个 TextBundle 渲染器。
 func NewTextBundleRenderer(tree *parse.Tree, linkPrefixes []string, options *Options) *TextBundleRenderer {
-	ret := &TextBundleRenderer{FormatRenderer: NewFormatRenderer(tree, options), linkPrefixes: linkPrefixes}
-	ret.RendererFuncs[ast.NodeLinkDest] = ret.renderLinkDest
+	ret := &TextBundleRenderer{linkPrefixes: linkPrefixes, linkResolver: options.LinkResolver}
+	bundleOptions := *options
+	bundleOptions.LinkBase, bundleOptions.LinkPrefix = "", ""
+	bundleOptions.LinkResolver = ret.resolveLink
+	ret.FormatRenderer = NewFormatRenderer(tree, &bundleOptions)
 	return ret
 }
 
@@ -41,16 +45,22 @@ func (r *TextBundleRenderer) Render() (output []byte, originalLink []string) {
 	return
 }
 
-func (r *TextBundleRenderer) renderLinkDest(node *ast.Node, entering bool) ast.WalkStatus {
-	if entering {
-		dest := util.BytesToStr(node.Tokens)
-		for _, linkPrefix := range r.linkPrefixes {
-			if "" != linkPrefix && strings.HasPrefix(dest, linkPrefix) {
-				r.originalLink = append(r.originalLink, dest)
-				dest = "assets" + dest[len(linkPrefix):]
-			}
+func (r *TextBundleRenderer) resolveLink(kind LinkKind, dest string, tree *parse.Tree) (url string, attrs [][]string) {
+	if nil != r.linkResolver {
+		if url, attrs = r.linkResolver(kind, dest, tree); "" != url {
+			dest = url
+		}
+	}
+	if LinkKindFootnote == kind || LinkKindBlockRef == kind {
+		return
+	}
+
+	for _, linkPrefix := range r.linkPrefixes {
+		if "" != linkPrefix && strings.HasPrefix(dest, linkPrefix) {
+			r.originalLink = append(r.originalLink, dest)
+			dest = "assets" + dest[len(linkPrefix):]
 		}
-		r.WriteString(dest)
 	}
-	return ast.WalkContinue
+	url = dest
+	return
 }
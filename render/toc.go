@@ -0,0 +1,148 @@
+package render
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/pafthang/md/ast"
+	"github.com/pafthang/md/parse"
+)
+
+// TOCOptions 描述了生成目录的选项。
+type TOCOptions struct {
+	// Nested 设置是否包含引述块、超级块、列表项等容器块中的标题，默认仅包含文档顶层的标题。
+	Nested bool
+	// Numbering 设置是否生成层级编号，比如 "2.3.1"。
+	Numbering bool
+	// InjectNumbering 设置是否将层级编号作为前缀插入到标题节点中，之后渲染的标题都会带有编号。开启时隐含 Numbering。
+	// 标题 ID 在插入编号前确定，因此不受编号影响。
+	InjectNumbering bool
+}
+
+// TOCItem 描述了目录项。
+type TOCItem struct {
+	ID          string     `json:"id"`                    // 标题 ID，和渲染 HTML 时使用的 ID 一致
+	Level       int        `json:"level"`                 // 标题级别 1~6
+	Text        string     `json:"text"`                  // 标题纯文本
+	Content     string     `json:"content"`               // 标题 HTML 内容，保留代码、强调等行级格式
+	Number      string     `json:"number,omitempty"`      // 层级编号
+	SourceStart *ast.Pos   `json:"sourceStart,omitempty"` // 在原始输入中的起始位置，需要开启解析选项 SourcePos
+	SourceEnd   *ast.Pos   `json:"sourceEnd,omitempty"`   // 在原始输入中的结束位置，需要开启解析选项 SourcePos
+	Children    []*TOCItem `json:"children,omitempty"`    // 下级目录项
+	Node        *ast.Node  `json:"-"`                     // 标题节点
+}
+
+// TOC 返回语法树 tree 的标题层级结构。
+//
+// 下级标题挂在之前最近的级别更高的标题下，跳级的标题（比如 h1 下直接出现 h3）同样作为下级目录项，编号按照目录层级而不是标题级别生成。
+func TOC(tree *parse.Tree, options *Options, tocOptions *TOCOptions) (ret []*TOCItem) {
+	if nil == tocOptions {
+		tocOptions = &TOCOptions{}
+	}
+
+	var headings []*ast.Node
+	ast.Walk(tree.Root, func(n *ast.Node, entering bool) ast.WalkStatus {
+		if !entering || ast.NodeHeading != n.Type {
+			return ast.WalkContinue
+		}
+		if tocOptions.Nested || tree.Root == n.Parent {
+			headings = append(headings, n)
+		}
+		return ast.WalkSkipChildren
+	})
+
+	r := NewBaseRenderer(tree, options)
+	var stack []*TOCItem
+	for _, heading := range headings {
+		item := &TOCItem{
+			ID:          r.headingAnchorID(heading),
+			Level:       heading.HeadingLevel,
+			Text:        tocText(heading),
+			Content:     headingText(heading),
+			SourceStart: heading.SourceStart,
+			SourceEnd:   heading.SourceEnd,
+			Node:        heading,
+		}
+
+		for 0 < len(stack) && stack[len(stack)-1].Level >= item.Level {
+			stack = stack[:len(stack)-1]
+		}
+		if 0 < len(stack) {
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, item)
+		} else {
+			ret = append(ret, item)
+		}
+		stack = append(stack, item)
+	}
+
+	if tocOptions.Numbering || tocOptions.InjectNumbering {
+		numberTOC(ret, "")
+	}
+	if tocOptions.InjectNumbering {
+		injectTOCNumbering(ret)
+	}
+	return
+}
+
+// headingAnchorID 返回标题 heading 渲染时使用的 ID。
+func (r *BaseRenderer) headingAnchorID(heading *ast.Node) (ret string) {
+	options := r.Options
+	ret = r.HeadingID(heading)
+	if options.EditorWYSIWYG {
+		ret = "wysiwyg-" + ret
+	} else if options.EditorIR {
+		ret = "ir-" + ret
+	}
+
+	if options.KramdownBlockIAL {
+		if id := heading.IALAttr("id"); "" != id {
+			ret = id
+		}
+	}
+	return
+}
+
+func numberTOC(items []*TOCItem, prefix string) {
+	for i, item := range items {
+		item.Number = prefix + strconv.Itoa(i+1)
+		numberTOC(item.Children, item.Number+".")
+	}
+}
+
+func injectTOCNumbering(items []*TOCItem) {
+	for _, item := range items {
+		number := &ast.Node{Type: ast.NodeText, Tokens: []byte(item.Number + " ")}
+		if marker := item.Node.FirstChild; nil != marker && ast.NodeHeadingC8hMarker == marker.Type {
+			marker.InsertAfter(number)
+		} else {
+			item.Node.PrependChild(number)
+		}
+		injectTOCNumbering(item.Children)
+	}
+}
+
+// tocText 返回标题 heading 的纯文本。
+func tocText(heading *ast.Node) string {
+	buf := &bytes.Buffer{}
+	ast.Walk(heading, func(n *ast.Node, entering bool) ast.WalkStatus {
+		if !entering {
+			return ast.WalkContinue
+		}
+
+		switch n.Type {
+		case ast.NodeText, ast.NodeLinkText, ast.NodeBlockRefText, ast.NodeBlockRefDynamicText, ast.NodeFileAnnotationRefText,
+			ast.NodeCodeSpanContent, ast.NodeInlineMathContent, ast.NodeEmojiUnicode:
+			buf.Write(n.Tokens)
+		case ast.NodeTextMark:
+			buf.WriteString(n.TextMarkTextContent)
+		case ast.NodeWikiLink, ast.NodeWikiLinkEmbed:
+			buf.WriteString(n.WikiLinkText())
+			return ast.WalkSkipChildren
+		case ast.NodeHeadingID, ast.NodeKramdownSpanIAL:
+			return ast.WalkSkipChildren
+		}
+		return ast.WalkContinue
+	})
+	return string(bytes.TrimSpace(buf.Bytes()))
+}
@@ -0,0 +1,88 @@
+package render
+
+import (
+	"bytes"
+	"path"
+	"strings"
+
+	"github.com/pafthang/md/ast"
+	"github.com/pafthang/md/lex"
+	"github.com/pafthang/md/util"
+)
+
+// WikiLinkDest 返回维基链接节点 node 的链接地址。
+//
+// 设置了 Options.WikiLinkResolver 时使用它将页面名称解析为链接地址，否则直接使用页面名称作为相对路径（经过 Options.LinkResolver 改写）；
+// 标题部分会作为锚点追加在链接地址后。
+func (r *BaseRenderer) WikiLinkDest(node *ast.Node) string {
+	page, heading, _ := node.WikiLinkParts()
+	var dest string
+	if "" != page {
+		if nil != r.Options.WikiLinkResolver {
+			dest = r.Options.WikiLinkResolver(page)
+		} else {
+			dest = r.ResolveLinkStr(node, r.EncodeLinkSpace(page))
+			dest = util.BytesToStr(r.LinkPath([]byte(dest)))
+		}
+		if nil == r.sanitizeURL(util.StrToBytes(dest)) {
+			dest = ""
+		}
+	}
+	if "" != heading {
+		dest += "#" + r.EncodeLinkSpace(heading)
+	}
+	return dest
+}
+
+// isWikiLinkImage 判断维基链接嵌入节点 node 是否嵌入的是图片。
+func isWikiLinkImage(node *ast.Node) bool {
+	page, _, _ := node.WikiLinkParts()
+	switch strings.ToLower(path.Ext(page)) {
+	case ".png", ".jpg", ".jpeg", ".gif", ".webp", ".svg", ".bmp", ".ico", ".avif":
+		return true
+	}
+	return false
+}
+
+// wikiLinkTarget 返回维基链接节点 node 的目标 Page#Heading。
+func wikiLinkTarget(node *ast.Node) string {
+	page, heading, _ := node.WikiLinkParts()
+	if "" != heading {
+		return page + "#" + heading
+	}
+	return page
+}
+
+// wikiLinkStdMarkdown 返回维基链接节点 node 导出为标准 Markdown 链接 [text](dest) 的文本，链接文本和地址都会被转义。
+func (r *BaseRenderer) wikiLinkStdMarkdown(node *ast.Node) string {
+	text := lex.EscapeMarkers([]byte(node.WikiLinkText()))
+	text = bytes.ReplaceAll(text, []byte("["), []byte("\\["))
+	text = bytes.ReplaceAll(text, []byte("]"), []byte("\\]"))
+	dest := r.WikiLinkDest(node)
+	if strings.ContainsAny(dest, " ()<>\\") {
+		dest = "<" + strings.NewReplacer("\\", "\\\\", "<", "\\<", ">", "\\>").Replace(dest) + ">"
+	}
+	return "[" + util.BytesToStr(text) + "](" + dest + ")"
+}
+
+// wikiLinkMarkdown 返回维基链接节点 node 的 Markdown 文本 [[Page#Heading|Alias]]。
+func wikiLinkMarkdown(node *ast.Node) string {
+	page, heading, alias := node.WikiLinkParts()
+	buf := &strings.Builder{}
+	if ast.NodeWikiLinkEmbed == node.Type {
+		buf.WriteByte(lex.ItemBang)
+	}
+	buf.WriteString("[[")
+	buf.WriteString(page)
+	if "" != heading {
+		buf.WriteString("#" + heading)
+	}
+	if "" != alias {
+		if node.ParentIs(ast.NodeTableCell) {
+			buf.WriteByte(lex.ItemBackslash)
+		}
+		buf.WriteString("|" + alias)
+	}
+	buf.WriteString("]]")
+	return buf.String()
+}
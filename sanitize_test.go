@@ -0,0 +1,154 @@
+package md_test
+
+import (
+	"strings"
+	"testing"
+
+	md "github.com/pafthang/md"
+	"github.com/pafthang/md/parse"
+	"github.com/pafthang/md/render"
+)
+
+var sanitizePolicyTests = []struct {
+	in      string
+	strict  string
+	trusted string
+}{
+	{`<p style="color:red" data-x="1" onclick="x()">a</p>`, `<p>a</p>`, `<p style="color: red" data-x="1">a</p>`},
+	{`<iframe src="https://e.com/v"></iframe>`, ``, `<iframe src="https://e.com/v"></iframe>`},
+	{`<video src="https://e.com/v.mp4" controls></video>`, ``, `<video src="https://e.com/v.mp4" controls=""></video>`},
+	{`<font color="red">f</font>`, `f`, `<font color="red">f</font>`},
+	{`<a href="https://e.com" target="_blank" rel="noopener">a</a>`, `<a href="https://e.com">a</a>`, `<a href="https://e.com" target="_blank" rel="noopener">a</a>`},
+	{`<img src="x.png" width="10" height="10%" loading="lazy">`, `<img src="x.png" width="10" height="10%">`, `<img src="x.png" width="10" height="10%" loading="lazy">`},
+	{`<div class="evil language-go">c</div>`, `<div>c</div>`, `<div class="evil language-go">c</div>`},
+	{`<span class="language-go">c</span>`, `<span class="language-go">c</span>`, `<span class="language-go">c</span>`},
+	{`<input type="text"><input type="checkbox" checked disabled>`, `<input><input type="checkbox" checked="" disabled="">`, `<input><input type="checkbox" checked="" disabled="">`},
+	{`<td align="middle">x</td>`, `<td>x</td>`, `<td>x</td>`},
+	{`<img src="x" srcset="a.png 1x, javascript:alert(1) 2x">`, `<img src="x">`, `<img src="x">`},
+	{`<script>alert(1)</script>b`, `b`, `b`},
+	{`<style>p{}</style>b`, `b`, `b`},
+	{`<form><button>b</button></form>`, `b`, `b`},
+	{`<!-- c -->d`, `d`, `d`},
+}
+
+func TestSanitizePolicy(t *testing.T) {
+	strict, trusted := render.NewStrictUGCPolicy(), render.NewTrustedDocsPolicy()
+	for i, test := range sanitizePolicyTests {
+		if actual := strict.Sanitize(test.in); test.strict != actual {
+			t.Errorf("strict policy test #%d, expected [%s] but got [%s]", i, test.strict, actual)
+		}
+		if actual := trusted.Sanitize(test.in); test.trusted != actual {
+			t.Errorf("trusted policy test #%d, expected [%s] but got [%s]", i, test.trusted, actual)
+		}
+	}
+}
+
+var sanitizeURLTests = []struct {
+	url     string
+	strict  bool
+	trusted bool
+}{
+	{"javascript:alert(1)", false, false},
+	{"JaVaScRiPt:alert(1)", false, false},
+	{" java\tscript:alert(1)", false, false},
+	{"jav&#x09;ascript:alert(1)", false, false},
+	{"&#106;avascript:alert(1)", false, false},
+	{"vbscript:msgbox(1)", false, false},
+	{"data:text/html,<script>alert(1)</script>", false, false},
+	{"data:image/svg+xml;base64,PHN2Zz4=", false, false},
+	{"DATA:image/svg+xml,<svg onload=alert(1)>", false, false},
+	{"data:image/png;base64,iVBORw0=", false, true},
+	{"tel:123", false, true},
+	{"mailto:a@b.c", true, true},
+	{"https://e.com/a", true, true},
+	{"/a/b", true, true},
+	{"#h", true, true},
+	{"a:b/c", false, false},
+}
+
+func TestSanitizePolicyURL(t *testing.T) {
+	strict, trusted := render.NewStrictUGCPolicy(), render.NewTrustedDocsPolicy()
+	for _, test := range sanitizeURLTests {
+		for _, p := range []struct {
+			name    string
+			policy  *render.SanitizePolicy
+			allowed bool
+		}{{"strict", strict, test.strict}, {"trusted", trusted, test.trusted}} {
+			if actual := p.policy.AllowURL(test.url); p.allowed != actual {
+				t.Errorf("%s policy URL [%s], expected allowed %v but got %v", p.name, test.url, p.allowed, actual)
+			}
+
+			// 属性值中的实体由 HTML 分词器解码后再校验
+			attr := strings.ReplaceAll(strings.ReplaceAll(test.url, "<", "&lt;"), ">", "&gt;")
+			out := p.policy.Sanitize(`<a href="` + attr + `">a</a><img src="` + attr + `">`)
+			if allowed := strings.Contains(out, "href="); p.allowed != allowed || allowed != strings.Contains(out, "src=") {
+				t.Errorf("%s policy URL [%s], expected allowed %v but got [%s]", p.name, test.url, p.allowed, out)
+			}
+		}
+	}
+}
+
+const sanitizeMarkdown = "[a](javascript:alert(1)) [b](jav&#x09;ascript:alert(1)) ![i](data:image/svg+xml;base64,PHN2Zz4=) ![p](data:image/png;base64,iVBORw0=) <span onclick=\"x()\" style=\"color:red\">s</span>\n\n<img src=x onerror=alert(1)><script>alert(1)</script>\n"
+
+// checkSanitized 检查 HTML 输出中没有脚本、事件属性和危险的 URL 协议。
+func checkSanitized(t *testing.T, name, out string) {
+	lower := strings.ToLower(out)
+	for _, unsafe := range []string{"javascript:", "ascript:", "<script", "onclick", "onerror", "data:image/svg"} {
+		if strings.Contains(lower, unsafe) {
+			t.Errorf("%s output contains [%s]: [%s]", name, unsafe, out)
+		}
+	}
+}
+
+func TestSanitizePolicyRender(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		policy  *render.SanitizePolicy
+		dataImg bool
+		style   bool
+	}{
+		{"strict", render.NewStrictUGCPolicy(), false, false},
+		{"trusted", render.NewTrustedDocsPolicy(), true, true},
+	} {
+		engine := md.New()
+		engine.SetSanitizePolicy(test.policy)
+
+		htmlStr := engine.MarkdownStr("", sanitizeMarkdown)
+		checkSanitized(t, test.name+" html", htmlStr)
+		if !strings.Contains(htmlStr, `<img src="x">`) {
+			t.Errorf("%s html output lost the sanitized image [%s]", test.name, htmlStr)
+		}
+		if test.dataImg != strings.Contains(htmlStr, `src="data:image/png;base64,iVBORw0="`) {
+			t.Errorf("%s html output data image mismatch [%s]", test.name, htmlStr)
+		}
+		if test.style != strings.Contains(htmlStr, `style="color: red"`) {
+			t.Errorf("%s html output style mismatch [%s]", test.name, htmlStr)
+		}
+
+		tree := parse.Parse("", []byte(sanitizeMarkdown), engine.ParseOptions)
+		preview := engine.ProtylePreview(tree, engine.RenderOptions)
+		checkSanitized(t, test.name+" protyle preview", preview)
+		if test.dataImg != strings.Contains(preview, `src="data:image/png;base64,iVBORw0="`) {
+			t.Errorf("%s protyle preview data image mismatch [%s]", test.name, preview)
+		}
+
+		// 编辑器 DOM 中的 HTML 块保留源码，只检查链接地址
+		dom := engine.Md2BlockDOM("[a](javascript:alert(1)) [b](https://e.com)\n", false)
+		if strings.Contains(dom, "javascript:") || !strings.Contains(dom, `data-href="https://e.com"`) {
+			t.Errorf("%s block DOM link mismatch [%s]", test.name, dom)
+		}
+
+		const h = `<p><a href="javascript:alert(1)" onclick="x()">a</a><img src="data:image/svg+xml,x"><span style="color:red">s</span></p><script>alert(1)</script>`
+		markdown, err := engine.HTML2Markdown(h)
+		if nil != err {
+			t.Fatalf("%s html2md failed: %s", test.name, err)
+		}
+		checkSanitized(t, test.name+" html2md", markdown)
+		if !strings.Contains(markdown, "[a]") || strings.Contains(markdown, "alert") {
+			t.Errorf("%s html2md mismatch [%s]", test.name, markdown)
+		}
+
+		tree = engine.HTML2Tree(h)
+		checkSanitized(t, test.name+" html2tree", engine.Tree2HTML(tree, engine.RenderOptions))
+	}
+}
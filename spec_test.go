@@ -0,0 +1,56 @@
+package md_test
+
+import (
+	"embed"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	md "github.com/pafthang/md"
+)
+
+// testdata/spec 下的规范示例：
+//
+//   - commonmark-0.31.2.json 来自 CommonMark 0.31.2 规范 https://spec.commonmark.org/0.31.2/spec.json
+//   - gfm-extensions-curated.json 是手工整理的 GFM 扩展（表格、任务列表、删除线和自动链接扩展）示例子集，并不是完整的 GFM 规范：
+//     选取了 GFM 规范扩展章节中的示例并补充了一些边界情况，不包含 GFM 规范中和 CommonMark 相同的示例，示例编号也和 GFM 规范不对应
+//
+//go:embed testdata/spec/*.json
+var specFS embed.FS
+
+// specExample 描述了规范中的一个示例。
+type specExample struct {
+	Markdown string `json:"markdown"`
+	HTML     string `json:"html"`
+	Example  int    `json:"example"`
+	Section  string `json:"section"`
+}
+
+const (
+	specCommonMark = "commonmark-0.31.2.json"
+	specGFM        = "gfm-extensions-curated.json"
+)
+
+func loadSpec(tb testing.TB, name string) (ret []*specExample) {
+	data, err := specFS.ReadFile("testdata/spec/" + name)
+	if nil != err {
+		tb.Fatalf("read spec [%s] failed: %s", name, err)
+	}
+	if err = json.Unmarshal(data, &ret); nil != err {
+		tb.Fatalf("unmarshal spec [%s] failed: %s", name, err)
+	}
+	return
+}
+
+// newSpecEngine 创建用于规范一致性测试的引擎：关闭规范之外的扩展和渲染增强，CommonMark 规范还会关闭 GFM 扩展。
+func newSpecEngine(spec string) *md.MD {
+	if specCommonMark == spec {
+		return md.New(md.PresetCommonMark)
+	}
+	return md.New(md.PresetGFM)
+}
+
+// normalizeSpecHTML 规范化 HTML 以便和规范示例比较，目前只统一空元素的自闭合写法，比如 <br /> 和 <br>。
+func normalizeSpecHTML(html string) string {
+	return strings.ReplaceAll(html, " />", ">")
+}
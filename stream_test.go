@@ -0,0 +1,146 @@
+package md_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	md "github.com/pafthang/md"
+	"github.com/pafthang/md/parse"
+	"github.com/pafthang/md/render"
+)
+
+func TestMarkdownTo(t *testing.T) {
+	engine := md.New()
+	headingEngine := md.New()
+	headingEngine.RenderOptions.HeadingID = true
+	sluggerEngine := md.New()
+	sluggerEngine.RenderOptions.HeadingID = true
+	sluggerEngine.RenderOptions.HeadingIDSlugger = render.SluggerGitHub
+	dlEngine := md.New()
+	dlEngine.ParseOptions.DefinitionList = true
+	for _, c := range []struct {
+		engine   *md.MD
+		markdown string
+	}{
+		{engine, "a [foo] b\n\nc\n\n[foo]: /url\n"},
+		{engine, "a[^1]\n\nb\n\n[^1]: note\n"},
+		{engine, "a[^1]\n\n[^1]: x\n\nb[^1]\n\n[^2]: y\n\nc[^2] d[^1]\n"},
+		{engine, "a[^1]\n\n[^1]: x\n\nb [foo]\n\nc[^1]\n\n[foo]: /url\n"},
+		{engine, "see array[0] here\n\n[bar][]\n\n[bar]: /bar \"title\"\n\n[baz]\n"},
+		{engine, "# h\n\n- a\n- b\n\npara\n\n```\n[x]\n\n```\n\n[x]: /x\n"},
+		{engine, "[*foo* bar][]\n\n[*foo* bar]: /url\n"},
+		{engine, "[foo][ref\\[]\n\n[ref\\[]: /uri\n"},
+		{engine, "[ẞ]\n\n[SS]: /url\n"},
+		{headingEngine, "# Dup\n\n# Dup\n\n# Dup\n"},
+		{sluggerEngine, "# Dup\n\n# Dup\n\n## Dup\n"},
+		{dlEngine, "T\n: one\n\nU\n: two\n\nV\n\n: three\n"},
+	} {
+		buf := &bytes.Buffer{}
+		if err := c.engine.MarkdownTo(buf, strings.NewReader(c.markdown)); nil != err {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if expected := c.engine.MarkdownStr("", c.markdown); expected != buf.String() {
+			t.Errorf("stream html mismatch for [%q], expected [%q] but got [%q]", c.markdown, expected, buf.String())
+		}
+	}
+}
+
+func TestMarkdownToToC(t *testing.T) {
+	engine := md.New()
+	engine.RenderOptions.ToC = true
+	buf := &bytes.Buffer{}
+	if err := engine.MarkdownTo(buf, strings.NewReader("[toc]\n\n# h\n")); !errors.Is(err, md.ErrStreamToC) {
+		t.Fatalf("expected ErrStreamToC but got %v", err)
+	}
+	if 0 < buf.Len() {
+		t.Fatalf("expected no output but got [%q]", buf.String())
+	}
+}
+
+// countingReader 记录已经读取的字节数。
+type countingReader struct {
+	reader io.Reader
+	read   int
+}
+
+func (r *countingReader) Read(p []byte) (n int, err error) {
+	n, err = r.reader.Read(p)
+	r.read += n
+	return
+}
+
+// firstWriteWriter 记录第一次写入时 reader 已经读取的字节数。
+type firstWriteWriter struct {
+	reader *countingReader
+	read   int
+}
+
+func (w *firstWriteWriter) Write(p []byte) (int, error) {
+	if 0 == w.read {
+		w.read = w.reader.read
+	}
+	return len(p), nil
+}
+
+func TestMarkdownToPending(t *testing.T) {
+	markdown := strings.Repeat("see array[0] here\n\n", 20*1024)
+	reader := &countingReader{reader: strings.NewReader(markdown)}
+	writer := &firstWriteWriter{reader: reader}
+	if err := md.New().MarkdownTo(writer, reader); nil != err {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if 0 == writer.read || 4*parse.DefaultStreamMaxPendingBytes < writer.read {
+		t.Fatalf("expected output before reading [%d] bytes but got first write after [%d] bytes", 4*parse.DefaultStreamMaxPendingBytes, writer.read)
+	}
+}
+
+func TestMarkdownToLimits(t *testing.T) {
+	engine := md.New()
+	engine.ParseOptions.MaxInputBytes = 8
+	buf := &bytes.Buffer{}
+	err := engine.MarkdownTo(buf, strings.NewReader("# foo\n\nbar *baz*\n\nqux\n"))
+	var limitErr *parse.LimitError
+	if !errors.As(err, &limitErr) || parse.LimitMaxInputBytes != limitErr.Limit {
+		t.Fatalf("expected MaxInputBytes limit error but got %v", err)
+	}
+	if expected := "<h1>foo</h1>\n<p>bar *baz*</p>\n<p>qux</p>\n"; expected != buf.String() {
+		t.Fatalf("degraded html mismatch, expected [%q] but got [%q]", expected, buf.String())
+	}
+}
+
+// TestMarkdownToSpec 逐个规范示例以及将所有示例拼接为一个文档比较流式转换和一次性转换的结果。
+func TestMarkdownToSpec(t *testing.T) {
+	sluggerEngine := md.New()
+	sluggerEngine.RenderOptions.HeadingID = true
+	sluggerEngine.RenderOptions.HeadingIDSlugger = render.SluggerGitHub
+	for _, spec := range []string{specCommonMark, specGFM} {
+		examples := loadSpec(t, spec)
+		all := &strings.Builder{}
+		for _, example := range examples {
+			all.WriteString(example.Markdown)
+			all.WriteString("\n")
+		}
+		for _, engine := range []*md.MD{md.New(), newSpecEngine(spec), sluggerEngine} {
+			for _, example := range examples {
+				buf := &bytes.Buffer{}
+				if err := engine.MarkdownTo(buf, strings.NewReader(example.Markdown)); nil != err {
+					t.Fatalf("unexpected error %v", err)
+				}
+				if expected := engine.MarkdownStr("", example.Markdown); expected != buf.String() {
+					t.Errorf("%s example [%d] %s: stream html mismatch, expected [%q] but got [%q]", spec, example.Example, example.Section, expected, buf.String())
+				}
+			}
+
+			buf := &bytes.Buffer{}
+			if err := engine.MarkdownTo(buf, strings.NewReader(all.String())); nil != err {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if expected := engine.MarkdownStr("", all.String()); expected != buf.String() {
+				t.Errorf("%s: whole spec stream html mismatch", spec)
+			}
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package md_test
+
+import (
+	"strings"
+	"testing"
+
+	md "github.com/pafthang/md"
+)
+
+func TestTerminalControlCharacters(t *testing.T) {
+	engine := md.New()
+	markdown := "a\x1b]0;pwned\x07b\x1b[2J &#27;[31m `c\x1b]8;;http://x\x1b\\d` [e\x9b](http://f/\x1b[2J)\n\n```\ng\x1b[2J\th\n```\n"
+	for _, ansi := range []bool{false, true} {
+		text := string(engine.Markdown2Text("", []byte(markdown), 80, ansi))
+		if strings.Contains(text, "\x07") || strings.Contains(text, "\u009b") || strings.Contains(text, "\x1b]") || strings.Contains(text, "\x1b[2J") || strings.Contains(text, "\x1b[31m") {
+			t.Fatalf("control characters reached the output [%q]", text)
+		}
+		if !strings.Contains(text, "g[2J    h") {
+			t.Fatalf("expected code text to be kept [%q]", text)
+		}
+	}
+}
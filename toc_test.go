@@ -0,0 +1,88 @@
+package md_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	md "github.com/pafthang/md"
+	"github.com/pafthang/md/parse"
+	"github.com/pafthang/md/render"
+)
+
+func TestTOCInjectNumbering(t *testing.T) {
+	for _, slugger := range []*render.Slugger{nil, render.SluggerGitHub} {
+		engine := md.New()
+		engine.SetHeadingID(true)
+		engine.SetHeadingIDSlugger(slugger)
+		tree := parse.Parse("", []byte("# Intro\n\n## Dup\n\n## Dup\n"), engine.ParseOptions)
+		items := engine.TOC(tree, &render.TOCOptions{InjectNumbering: true})
+		html := string(render.NewHtmlRenderer(tree, engine.RenderOptions).Render())
+		ids := []string{items[0].ID, items[0].Children[0].ID, items[0].Children[1].ID}
+		for _, id := range ids {
+			if !strings.Contains(html, `id="`+id+`"`) {
+				t.Fatalf("toc id [%s] not found in [%s]", id, html)
+			}
+		}
+		if !strings.Contains(html, "1.2 Dup") {
+			t.Fatalf("expected injected numbering in [%s]", html)
+		}
+		if nil != slugger && ("intro" != ids[0] || "dup-1" != ids[2]) {
+			t.Fatalf("unexpected toc ids %v", ids)
+		}
+	}
+}
+
+// tocString 返回目录项 items 的缩进文本表示，每行包括编号、级别、ID、纯文本、HTML 内容和起始位置。
+func tocString(items []*render.TOCItem, indent string) (ret string) {
+	for _, item := range items {
+		ret += fmt.Sprintf("%s%s h%d #%s [%s] [%s]", indent, item.Number, item.Level, item.ID, item.Text, item.Content)
+		if nil != item.SourceStart {
+			ret += fmt.Sprintf(" %d:%d-%d:%d", item.SourceStart.Line, item.SourceStart.Column, item.SourceEnd.Line, item.SourceEnd.Column)
+		}
+		ret += "\n" + tocString(item.Children, indent+"  ")
+	}
+	return
+}
+
+func TestTOC(t *testing.T) {
+	const markdown = "# A `<code>` *em*\n\n### Skip\n\n## B [[Page|Alias]] [l](u)\n\n> # Quoted\n\n- ## Item\n\n# C $x$\n\n#### D\n\n## E\n"
+	for _, test := range []struct {
+		options   *render.TOCOptions
+		sourcePos bool
+		expected  string
+	}{
+		{nil, false, "" +
+			" h1 #a-code-em [A <code> em] [A <code>&lt;code&gt;</code> <em>em</em>]\n" +
+			"   h3 #skip [Skip] [Skip]\n" +
+			"   h2 #b-alias-l [B Alias l] [B Alias l]\n" +
+			" h1 #c-x [C x] [C <span class=\"language-math\">x</span>]\n" +
+			"   h4 #d [D] [D]\n" +
+			"   h2 #e [E] [E]\n"},
+		{&render.TOCOptions{Numbering: true}, true, "" +
+			"1 h1 #a-code-em [A <code> em] [A <code>&lt;code&gt;</code> <em>em</em>] 1:1-1:18\n" +
+			"  1.1 h3 #skip [Skip] [Skip] 3:1-3:9\n" +
+			"  1.2 h2 #b-alias-l [B Alias l] [B Alias l] 5:1-5:27\n" +
+			"2 h1 #c-x [C x] [C <span class=\"language-math\">x</span>] 11:1-11:8\n" +
+			"  2.1 h4 #d [D] [D] 13:1-13:7\n" +
+			"  2.2 h2 #e [E] [E] 15:1-15:5\n"},
+		{&render.TOCOptions{Nested: true, Numbering: true}, false, "" +
+			"1 h1 #a-code-em [A <code> em] [A <code>&lt;code&gt;</code> <em>em</em>]\n" +
+			"  1.1 h3 #skip [Skip] [Skip]\n" +
+			"  1.2 h2 #b-alias-l [B Alias l] [B Alias l]\n" +
+			"2 h1 #quoted [Quoted] [Quoted]\n" +
+			"  2.1 h2 #item [Item] [Item]\n" +
+			"3 h1 #c-x [C x] [C <span class=\"language-math\">x</span>]\n" +
+			"  3.1 h4 #d [D] [D]\n" +
+			"  3.2 h2 #e [E] [E]\n"},
+	} {
+		engine := md.New()
+		engine.SetWikiLink(true)
+		engine.SetHeadingIDSlugger(render.SluggerGitHub)
+		engine.ParseOptions.SourcePos = test.sourcePos
+		tree := parse.Parse("", []byte(markdown), engine.ParseOptions)
+		if actual := tocString(engine.TOC(tree, test.options), ""); test.expected != actual {
+			t.Errorf("toc options %+v, expected\n%s\nbut got\n%s", test.options, test.expected, actual)
+		}
+	}
+}
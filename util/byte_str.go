@@ -10,9 +10,12 @@ func BytesToStr(bytes []byte) string {
 	return *(*string)(unsafe.Pointer(&bytes))
 }
 
-// StrToBytes 快速转换 string 为 []byte。
+// StrToBytes 快速转换 string 为 []byte，返回的字节数组和 str 共享内存，不能修改。
+//
+// 不能通过 uintptr 转换底层指针，否则逃逸分析无法跟踪 str，分配在栈上的 str 可能被返回的字节数组引用，导致并发 GC 时崩溃。
 func StrToBytes(str string) []byte {
-	x := (*[2]uintptr)(unsafe.Pointer(&str))
-	h := [3]uintptr{x[0], x[1], x[1]}
-	return *(*[]byte)(unsafe.Pointer(&h))
+	return *(*[]byte)(unsafe.Pointer(&struct {
+		string
+		Cap int
+	}{str, len(str)}))
 }
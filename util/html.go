@@ -81,6 +81,10 @@ func DomHTML(n *html.Node) []byte {
 }
 
 func DomText(n *html.Node) string {
+	if nil == n {
+		return ""
+	}
+
 	buf := &bytes.Buffer{}
 	if html.TextNode == n.Type {
 		buf.WriteString(n.Data)
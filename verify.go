@@ -0,0 +1,255 @@
+package md
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/pafthang/md/ast"
+	"github.com/pafthang/md/parse"
+)
+
+// 格式化校验差异类型。
+const (
+	FormatDiffUnstable = "unstable" // 对格式化结果再次格式化后发生了变化
+	FormatDiffSemantic = "semantic" // 格式化前后的语法树不等价
+)
+
+// FormatDiff 描述了格式化校验发现的一处差异。
+type FormatDiff struct {
+	Kind      string    `json:"kind"`            // 差异类型，FormatDiffUnstable 或者 FormatDiffSemantic
+	Path      string    `json:"path"`            // 语义差异所在节点的路径，比如 Document/List[1]/ListItem[0]/Paragraph[0]
+	Original  string    `json:"original"`        // 原文中的节点摘要，格式不稳定时为第一次格式化结果中的行
+	Formatted string    `json:"formatted"`       // 格式化结果中的节点摘要，格式不稳定时为第二次格式化结果中的行
+	Start     *ast.Pos  `json:"start,omitempty"` // 语义差异在原文中的位置，格式不稳定时为在第一次格式化结果中的位置
+	Node      *ast.Node `json:"-"`               // 原文中出现差异的节点，可能为 nil
+}
+
+// String 返回差异的文本描述。
+func (d *FormatDiff) String() string {
+	ret := d.Kind
+	if nil != d.Start {
+		ret = d.Start.String() + ": " + ret
+	}
+	if "" != d.Path {
+		ret += " " + d.Path
+	}
+	return ret + ": " + strconv.Quote(d.Original) + " -> " + strconv.Quote(d.Formatted)
+}
+
+// VerifyFormat 格式化 markdown 并校验格式化结果：再次格式化时结果不变，并且重新解析后的语法树和原文等价。
+//
+// 比较语法树时忽略标记符风格，比如 * 和 _、ATX 和 Setext 标题、围栏代码块和缩进代码块、行内链接和引用链接，
+// 文本中的空白和软换行视为一个空格。没有差异时 diffs 为空。
+func (md *MD) VerifyFormat(name string, markdown []byte) (formatted []byte, diffs []*FormatDiff) {
+	formatted = md.Format(name, markdown)
+	if again := md.Format(name, formatted); !bytes.Equal(formatted, again) {
+		diffs = append(diffs, unstableDiff(formatted, again))
+	}
+
+	parseOptions := *md.ParseOptions
+	parseOptions.SourcePos = true
+	original := formatSemantics(parse.Parse(name, markdown, &parseOptions).Root)
+	reparsed := formatSemantics(parse.Parse(name, formatted, &parseOptions).Root)
+	diffSemantics(original, reparsed, "Document", &diffs)
+	return
+}
+
+// VerifyFormatStr 接受 string 类型的 markdown 后直接调用 VerifyFormat 进行处理。
+func (md *MD) VerifyFormatStr(name, markdown string) (formatted string, diffs []*FormatDiff) {
+	formattedBytes, diffs := md.VerifyFormat(name, []byte(markdown))
+	return string(formattedBytes), diffs
+}
+
+func unstableDiff(first, second []byte) *FormatDiff {
+	firstLines, secondLines := bytes.Split(first, []byte("\n")), bytes.Split(second, []byte("\n"))
+	i, offset := 0, 0
+	for ; i < len(firstLines) && i < len(secondLines) && bytes.Equal(firstLines[i], secondLines[i]); i++ {
+		offset += len(firstLines[i]) + 1
+	}
+	ret := &FormatDiff{Kind: FormatDiffUnstable, Start: &ast.Pos{Line: i + 1, Column: 1, Offset: offset}}
+	if i < len(firstLines) {
+		ret.Original = string(firstLines[i])
+	}
+	if i < len(secondLines) {
+		ret.Formatted = string(secondLines[i])
+	}
+	return ret
+}
+
+// semanticNode 描述了用于比较格式化前后语义的节点，desc 中只包含和标记符风格无关的信息。
+type semanticNode struct {
+	desc     string
+	text     bool // 是否是合并后的文本
+	node     *ast.Node
+	children []*semanticNode
+}
+
+func formatSemantics(n *ast.Node) *semanticNode {
+	ret := &semanticNode{desc: semanticDesc(n), node: n}
+	if semanticLeaf(n) {
+		return ret
+	}
+
+	var text *semanticNode
+	for c := n.FirstChild; nil != c; c = c.Next {
+		if semanticIgnored(c) {
+			continue
+		}
+
+		switch c.Type {
+		case ast.NodeText, ast.NodeLinkText, ast.NodeSoftBreak:
+			if nil == text {
+				text = &semanticNode{text: true, node: c}
+				ret.children = append(ret.children, text)
+			}
+			if ast.NodeSoftBreak == c.Type {
+				text.desc += " "
+			} else {
+				text.desc += string(c.Tokens)
+			}
+		default:
+			text = nil
+			ret.children = append(ret.children, formatSemantics(c))
+		}
+	}
+
+	children := ret.children[:0]
+	for _, c := range ret.children {
+		if c.text {
+			content := strings.Join(strings.Fields(c.desc), " ")
+			if "" == content {
+				continue
+			}
+			c.desc = "Text " + strconv.Quote(content)
+		} else if ast.NodeParagraph == c.node.Type && 0 == len(c.children) {
+			continue // 只包含空白的段落
+		}
+		children = append(children, c)
+	}
+	ret.children = children
+	return ret
+}
+
+func semanticIgnored(n *ast.Node) bool {
+	switch n.Type {
+	case ast.NodeBang, ast.NodeOpenBracket, ast.NodeCloseBracket, ast.NodeOpenParen, ast.NodeCloseParen, ast.NodeLess, ast.NodeGreater,
+		ast.NodeLinkSpace, ast.NodeLinkDest, ast.NodeLinkTitle, ast.NodeLinkRefDefBlock, ast.NodeHeadingC8hMarker, ast.NodeBlockquoteMarker,
+		ast.NodeCodeBlockFenceOpenMarker, ast.NodeCodeBlockFenceInfoMarker, ast.NodeCodeBlockFenceCloseMarker:
+		return true
+	case ast.NodeTaskListItemMarker:
+		return false
+	}
+	return strings.HasSuffix(n.Type.String(), "Marker")
+}
+
+func semanticLeaf(n *ast.Node) bool {
+	switch n.Type {
+	case ast.NodeCodeBlock, ast.NodeCodeSpan, ast.NodeMathBlock, ast.NodeInlineMath, ast.NodeFootnotesRef:
+		return true
+	}
+	return false
+}
+
+func semanticDesc(n *ast.Node) string {
+	ret := strings.TrimPrefix(n.Type.String(), "Node")
+	switch n.Type {
+	case ast.NodeHeading:
+		ret += " level=" + strconv.Itoa(n.HeadingLevel)
+	case ast.NodeList:
+		switch {
+		case 1 == n.ListData.Typ || (3 == n.ListData.Typ && 0 == n.ListData.BulletChar):
+			ret += " ordered start=" + strconv.Itoa(n.ListData.Start)
+		default:
+			ret += " bullet"
+		}
+		ret += " tight=" + strconv.FormatBool(n.ListData.Tight)
+	case ast.NodeTaskListItemMarker:
+		ret += " checked=" + strconv.FormatBool(n.TaskListItemChecked)
+	case ast.NodeCodeBlock:
+		var info, code []byte
+		if marker := n.ChildByType(ast.NodeCodeBlockFenceInfoMarker); nil != marker {
+			info = marker.CodeBlockInfo
+		}
+		if content := n.ChildByType(ast.NodeCodeBlockCode); nil != content {
+			code = content.Tokens
+		}
+		ret += " info=" + strconv.Quote(string(info)) + " code=" + strconv.Quote(string(code))
+	case ast.NodeCodeSpan:
+		ret += " " + strconv.Quote(semanticContent(n, ast.NodeCodeSpanContent))
+	case ast.NodeInlineMath:
+		ret += " " + strconv.Quote(semanticContent(n, ast.NodeInlineMathContent))
+	case ast.NodeMathBlock:
+		ret += " " + strconv.Quote(strings.TrimSpace(semanticContent(n, ast.NodeMathBlockContent)))
+	case ast.NodeLink, ast.NodeImage:
+		var dest, title []byte
+		if d := n.ChildByType(ast.NodeLinkDest); nil != d {
+			dest = d.Tokens
+		}
+		if t := n.ChildByType(ast.NodeLinkTitle); nil != t {
+			title = t.Tokens
+		}
+		ret += " dest=" + strconv.Quote(string(dest)) + " title=" + strconv.Quote(string(title))
+	case ast.NodeTableCell:
+		ret += " align=" + strconv.Itoa(n.TableCellAlign)
+	case ast.NodeFootnotesRef:
+		ret += " " + strconv.Quote(string(n.Tokens))
+	case ast.NodeFootnotesDef:
+		ret += " " + strconv.Quote(string(n.Tokens))
+	case ast.NodeHTMLBlock:
+		ret += " " + strconv.Quote(strings.TrimSpace(string(n.Tokens))) // HTML 块的缩进不影响渲染结果
+	default:
+		if nil == n.FirstChild && 0 < len(n.Tokens) {
+			ret += " " + strconv.Quote(string(n.Tokens))
+		}
+	}
+	return ret
+}
+
+func semanticContent(n *ast.Node, contentType ast.NodeType) string {
+	if content := n.ChildByType(contentType); nil != content {
+		return string(content.Tokens)
+	}
+	return ""
+}
+
+func diffSemantics(original, formatted *semanticNode, path string, diffs *[]*FormatDiff) {
+	if original.desc != formatted.desc {
+		*diffs = append(*diffs, semanticDiff(path, original, formatted))
+		return
+	}
+
+	i := 0
+	for ; i < len(original.children) && i < len(formatted.children); i++ {
+		child := original.children[i]
+		diffSemantics(child, formatted.children[i], path+"/"+semanticName(child)+"["+strconv.Itoa(i)+"]", diffs)
+	}
+	for j := i; j < len(original.children); j++ {
+		child := original.children[j]
+		*diffs = append(*diffs, semanticDiff(path+"/"+semanticName(child)+"["+strconv.Itoa(j)+"]", child, nil))
+	}
+	for j := i; j < len(formatted.children); j++ {
+		child := formatted.children[j]
+		diff := semanticDiff(path+"/"+semanticName(child)+"["+strconv.Itoa(j)+"]", nil, child)
+		diff.Start, diff.Node = original.node.SourceStart, original.node
+		*diffs = append(*diffs, diff)
+	}
+}
+
+func semanticDiff(path string, original, formatted *semanticNode) (ret *FormatDiff) {
+	ret = &FormatDiff{Kind: FormatDiffSemantic, Path: path}
+	if nil != original {
+		ret.Original, ret.Start, ret.Node = original.desc, original.node.SourceStart, original.node
+	}
+	if nil != formatted {
+		ret.Formatted = formatted.desc
+	}
+	return
+}
+
+func semanticName(n *semanticNode) string {
+	if idx := strings.IndexByte(n.desc, ' '); 0 < idx {
+		return n.desc[:idx]
+	}
+	return n.desc
+}
@@ -0,0 +1,169 @@
+package md_test
+
+import (
+	"strings"
+	"testing"
+
+	md "github.com/pafthang/md"
+	"github.com/pafthang/md/render"
+)
+
+// formatKnownIssues 记录了目前格式化后不稳定或者语义发生变化的规范示例编号，修复后需要从这里移除。
+var formatKnownIssues = map[string][]int{
+	specCommonMark: {11, 16, 17, 20, 43, 47, 49, 50, 51, 52, 53, 54, 61, 70, 77, 87, 134, 200, 226, 238, 254, 273, 274, 286, 287, 288, 290,
+		300, 306, 311, 312, 313, 314, 315, 321, 324, 330, 331, 346, 492, 495, 498, 499, 500, 526, 538, 603, 612, 633, 634, 635, 636, 637, 638, 639},
+	specGFM: {12, 13, 14, 27, 31, 32, 33},
+}
+
+func formatKnownIssue(spec string, example int) bool {
+	for _, num := range formatKnownIssues[spec] {
+		if num == example {
+			return true
+		}
+	}
+	return false
+}
+
+func TestVerifyFormat(t *testing.T) {
+	engine := md.New()
+	formatted, diffs := engine.VerifyFormatStr("", "Title\n===\n\n* foo\n* _bar_\n\ntext\n\n    code\n")
+	if 0 < len(diffs) {
+		t.Fatalf("unexpected diffs %v", diffs)
+	}
+	if expected := "Title\n=====\n\n* foo\n* _bar_\n\ntext\n\n```\ncode\n```\n"; expected != formatted {
+		t.Fatalf("formatted mismatch, expected [%q] but got [%q]", expected, formatted)
+	}
+
+	// 默认选项下硬换行会被格式化为软换行
+	_, diffs = engine.VerifyFormatStr("", "foo\\\nbar\n")
+	if 0 == len(diffs) {
+		t.Fatalf("expected diffs")
+	}
+	diff := diffs[0]
+	if md.FormatDiffSemantic != diff.Kind || "Document/Paragraph[0]/Text[0]" != diff.Path || `Text "foo"` != diff.Original || `Text "foo bar"` != diff.Formatted {
+		t.Fatalf("unexpected diff %s", diff)
+	}
+	if nil == diff.Start || 1 != diff.Start.Line || 1 != diff.Start.Column {
+		t.Fatalf("unexpected diff position %v", diff.Start)
+	}
+}
+
+func TestVerifyFormatStyle(t *testing.T) {
+	engine := md.New()
+	engine.SetSoftBreak2HardBreak(false)
+	engine.SetFormatStyle(&render.FormatStyle{BulletChar: '-', EmphasisChar: '*', HeadingStyle: render.HeadingStyleATX, FenceChar: '~',
+		OrderedListNumbering: render.OrderedListOne, HardWrap: 20, LinkStyle: render.LinkStyleReference, LinkRefLabel: render.LinkRefLabelText})
+	markdown := "Title\n===\n\n* foo\n* _bar_\n\n+ baz\n\n1. one\n2. two\n\nA long paragraph with [a link](https://example.com \"Example\") and __strong__ text.\n"
+	formatted, diffs := engine.VerifyFormatStr("", markdown)
+	if 0 < len(diffs) {
+		t.Fatalf("unexpected diffs %v\n%s", diffs, formatted)
+	}
+}
+
+func TestFormatHardWrap(t *testing.T) {
+	engine := md.New()
+	engine.SetFormatStyle(&render.FormatStyle{HardWrap: 10})
+	markdown := "aaa bbb ccc ddd\n"
+	if formatted := engine.FormatStr("", markdown); markdown != formatted {
+		t.Fatalf("expected no wrapping with SoftBreak2HardBreak, got [%q]", formatted)
+	}
+
+	engine.SetSoftBreak2HardBreak(false)
+	if formatted := engine.FormatStr("", markdown); "aaa bbb\nccc ddd\n" != formatted {
+		t.Fatalf("unexpected wrapping [%q]", formatted)
+	}
+}
+
+func TestFormatLinkStyleReference(t *testing.T) {
+	for _, label := range []render.LinkRefLabel{render.LinkRefLabelNumber, render.LinkRefLabelText} {
+		engine := md.New()
+		engine.SetFormatStyle(&render.FormatStyle{LinkStyle: render.LinkStyleReference, LinkRefLabel: label})
+		for _, markdown := range []string{
+			"Read arr[1] first, then [docs](/u).\n",
+			"[ [brackets] ](/b)\n",
+			"a <http://auto> b https://www.example.com\n",
+			"[x]: /y\n\n[a](/a) [x] [c][] [d](/d)\n\n[c]: /c\n",
+		} {
+			formatted, diffs := engine.VerifyFormatStr("", markdown)
+			if 0 < len(diffs) {
+				t.Errorf("unexpected diffs for [%q]: %v\nformatted:\n%s", markdown, diffs, formatted)
+			}
+			if again := engine.FormatStr("", formatted); formatted != again {
+				t.Errorf("format is not idempotent for [%q], expected [%q] but got [%q]", markdown, formatted, again)
+			}
+		}
+	}
+}
+
+func TestVerifyFormatSpec(t *testing.T) {
+	engine := md.New()
+	for _, spec := range []string{specCommonMark, specGFM} {
+		for _, example := range loadSpec(t, spec) {
+			if formatKnownIssue(spec, example.Example) {
+				continue
+			}
+			if formatted, diffs := engine.VerifyFormatStr("", example.Markdown); 0 < len(diffs) {
+				t.Errorf("%s example [%d] %s: %v\nmarkdown:\n%s\nformatted:\n%s", spec, example.Example, example.Section, diffs, example.Markdown, formatted)
+			}
+		}
+	}
+}
+
+func TestVerifyFormatSpecLinkStyle(t *testing.T) {
+	for _, style := range []render.LinkStyle{render.LinkStyleInline, render.LinkStyleReference} {
+		engine := md.New()
+		engine.SetFormatStyle(&render.FormatStyle{LinkStyle: style})
+		for _, spec := range []string{specCommonMark, specGFM} {
+			for _, example := range loadSpec(t, spec) {
+				if formatKnownIssue(spec, example.Example) {
+					continue
+				}
+				if formatted, diffs := engine.VerifyFormatStr("", example.Markdown); 0 < len(diffs) {
+					t.Errorf("link style [%d] %s example [%d] %s: %v\nmarkdown:\n%s\nformatted:\n%s", style, spec, example.Example, example.Section, diffs, example.Markdown, formatted)
+				}
+			}
+		}
+	}
+}
+
+func TestFormatLinkDest(t *testing.T) {
+	engine := md.New()
+	engine.SetFormatStyle(&render.FormatStyle{LinkStyle: render.LinkStyleInline})
+	for _, c := range []struct{ markdown, formatted string }{
+		{"[Foo]\n\n[Foo]: my_(url) 'title (p)'\n", "[Foo](<my_(url)> \"title (p)\")\n"},
+		{"[a](b(c))\n", "[a](b(c))\n"},
+		{"[a](b\\)c)\n", "[a](<b)c>)\n"},
+		{"[a](<>)\n", "[a](<>)\n"},
+		{"[a](b\\\\\\*)\n", "[a](b%5C*)\n"},
+		{"- a\n- b\n\n  [ref]: /url\n- d\n", "- a\n- b\n\n  [ref]: /url\n- d\n"},
+	} {
+		formatted, diffs := engine.VerifyFormatStr("", c.markdown)
+		if 0 < len(diffs) {
+			t.Errorf("unexpected diffs for [%q]: %v", c.markdown, diffs)
+		}
+		if c.formatted != formatted {
+			t.Errorf("format mismatch for [%q], expected [%q] but got [%q]", c.markdown, c.formatted, formatted)
+		}
+	}
+}
+
+func FuzzVerifyFormat(f *testing.F) {
+	for _, spec := range []string{specCommonMark, specGFM} {
+		for _, example := range loadSpec(f, spec) {
+			if !formatKnownIssue(spec, example.Example) {
+				f.Add(example.Markdown)
+			}
+		}
+	}
+
+	engine := md.New()
+	f.Fuzz(func(t *testing.T, markdown string) {
+		if formatted, diffs := engine.VerifyFormatStr("", markdown); 0 < len(diffs) {
+			var lines []string
+			for _, diff := range diffs {
+				lines = append(lines, diff.String())
+			}
+			t.Errorf("verify format failed:\n%s\nmarkdown:\n%q\nformatted:\n%q", strings.Join(lines, "\n"), markdown, formatted)
+		}
+	})
+}